@@ -343,7 +343,7 @@ type ClusterList struct {
 	fs         *pflag.FlagSet
 	filter     clusterFilter
 	simplified bool
-	json       bool
+	output     formatter.Output
 }
 
 func (c *ClusterList) Info() *cmd.Info {
@@ -357,11 +357,10 @@ func (c *ClusterList) Flags() *pflag.FlagSet {
 	if c.fs == nil {
 		c.fs = pflag.NewFlagSet("cluster-list", pflag.ExitOnError)
 		c.fs.StringVarP(&c.filter.name, standards.FlagName, standards.ShortFlagName, "", "Filter clusters by name")
-		c.fs.StringVarP(&c.filter.pool, standards.FlagPool, standards.ShortFlagPool, "", "Filter clusters by pool")
+		c.fs.StringVar(&c.filter.pool, standards.FlagPool, "", "Filter clusters by pool"+formatter.MovedShorthandNote)
 
 		c.fs.BoolVarP(&c.simplified, standards.FlagOnlyName, standards.ShortFlagOnlyName, false, "Display only clusters name")
-		c.fs.BoolVar(&c.json, standards.FlagJSON, false, "Display in JSON format")
-
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -392,13 +391,36 @@ func (c *ClusterList) Run(ctx *cmd.Context) error {
 		return nil
 	}
 
-	if c.json {
-		return formatter.JSON(ctx.Stdout, clusters)
-	}
-
+	if !c.output.IsTable() {
+		return c.output.Print(ctx.Stdout, formatter.Printable{
+			Data: clusters,
+			Names: func() []string {
+				names := make([]string, len(clusters))
+				for i, cluster := range clusters {
+					names[i] = cluster.Name
+				}
+				return names
+			},
+			Rows: func() ([]string, [][]string) {
+				rows := make([][]string, len(clusters))
+				for i, cluster := range clusters {
+					rows[i] = []string{
+						cluster.Name,
+						cluster.Provisioner,
+						strings.Join(cluster.Addresses, " "),
+						strconv.FormatBool(cluster.Default),
+						strings.Join(cluster.Pools, " "),
+					}
+				}
+				return []string{"name", "provisioner", "addresses", "default", "pools"}, rows
+			},
+		})
+	}
+
+	compact := tablecli.TableConfig.UseTabWriter && !c.output.Wide()
 	tbl := tablecli.NewTable()
 	tbl.LineSeparator = true
-	if tablecli.TableConfig.UseTabWriter {
+	if compact {
 		tbl.Headers = tablecli.Row{"Name", "Provisioner", "Addresses", "Default"}
 	} else {
 		tbl.Headers = tablecli.Row{"Name", "Provisioner", "Addresses", "Custom Data", "Default", "Pools"}
@@ -409,7 +431,7 @@ func (c *ClusterList) Run(ctx *cmd.Context) error {
 			custom = append(custom, fmt.Sprintf("%s=%s", k, v))
 		}
 		addresses := strings.Join(c.Addresses, "\n")
-		if tablecli.TableConfig.UseTabWriter {
+		if compact {
 			tbl.AddRow(tablecli.Row{c.Name, c.Provisioner, addresses, strconv.FormatBool(c.Default)})
 			continue
 		}
@@ -590,14 +612,14 @@ func hasPool(pools []string, pool string) (int, bool) {
 }
 
 type ClusterInfo struct {
-	fs   *pflag.FlagSet
-	json bool
+	fs     *pflag.FlagSet
+	output formatter.Output
 }
 
 func (c *ClusterInfo) Flags() *pflag.FlagSet {
 	if c.fs == nil {
 		c.fs = pflag.NewFlagSet("cluster-info", pflag.ExitOnError)
-		c.fs.BoolVar(&c.json, standards.FlagJSON, false, "Display in JSON format")
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -627,8 +649,11 @@ func (c *ClusterInfo) Run(ctx *cmd.Context) error {
 	}
 	defer resp.Body.Close()
 
-	if c.json {
-		return formatter.JSON(ctx.Stdout, cluster)
+	if !c.output.IsTable() {
+		return c.output.Print(ctx.Stdout, formatter.Printable{
+			Data:  cluster,
+			Names: func() []string { return []string{cluster.Name} },
+		})
 	}
 
 	tabWriter := tabwriter.NewWriter(ctx.Stdout, 0, 0, 2, ' ', 0)
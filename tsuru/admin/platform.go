@@ -30,7 +30,7 @@ import (
 type PlatformList struct {
 	fs         *pflag.FlagSet
 	simplified bool
-	json       bool
+	output     formatter.Output
 }
 
 func (p *PlatformList) Run(context *cmd.Context) error {
@@ -67,21 +67,33 @@ func (p *PlatformList) Run(context *cmd.Context) error {
 		return nil
 	}
 
-	if p.json {
-		return formatter.JSON(context.Stdout, platforms)
+	if !p.output.IsTable() {
+		return p.output.Print(context.Stdout, formatter.Printable{
+			Data: platforms,
+			Names: func() []string {
+				names := make([]string, len(platforms))
+				for i, p := range platforms {
+					names[i] = p.Name
+				}
+				return names
+			},
+			Rows: func() ([]string, [][]string) {
+				rows := make([][]string, len(platforms))
+				for i, p := range platforms {
+					rows[i] = []string{p.Name, platformStatus(p)}
+				}
+				return []string{"name", "status"}, rows
+			},
+		})
 	}
 
 	tbl := tablecli.NewTable()
 	tbl.Headers = tablecli.Row{"Name", "Status"}
 	tbl.LineSeparator = false
 	for _, p := range platforms {
-		status := "enabled"
-		if p.Disabled {
-			status = "disabled"
-		}
 		tbl.AddRow(tablecli.Row{
 			p.Name,
-			status,
+			platformStatus(p),
 		})
 	}
 	fmt.Fprint(context.Stdout, tbl.String())
@@ -89,11 +101,18 @@ func (p *PlatformList) Run(context *cmd.Context) error {
 	return nil
 }
 
+func platformStatus(p appTypes.Platform) string {
+	if p.Disabled {
+		return "disabled"
+	}
+	return "enabled"
+}
+
 func (c *PlatformList) Flags() *pflag.FlagSet {
 	if c.fs == nil {
 		c.fs = pflag.NewFlagSet("platform-list", pflag.ExitOnError)
 		c.fs.BoolVarP(&c.simplified, standards.FlagOnlyName, standards.ShortFlagOnlyName, false, "Display only platform name")
-		c.fs.BoolVar(&c.json, standards.FlagJSON, false, "Display in JSON format")
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -289,14 +308,14 @@ func (p *PlatformRemove) Run(context *cmd.Context) error {
 }
 
 type PlatformInfo struct {
-	fs   *pflag.FlagSet
-	json bool
+	fs     *pflag.FlagSet
+	output formatter.Output
 }
 
 func (c *PlatformInfo) Flags() *pflag.FlagSet {
 	if c.fs == nil {
 		c.fs = pflag.NewFlagSet("platform-info", pflag.ExitOnError)
-		c.fs.BoolVar(&c.json, standards.FlagJSON, false, "Display platform in JSON Format")
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -326,8 +345,11 @@ func (c PlatformInfo) Run(ctx *cmd.Context) error {
 	}
 	defer resp.Body.Close()
 
-	if c.json {
-		return formatter.JSON(ctx.Stdout, info)
+	if !c.output.IsTable() {
+		return c.output.Print(ctx.Stdout, formatter.Printable{
+			Data:  info,
+			Names: func() []string { return []string{info.Platform.Name} },
+		})
 	}
 
 	var status string
@@ -17,7 +17,6 @@ import (
 	"github.com/tsuru/go-tsuruclient/pkg/config"
 	"github.com/tsuru/tsuru-client/tsuru/app"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
-	"github.com/tsuru/tsuru-client/tsuru/cmd/standards"
 	"github.com/tsuru/tsuru-client/tsuru/formatter"
 	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
 	"github.com/tsuru/tsuru/types/quota"
@@ -94,7 +93,7 @@ type AppQuotaView struct {
 	app.AppNameMixIn
 
 	flagsApplied bool
-	json         bool
+	output       formatter.Output
 }
 
 func (*AppQuotaView) Info() *cmd.Info {
@@ -109,7 +108,7 @@ func (*AppQuotaView) Info() *cmd.Info {
 func (c *AppQuotaView) Flags() *pflag.FlagSet {
 	fs := c.AppNameMixIn.Flags()
 	if !c.flagsApplied {
-		fs.BoolVar(&c.json, standards.FlagJSON, false, "Show JSON")
+		c.output.AddFlags(fs)
 
 		c.flagsApplied = true
 	}
@@ -138,8 +137,8 @@ func (c *AppQuotaView) Run(context *cmd.Context) error {
 		return err
 	}
 
-	if c.json {
-		return formatter.JSON(context.Stdout, quota)
+	if !c.output.IsTable() {
+		return c.output.Print(context.Stdout, formatter.Printable{Data: quota})
 	}
 
 	fmt.Fprintf(context.Stdout, "App: %s\n", appName)
@@ -27,12 +27,14 @@ func (s *S) SetUpSuite(c *check.C) {
 	//var stdout, stderr bytes.Buffer
 	//s.manager = cmd.NewManagerPanicExiter("glb", "1.0.0", "Supported-Tsuru-Version", &stdout, &stderr, os.Stdin, nil)
 	os.Setenv("TSURU_TARGET", "http://localhost")
+	os.Setenv("TSURU_HTTP_RETRIES", "0")
 	form.DefaultEncoder = form.DefaultEncoder.UseJSONTags(false)
 	form.DefaultDecoder = form.DefaultDecoder.UseJSONTags(false)
 }
 
 func (s *S) TearDownSuite(c *check.C) {
 	os.Unsetenv("TSURU_TARGET")
+	os.Unsetenv("TSURU_HTTP_RETRIES")
 }
 
 func (s *S) SetUpTest(c *check.C) {
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
 	"github.com/tsuru/tsuru-client/tsuru/cmd/standards"
+	"github.com/tsuru/tsuru-client/tsuru/project"
 )
 
 var (
@@ -38,11 +39,20 @@ func (cmd *AppNameMixIn) AppNameByArgsAndFlag(args []string) (string, error) {
 	return cmd.AppNameByFlag()
 }
 
+// AppNameByFlag returns the app set in the --app flag or, when the flag is
+// not given, the one in the project context file.
 func (cmd *AppNameMixIn) AppNameByFlag() (string, error) {
-	if cmd.appName == "" {
+	if cmd.appName != "" {
+		return cmd.appName, nil
+	}
+	projectCtx, err := project.Current()
+	if err != nil {
+		return "", err
+	}
+	if projectCtx == nil || projectCtx.App == "" {
 		return "", ErrAppNameRequired
 	}
-	return cmd.appName, nil
+	return projectCtx.App, nil
 }
 
 func (cmd *AppNameMixIn) Flags() *pflag.FlagSet {
@@ -5,6 +5,8 @@
 package app
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/spf13/pflag"
@@ -69,6 +71,27 @@ Use the --app flag to specify it.
 `)
 }
 
+func (s *S) TestAppNameMixInWithProjectContext(c *check.C) {
+	wd, err := os.Getwd()
+	c.Assert(err, check.IsNil)
+	defer os.Chdir(wd)
+	root := c.MkDir()
+	c.Assert(os.WriteFile(filepath.Join(root, ".tsuru.yaml"), []byte("app: contextapp\n"), 0600), check.IsNil)
+	c.Assert(os.Chdir(root), check.IsNil)
+	g := AppNameMixIn{}
+	g.Flags().Parse([]string{})
+	name, err := g.AppNameByFlag()
+	c.Assert(err, check.IsNil)
+	c.Assert(name, check.Equals, "contextapp")
+	name, err = g.AppNameByArgsAndFlag([]string{"myapp"})
+	c.Assert(err, check.IsNil)
+	c.Assert(name, check.Equals, "myapp")
+	g.Flags().Parse([]string{"-a", "myapp"})
+	name, err = g.AppNameByFlag()
+	c.Assert(err, check.IsNil)
+	c.Assert(name, check.Equals, "myapp")
+}
+
 func (s *S) TestAppNameMixInFlags(c *check.C) {
 	var flags []pflag.Flag
 	expected := []pflag.Flag{*appflag}
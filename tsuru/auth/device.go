@@ -0,0 +1,58 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	stdContext "context"
+	"errors"
+	"fmt"
+
+	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	"golang.org/x/oauth2"
+)
+
+var errDeviceAuthURLMissing = errors.New("the authorization server does not publish a device authorization endpoint, use --device-auth-url to set it")
+
+// deviceLogin logs in with the OAuth 2.0 Device Authorization Grant (RFC
+// 8628), for machines where no browser can reach a callback server on
+// localhost. The user opens the verification URL on any device and enters the
+// code displayed, while the client polls the token endpoint.
+func deviceLogin(ctx *cmd.Context, loginInfo *authTypes.SchemeInfo, deviceAuthURL, key string) error {
+	if deviceAuthURL == "" {
+		return errDeviceAuthURLMissing
+	}
+
+	fmt.Fprintln(ctx.Stderr, "Starting OIDC device login")
+
+	oauth2Config := oauth2.Config{
+		ClientID: loginInfo.Data.ClientID,
+		Scopes:   loginInfo.Data.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:       loginInfo.Data.AuthURL,
+			TokenURL:      loginInfo.Data.TokenURL,
+			DeviceAuthURL: deviceAuthURL,
+		},
+	}
+
+	authCtx := stdContext.Background()
+	deviceAuth, err := oauth2Config.DeviceAuth(authCtx)
+	if err != nil {
+		return fmt.Errorf("unable to start the device authorization: %w", err)
+	}
+
+	fmt.Fprintf(ctx.Stdout, "Open the following URL in a browser, on this or any other device:\n\n    %s\n\n", deviceAuth.VerificationURI)
+	fmt.Fprintf(ctx.Stdout, "And enter the code: %s\n", deviceAuth.UserCode)
+	if deviceAuth.VerificationURIComplete != "" {
+		fmt.Fprintf(ctx.Stdout, "\nOr open the URL with the code already filled in:\n\n    %s\n", deviceAuth.VerificationURIComplete)
+	}
+	fmt.Fprintln(ctx.Stderr, "\nWaiting for the authorization...")
+
+	t, err := oauth2Config.DeviceAccessToken(authCtx, deviceAuth)
+	if err != nil {
+		return fmt.Errorf("device login failed: %w", err)
+	}
+	return storeOIDCSession(ctx, key, t, &oauth2Config)
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+
+	"github.com/tsuru/go-tsuruclient/pkg/config"
+	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
+	"github.com/tsuru/tsuru/fs/fstest"
+	"github.com/tsuru/tsuru/types/auth"
+	"gopkg.in/check.v1"
+)
+
+func (s *S) TestDeviceLogin(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+
+	var polls int32
+	fakeIDP := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		b, err := io.ReadAll(req.Body)
+		c.Assert(err, check.IsNil)
+		body, err := url.ParseQuery(string(b))
+		c.Assert(err, check.IsNil)
+		c.Assert(body.Get("client_id"), check.Equals, "test-tsuru")
+		rw.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/device":
+			c.Assert(body.Get("scope"), check.Equals, "scope1")
+			rw.Write([]byte(`{"device_code":"dev123","user_code":"ABCD-EFGH","verification_uri":"https://idp.example.com/device","verification_uri_complete":"https://idp.example.com/device?user_code=ABCD-EFGH","expires_in":60,"interval":1}`))
+		case "/token":
+			c.Assert(body.Get("grant_type"), check.Equals, "urn:ietf:params:oauth:grant-type:device_code")
+			c.Assert(body.Get("device_code"), check.Equals, "dev123")
+			if atomic.AddInt32(&polls, 1) == 1 {
+				rw.WriteHeader(http.StatusBadRequest)
+				rw.Write([]byte(`{"error":"authorization_pending"}`))
+				return
+			}
+			rw.Write([]byte(`{"access_token":"mytoken","refresh_token":"refreshtoken","token_type":"bearer","expires_in":3600}`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer fakeIDP.Close()
+
+	context := &cmd.Context{
+		Stdout: &bytes.Buffer{},
+		Stderr: &bytes.Buffer{},
+	}
+	err := deviceLogin(context, &auth.SchemeInfo{
+		Data: auth.SchemeData{
+			TokenURL: fakeIDP.URL + "/token",
+			ClientID: "test-tsuru",
+			Scopes:   []string{"scope1"},
+		},
+	}, fakeIDP.URL+"/device", "test")
+	c.Assert(err, check.IsNil)
+	c.Assert(atomic.LoadInt32(&polls), check.Equals, int32(2))
+
+	stdout := context.Stdout.(*bytes.Buffer).String()
+	c.Assert(strings.Contains(stdout, "https://idp.example.com/device\n"), check.Equals, true)
+	c.Assert(strings.Contains(stdout, "And enter the code: ABCD-EFGH"), check.Equals, true)
+	c.Assert(strings.Contains(stdout, "https://idp.example.com/device?user_code=ABCD-EFGH"), check.Equals, true)
+	c.Assert(strings.Contains(context.Stderr.(*bytes.Buffer).String(), "The OIDC token will expire in"), check.Equals, true)
+
+	tokenV1, err := credentials.ReadTokenV1("test")
+	c.Assert(err, check.IsNil)
+	c.Assert(tokenV1, check.Equals, "mytoken")
+	tokenV2, err := credentials.ReadTokenV2("test")
+	c.Assert(err, check.IsNil)
+	c.Assert(tokenV2.Scheme, check.Equals, "oidc")
+	c.Assert(tokenV2.OAuth2Token.RefreshToken, check.Equals, "refreshtoken")
+	c.Assert(tokenV2.OAuth2Config.Endpoint.DeviceAuthURL, check.Equals, fakeIDP.URL+"/device")
+}
+
+func (s *S) TestDeviceLoginDenied(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+
+	fakeIDP := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		if req.URL.Path == "/device" {
+			rw.Write([]byte(`{"device_code":"dev123","user_code":"ABCD-EFGH","verification_uri":"https://idp.example.com/device","expires_in":60,"interval":1}`))
+			return
+		}
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte(`{"error":"access_denied"}`))
+	}))
+	defer fakeIDP.Close()
+
+	context := &cmd.Context{
+		Stdout: &bytes.Buffer{},
+		Stderr: &bytes.Buffer{},
+	}
+	err := deviceLogin(context, &auth.SchemeInfo{
+		Data: auth.SchemeData{TokenURL: fakeIDP.URL + "/token", ClientID: "test-tsuru"},
+	}, fakeIDP.URL+"/device", "test")
+	c.Assert(err, check.ErrorMatches, "device login failed: .*access_denied.*")
+	c.Assert(strings.Contains(context.Stdout.(*bytes.Buffer).String(), "user_code="), check.Equals, false)
+	tokenV1, err := credentials.ReadTokenV1("test")
+	c.Assert(err, check.IsNil)
+	c.Assert(tokenV1, check.Equals, "")
+}
+
+func (s *S) TestDeviceLoginWithoutDeviceAuthURL(c *check.C) {
+	context := &cmd.Context{
+		Stdout: &bytes.Buffer{},
+		Stderr: &bytes.Buffer{},
+	}
+	err := deviceLogin(context, &auth.SchemeInfo{}, "", "test")
+	c.Assert(err, check.Equals, errDeviceAuthURLMissing)
+}
+
+func (s *S) TestLoginDeviceRequiresOIDC(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+	targetInit()
+	setupNativeScheme(nil)
+
+	command := Login{}
+	command.Flags().Parse([]string{"--device"})
+	err := command.Run(&cmd.Context{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}})
+	c.Assert(err, check.ErrorMatches, `device login is not supported by scheme "native", only by oidc`)
+}
+
+func (s *S) TestSchemeInfoEndpoints(c *check.C) {
+	var schemes []schemeInfo
+	err := json.Unmarshal([]byte(`[
+		{"name": "oidc", "default": true, "data": {"clientID": "tsuru", "tokenURL": "https://idp/token", "deviceAuthURL": "https://idp/device", "revocationURL": "https://idp/revoke"}},
+		{"name": "native"}
+	]`), &schemes)
+	c.Assert(err, check.IsNil)
+	c.Assert(schemes, check.HasLen, 2)
+	c.Assert(schemes[0].Name, check.Equals, "oidc")
+	c.Assert(schemes[0].Default, check.Equals, true)
+	c.Assert(schemes[0].Data.ClientID, check.Equals, "tsuru")
+	c.Assert(schemes[0].Data.TokenURL, check.Equals, "https://idp/token")
+	c.Assert(schemes[0].DeviceAuthURL, check.Equals, "https://idp/device")
+	c.Assert(schemes[0].RevocationURL, check.Equals, "https://idp/revoke")
+	c.Assert(schemes[1].DeviceAuthURL, check.Equals, "")
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/tsuru/go-tsuruclient/pkg/config"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
 	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
 	authTypes "github.com/tsuru/tsuru/types/auth"
 )
@@ -25,7 +26,10 @@ var errTsuruTokenDefined = errors.New("this command can't run with $TSURU_TOKEN
 type Login struct {
 	fs *pflag.FlagSet
 
-	scheme string
+	scheme        string
+	noBrowser     bool
+	device        bool
+	deviceAuthURL string
 }
 
 func (c *Login) Info() *cmd.Info {
@@ -37,8 +41,27 @@ func (c *Login) Info() *cmd.Info {
 		successfully authenticated. If using OAuth, it will open a web browser for the
 		user to complete the login.
 		
+		When the browser runs on another machine, use [[--no-browser]]: the command
+		displays the authorization URL and reads, from the standard input, the URL
+		the browser is redirected to after authorizing, or just its code. The same
+		is offered when the browser can't be started or the callback server on
+		localhost can't be bound.
+		
+		On machines where a browser can't reach a callback server on localhost, like
+		jump hosts, containers and remote development boxes, use [[--device]] to
+		login with the OIDC device authorization grant: the command displays a URL
+		and a code to be entered in a browser on any other device, and waits for
+		the authorization.
+		
 		After that, the token generated by the tsuru server will be stored in
-		[[${HOME}/.tsuru/token]].
+		[[${HOME}/.tsuru/token.d]], and used only on the current target. Each
+		target has its own session, so logging in to one target does not
+		change the others.
+		
+		To keep the tokens in the OS keychain or another secret store instead, set a
+		credential helper with [[tsuru config set credential-helper <name>]]. The
+		helper is the [[tsuru-credential-<name>]] executable, run with the get, store
+		or erase actions and exchanging JSON through its standard input and output.
 		
 		All tsuru actions require the user to be authenticated (except [[tsuru login]]
 		and [[tsuru version]]).`,
@@ -54,6 +77,9 @@ func (c *Login) Flags() *pflag.FlagSet {
 
 		desc := `Login with specific auth scheme`
 		c.fs.StringVarP(&c.scheme, "scheme", "s", "", desc)
+		c.fs.BoolVar(&c.noBrowser, "no-browser", false, "Don't open a browser, display the authorization URL and read the redirected URL or code from the standard input")
+		c.fs.BoolVar(&c.device, "device", false, "Login with the device authorization grant, without a local browser (OIDC only)")
+		c.fs.StringVar(&c.deviceAuthURL, "device-auth-url", "", "Device authorization endpoint, when not published by the tsuru server")
 	}
 	return c.fs
 }
@@ -63,18 +89,34 @@ func (c *Login) Run(ctx *cmd.Context) error {
 		return errTsuruTokenDefined
 	}
 
+	key, err := credentials.CurrentKey()
+	if err != nil {
+		return err
+	}
+
 	scheme, err := getScheme(c.scheme)
 	if err != nil {
 		return err
 	}
 
+	if c.device {
+		if scheme.Name != "oidc" {
+			return fmt.Errorf("device login is not supported by scheme %q, only by oidc", scheme.Name)
+		}
+		deviceAuthURL := c.deviceAuthURL
+		if deviceAuthURL == "" {
+			deviceAuthURL = scheme.DeviceAuthURL
+		}
+		return deviceLogin(ctx, &scheme.SchemeInfo, deviceAuthURL, key)
+	}
+
 	switch scheme.Name {
 	case "oidc":
-		return oidcLogin(ctx, scheme)
+		return oidcLogin(ctx, &scheme.SchemeInfo, key, c.noBrowser)
 	case "oauth":
-		return oauthLogin(ctx, scheme)
+		return oauthLogin(ctx, &scheme.SchemeInfo, key, c.noBrowser)
 	case "native":
-		return nativeLogin(ctx)
+		return nativeLogin(ctx, key)
 	}
 
 	return fmt.Errorf("scheme %q is not implemented", scheme.Name)
@@ -87,7 +129,35 @@ func port(loginInfo *authTypes.SchemeInfo) string {
 	return ":0"
 }
 
-func getScheme(schemeName string) (*authTypes.SchemeInfo, error) {
+// schemeInfo is an auth scheme as returned by the tsuru server, along with
+// the identity provider endpoints published in the scheme data by servers
+// supporting them: the device authorization endpoint, in deviceAuthURL, and
+// the token revocation endpoint (RFC 7009), in revocationURL.
+type schemeInfo struct {
+	authTypes.SchemeInfo
+	DeviceAuthURL string
+	RevocationURL string
+}
+
+func (s *schemeInfo) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &s.SchemeInfo); err != nil {
+		return err
+	}
+	var endpoints struct {
+		Data struct {
+			DeviceAuthURL string `json:"deviceAuthURL"`
+			RevocationURL string `json:"revocationURL"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &endpoints); err != nil {
+		return err
+	}
+	s.DeviceAuthURL = endpoints.Data.DeviceAuthURL
+	s.RevocationURL = endpoints.Data.RevocationURL
+	return nil
+}
+
+func getScheme(schemeName string) (*schemeInfo, error) {
 	schemes, err := schemesInfo()
 	if err != nil {
 		return nil, err
@@ -117,7 +187,7 @@ func getScheme(schemeName string) (*authTypes.SchemeInfo, error) {
 	return nil, fmt.Errorf("scheme %q is not found, valid schemes are: %s", schemeName, strings.Join(foundSchemes, ", "))
 }
 
-func schemesInfo() ([]authTypes.SchemeInfo, error) {
+func schemesInfo() ([]schemeInfo, error) {
 	url, err := config.GetURLVersion("1.18", "/auth/schemes")
 	if err != nil {
 		return nil, err
@@ -131,7 +201,7 @@ func schemesInfo() ([]authTypes.SchemeInfo, error) {
 		return nil, fmt.Errorf("could not call %q, status code: %d", url, resp.StatusCode)
 	}
 
-	schemes := []authTypes.SchemeInfo{}
+	schemes := []schemeInfo{}
 	err = json.NewDecoder(resp.Body).Decode(&schemes)
 	if err != nil {
 		return nil, err
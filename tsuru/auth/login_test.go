@@ -5,6 +5,7 @@ package auth
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"net/http"
 	"os"
@@ -13,6 +14,8 @@ import (
 	"github.com/tsuru/go-tsuruclient/pkg/config"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
 	"github.com/tsuru/tsuru-client/tsuru/cmd/cmdtest"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
+	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
 	"github.com/tsuru/tsuru/fs/fstest"
 	"github.com/tsuru/tsuru/types/auth"
 	"gopkg.in/check.v1"
@@ -22,7 +25,9 @@ func targetInit() {
 	f, _ := config.Filesystem().Create(config.JoinWithUserDir(".tsuru", "target"))
 	f.Write([]byte("http://localhost"))
 	f.Close()
-	config.WriteOnTargetList("test", "http://localhost")
+	f, _ = config.Filesystem().OpenFile(config.JoinWithUserDir(".tsuru", "targets.yaml"), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	f.Write([]byte("targets:\n  test:\n    url: http://localhost\n"))
+	f.Close()
 }
 
 func setupNativeScheme(trans http.RoundTripper) {
@@ -80,11 +85,38 @@ func (s *S) TestNativeLogin(c *check.C) {
 	err := command.Run(&context)
 	c.Assert(err, check.IsNil)
 	c.Assert(context.Stdout.(*bytes.Buffer).String(), check.Equals, expected)
-	token, err := config.ReadTokenV1()
+	key, err := credentials.CurrentKey()
+	c.Assert(err, check.IsNil)
+	token, err := credentials.ReadTokenV1(key)
 	c.Assert(err, check.IsNil)
 	c.Assert(token, check.Equals, "sometoken")
 }
 
+func (s *S) TestNativeLoginDryRun(c *check.C) {
+	os.Setenv("TSURU_DRY_RUN", "true")
+	defer os.Unsetenv("TSURU_DRY_RUN")
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+	targetInit()
+	context := cmd.Context{
+		Args:   []string{"foo@foo.com"},
+		Stdout: &bytes.Buffer{},
+		Stderr: io.Discard,
+		Stdin:  strings.NewReader("chico\n"),
+	}
+	transport := cmdtest.ConditionalTransport{
+		Transport: cmdtest.Transport{Message: `{"token": "sometoken"}`, Status: http.StatusOK},
+		CondFunc: func(r *http.Request) bool {
+			c.Errorf("request should not be sent: %s %s", r.Method, r.URL)
+			return false
+		},
+	}
+	setupNativeScheme(&transport)
+	err := (&Login{}).Run(&context)
+	c.Assert(errors.Is(err, tsuruHTTP.ErrDryRun), check.Equals, true)
+	c.Assert(credentials.HasSession("test"), check.Equals, false)
+}
+
 func (s *S) TestNativeLoginWithoutEmailFromArg(c *check.C) {
 	os.Unsetenv("TSURU_TOKEN")
 	config.SetFileSystem(&fstest.RecordingFs{})
@@ -114,7 +146,7 @@ func (s *S) TestNativeLoginWithoutEmailFromArg(c *check.C) {
 	err := command.Run(&context)
 	c.Assert(err, check.IsNil)
 	c.Assert(context.Stdout.(*bytes.Buffer).String(), check.Equals, expected)
-	token, err := config.ReadTokenV1()
+	token, err := credentials.ReadTokenV1("test")
 	c.Assert(err, check.IsNil)
 	c.Assert(token, check.Equals, "sometoken")
 }
@@ -5,20 +5,33 @@
 package auth
 
 import (
+	stdContext "context"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/tsuru/go-tsuruclient/pkg/config"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
 	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
 )
 
+const revocationTimeout = 10 * time.Second
+
 type Logout struct{}
 
 func (c *Logout) Info() *cmd.Info {
 	return &cmd.Info{
 		Name: "logout",
-		Desc: "Logout will terminate the session with the tsuru server.",
+		Desc: `Logout will terminate the session with the current tsuru target. Sessions on other targets are kept.
+
+For OIDC and OAuth sessions, the refresh and access tokens are also revoked at
+the identity provider, when the tsuru server publishes its revocation endpoint.
+OAuth sessions started by older versions of the client are not revoked, as
+their scheme was not recorded.`,
 
 		OnlyAppendOnRoot: true,
 		GroupID:          "auth",
@@ -28,18 +41,112 @@ func (c *Logout) Info() *cmd.Info {
 func (c *Logout) Run(context *cmd.Context) error {
 	if url, err := config.GetURL("/users/tokens"); err == nil {
 		request, _ := http.NewRequest("DELETE", url, nil)
-		tsuruHTTP.AuthenticatedClient.Do(request)
+		if _, err = tsuruHTTP.AuthenticatedClient.Do(request); errors.Is(err, tsuruHTTP.ErrDryRun) {
+			return err
+		}
 	}
 
-	err := config.RemoveTokenV1()
+	if key, err := credentials.CurrentKey(); err == nil {
+		revokeSession(context, key)
+		if err = credentials.Remove(key); err != nil {
+			return err
+		}
+	}
+	if err := credentials.RemoveLegacy(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(context.Stdout, "Successfully logged out!")
+	return nil
+}
+
+type revocableToken struct {
+	token string
+	hint  string
+}
+
+// revokeSession revokes the tokens of the OIDC or OAuth session stored for
+// key at the identity provider. Failures are only warned about, as the session
+// is removed from the client anyway.
+func revokeSession(context *cmd.Context, key string) {
+	tokenV2, err := credentials.ReadTokenV2(key)
+	if err != nil || tokenV2 == nil {
+		return
+	}
+	var clientID string
+	var tokens []revocableToken
+	switch tokenV2.Scheme {
+	case "oidc":
+		if tokenV2.OAuth2Token == nil || tokenV2.OAuth2Config == nil {
+			return
+		}
+		clientID = tokenV2.OAuth2Config.ClientID
+		tokens = []revocableToken{
+			{tokenV2.OAuth2Token.RefreshToken, "refresh_token"},
+			{tokenV2.OAuth2Token.AccessToken, "access_token"},
+		}
+	case "oauth":
+		// The token of OAuth sessions is the access token issued by the
+		// identity provider to the tsuru server.
+		token, err := credentials.ReadTokenV1(key)
+		if err != nil {
+			return
+		}
+		tokens = []revocableToken{{token, "access_token"}}
+	default:
+		return
+	}
+	schemes, err := schemesInfo()
+	if err != nil {
+		fmt.Fprintf(context.Stderr, "Warning: unable to find the token revocation endpoint: %s\n", err)
+		return
+	}
+	var revocationURL string
+	for _, scheme := range schemes {
+		if scheme.Name == tokenV2.Scheme {
+			revocationURL = scheme.RevocationURL
+			if clientID == "" {
+				clientID = scheme.Data.ClientID
+			}
+		}
+	}
+	if revocationURL == "" {
+		return
+	}
+	for _, t := range tokens {
+		if t.token == "" {
+			continue
+		}
+		if err = revokeToken(revocationURL, clientID, t.token, t.hint); err != nil {
+			fmt.Fprintf(context.Stderr, "Warning: unable to revoke the %s: %s\n", strings.ReplaceAll(t.hint, "_", " "), err)
+		}
+	}
+}
+
+// revokeToken sends a revocation request (RFC 7009) for token to the
+// revocation endpoint of the identity provider. It's sent by the
+// UnauthenticatedClient, which uses the TLS settings of the target.
+func revokeToken(revocationURL, clientID, token, hint string) error {
+	form := url.Values{}
+	form.Set("token", token)
+	form.Set("token_type_hint", hint)
+	if clientID != "" {
+		form.Set("client_id", clientID)
+	}
+	ctx, cancel := stdContext.WithTimeout(stdContext.Background(), revocationTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, revocationURL, strings.NewReader(form.Encode()))
 	if err != nil {
 		return err
 	}
-	err = config.RemoveTokenV2()
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := tsuruHTTP.UnauthenticatedClient.Do(req)
 	if err != nil {
 		return err
 	}
-
-	fmt.Fprintln(context.Stdout, "Successfully logged out!")
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("revocation endpoint returned status %d", resp.StatusCode)
+	}
 	return nil
 }
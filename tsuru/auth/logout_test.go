@@ -5,13 +5,21 @@ package auth
 
 import (
 	"bytes"
+	"errors"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/tsuru/go-tsuruclient/pkg/config"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
 	"github.com/tsuru/tsuru-client/tsuru/cmd/cmdtest"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
+	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
 	"github.com/tsuru/tsuru/fs/fstest"
+	"golang.org/x/oauth2"
 	"gopkg.in/check.v1"
 )
 
@@ -22,8 +30,9 @@ func (s *S) TestLogout(c *check.C) {
 	defer func() {
 		config.ResetFileSystem()
 	}()
-	config.WriteTokenV1("mytoken")
 	os.Setenv("TSURU_TARGET", "localhost:8080")
+	credentials.WriteTokenV1("@localhost_8080", "mytoken")
+	credentials.WriteTokenV1("prod", "prodtoken")
 	expected := "Successfully logged out!\n"
 	context := cmd.Context{
 		Args:   []string{},
@@ -45,8 +54,12 @@ func (s *S) TestLogout(c *check.C) {
 	err := command.Run(&context)
 	c.Assert(err, check.IsNil)
 	c.Assert(context.Stdout.(*bytes.Buffer).String(), check.Equals, expected)
+	c.Assert(rfs.HasAction("remove "+config.JoinWithUserDir(".tsuru", "token.d", "@localhost_8080")), check.Equals, true)
 	c.Assert(rfs.HasAction("remove "+config.JoinWithUserDir(".tsuru", "token")), check.Equals, true)
 	c.Assert(called, check.Equals, true)
+	token, err := credentials.ReadTokenV1("prod")
+	c.Assert(err, check.IsNil)
+	c.Assert(token, check.Equals, "prodtoken")
 }
 
 func (s *S) TestLogoutNoTarget(c *check.C) {
@@ -69,3 +82,166 @@ func (s *S) TestLogoutNoTarget(c *check.C) {
 	c.Assert(context.Stdout.(*bytes.Buffer).String(), check.Equals, expected)
 	c.Assert(rfs.HasAction("remove "+config.JoinWithUserDir(".tsuru", "token")), check.Equals, true)
 }
+
+func setupLogoutSchemes(scheme, revocationURL string) {
+	idpHost := ""
+	if u, err := url.Parse(revocationURL); err == nil {
+		idpHost = u.Host
+	}
+	setupFakeTransport(&cmdtest.AnyConditionalTransport{
+		ConditionalTransports: []cmdtest.ConditionalTransport{
+			{
+				Transport: cmdtest.Transport{
+					Message: `[{"name": "` + scheme + `", "default": true, "data": {"clientID": "tsuru", "revocationURL": "` + revocationURL + `"}}]`,
+					Status:  http.StatusOK,
+				},
+				CondFunc: func(r *http.Request) bool {
+					return strings.HasSuffix(r.URL.Path, "/1.18/auth/schemes")
+				},
+			},
+			{
+				Transport: cmdtest.Transport{Status: http.StatusOK},
+				CondFunc: func(r *http.Request) bool {
+					return r.Method == http.MethodDelete && r.URL.Path == "/users/tokens"
+				},
+			},
+			{
+				// Revocation requests are sent through the client
+				// transport to the fake identity provider.
+				Transport: http.DefaultTransport,
+				CondFunc: func(r *http.Request) bool {
+					return idpHost != "" && r.URL.Host == idpHost
+				},
+			},
+		},
+	})
+}
+
+func writeOIDCSession(c *check.C) {
+	err := credentials.WriteTokenV2("test", config.TokenV2{
+		Scheme:       "oidc",
+		OAuth2Token:  &oauth2.Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)},
+		OAuth2Config: &oauth2.Config{ClientID: "tsuru"},
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(credentials.WriteTokenV1("test", "access"), check.IsNil)
+}
+
+func (s *S) TestLogoutRevokesOIDCTokens(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+	targetInit()
+	writeOIDCSession(c)
+	var revoked []string
+	fakeIDP := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		c.Check(req.Method, check.Equals, http.MethodPost)
+		c.Check(req.FormValue("client_id"), check.Equals, "tsuru")
+		revoked = append(revoked, req.FormValue("token_type_hint")+"="+req.FormValue("token"))
+	}))
+	defer fakeIDP.Close()
+	setupLogoutSchemes("oidc", fakeIDP.URL)
+
+	context := cmd.Context{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}}
+	err := (&Logout{}).Run(&context)
+	c.Assert(err, check.IsNil)
+	c.Assert(context.Stdout.(*bytes.Buffer).String(), check.Equals, "Successfully logged out!\n")
+	c.Assert(context.Stderr.(*bytes.Buffer).String(), check.Equals, "")
+	c.Assert(revoked, check.DeepEquals, []string{"refresh_token=refresh", "access_token=access"})
+	c.Assert(credentials.HasSession("test"), check.Equals, false)
+	tokenV2, err := credentials.ReadTokenV2("test")
+	c.Assert(err, check.IsNil)
+	c.Assert(tokenV2, check.IsNil)
+}
+
+func (s *S) TestLogoutRevocationFailure(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+	targetInit()
+	writeOIDCSession(c)
+	fakeIDP := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer fakeIDP.Close()
+	setupLogoutSchemes("oidc", fakeIDP.URL)
+
+	context := cmd.Context{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}}
+	err := (&Logout{}).Run(&context)
+	c.Assert(err, check.IsNil)
+	c.Assert(context.Stderr.(*bytes.Buffer).String(), check.Equals, `Warning: unable to revoke the refresh token: revocation endpoint returned status 503
+Warning: unable to revoke the access token: revocation endpoint returned status 503
+`)
+	c.Assert(credentials.HasSession("test"), check.Equals, false)
+}
+
+func (s *S) TestLogoutWithoutRevocationEndpoint(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+	targetInit()
+	writeOIDCSession(c)
+	setupLogoutSchemes("oidc", "")
+
+	context := cmd.Context{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}}
+	err := (&Logout{}).Run(&context)
+	c.Assert(err, check.IsNil)
+	c.Assert(context.Stderr.(*bytes.Buffer).String(), check.Equals, "")
+	c.Assert(credentials.HasSession("test"), check.Equals, false)
+}
+
+func (s *S) TestLogoutRevokesOAuthToken(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+	targetInit()
+	c.Assert(credentials.WriteTokenV1("test", "oauth-token"), check.IsNil)
+	c.Assert(credentials.WriteTokenV2("test", config.TokenV2{Scheme: "oauth"}), check.IsNil)
+	var revoked []string
+	fakeIDP := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		c.Check(req.Method, check.Equals, http.MethodPost)
+		c.Check(req.FormValue("client_id"), check.Equals, "tsuru")
+		revoked = append(revoked, req.FormValue("token_type_hint")+"="+req.FormValue("token"))
+	}))
+	defer fakeIDP.Close()
+	setupLogoutSchemes("oauth", fakeIDP.URL)
+
+	context := cmd.Context{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}}
+	err := (&Logout{}).Run(&context)
+	c.Assert(err, check.IsNil)
+	c.Assert(context.Stderr.(*bytes.Buffer).String(), check.Equals, "")
+	c.Assert(revoked, check.DeepEquals, []string{"access_token=oauth-token"})
+	c.Assert(credentials.HasSession("test"), check.Equals, false)
+}
+
+func (s *S) TestLogoutNativeSessionIsNotRevoked(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+	targetInit()
+	c.Assert(credentials.WriteTokenV1("test", "native-token"), check.IsNil)
+	fakeIDP := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		c.Errorf("native tokens should not be sent to the identity provider")
+	}))
+	defer fakeIDP.Close()
+	setupLogoutSchemes("oauth", fakeIDP.URL)
+
+	context := cmd.Context{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}}
+	err := (&Logout{}).Run(&context)
+	c.Assert(err, check.IsNil)
+	c.Assert(credentials.HasSession("test"), check.Equals, false)
+}
+
+func (s *S) TestLogoutDryRun(c *check.C) {
+	os.Setenv("TSURU_DRY_RUN", "true")
+	defer os.Unsetenv("TSURU_DRY_RUN")
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+	targetInit()
+	writeOIDCSession(c)
+	fakeIDP := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		c.Errorf("tokens should not be revoked in dry-run mode")
+	}))
+	defer fakeIDP.Close()
+	setupLogoutSchemes("oidc", fakeIDP.URL)
+
+	context := cmd.Context{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}}
+	err := (&Logout{}).Run(&context)
+	c.Assert(errors.Is(err, tsuruHTTP.ErrDryRun), check.Equals, true)
+	c.Assert(credentials.HasSession("test"), check.Equals, true)
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+)
+
+var errNoAuthorizationCode = errors.New("no authorization code was given")
+
+// manualRedirectURL is the redirect URL used when there's no callback server,
+// the browser is redirected to it and the user pastes the resulting URL.
+func manualRedirectURL(loginInfo *authTypes.SchemeInfo) string {
+	if loginInfo.Data.Port != "" {
+		return "http://localhost:" + loginInfo.Data.Port
+	}
+	return "http://localhost"
+}
+
+// promptAuthorizationCode displays authURL and completes the login with the
+// code read from the standard input, for when the browser can't reach a
+// callback server on localhost.
+func promptAuthorizationCode(ctx *cmd.Context, authURL string, complete func(code string) error) error {
+	fmt.Fprintf(ctx.Stdout, "Open the following URL in a browser, on this or any other device:\n\n    %s\n\n", authURL)
+	fmt.Fprintln(ctx.Stdout, "After authorizing, the browser is redirected to a localhost URL that may fail to load.")
+	fmt.Fprint(ctx.Stdout, "Paste that URL, or just its code parameter, here: ")
+	code, err := readAuthorizationCode(ctx.Stdin)
+	if err != nil {
+		return err
+	}
+	return complete(code)
+}
+
+// pasteAuthorizationCode completes the login with a code pasted on the
+// standard input while the callback server is still waiting, sending the
+// result to finish. Nothing is sent when there's nothing to read, leaving the
+// login to the callback server.
+func pasteAuthorizationCode(ctx *cmd.Context, complete func(code string) error, finish chan<- error) {
+	code, err := readAuthorizationCode(ctx.Stdin)
+	if err == errNoAuthorizationCode {
+		return
+	}
+	if err == nil {
+		err = complete(code)
+	}
+	finish <- err
+}
+
+func readAuthorizationCode(stdin io.Reader) (string, error) {
+	if stdin == nil {
+		return "", errNoAuthorizationCode
+	}
+	line, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	if strings.TrimSpace(line) == "" {
+		return "", errNoAuthorizationCode
+	}
+	return parseAuthorizationCode(line)
+}
+
+// parseAuthorizationCode extracts the authorization code from input, which is
+// either the URL the browser was redirected to, its query string or the code
+// itself.
+func parseAuthorizationCode(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if !strings.Contains(input, "=") {
+		return input, nil
+	}
+	query := input
+	if i := strings.Index(input, "?"); i >= 0 {
+		query = input[i+1:]
+	}
+	if i := strings.Index(query, "#"); i >= 0 {
+		query = query[:i]
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization response: %w", err)
+	}
+	return codeFromQuery(values)
+}
+
+// codeFromQuery returns the code in the query of an authorization response.
+func codeFromQuery(query url.Values) (string, error) {
+	// RFC 6749 section 4.1.2.1: the authorization server may redirect
+	// back with an error instead of a code.
+	if errCode := query.Get("error"); errCode != "" {
+		return "", fmt.Errorf("authorization server returned an error: %s: %s", errCode, query.Get("error_description"))
+	}
+	code := query.Get("code")
+	if code == "" {
+		return "", errors.New("authorization response missing 'code' parameter")
+	}
+	return code, nil
+}
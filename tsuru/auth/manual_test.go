@@ -0,0 +1,184 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/tsuru/go-tsuruclient/pkg/config"
+	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
+	"github.com/tsuru/tsuru/exec"
+	"github.com/tsuru/tsuru/fs/fstest"
+	"github.com/tsuru/tsuru/types/auth"
+	"gopkg.in/check.v1"
+)
+
+func (s *S) TestParseAuthorizationCode(c *check.C) {
+	tests := []struct {
+		input, code, err string
+	}{
+		{input: "321\n", code: "321"},
+		{input: "http://localhost:41000/?code=321&state=", code: "321"},
+		{input: "  http://localhost/?state=x&code=a%2Fb#frag\n", code: "a/b"},
+		{input: "code=321&state=x", code: "321"},
+		{input: "http://localhost/?state=x", err: "authorization response missing 'code' parameter"},
+		{input: "http://localhost/?error=access_denied&error_description=Denied", err: "authorization server returned an error: access_denied: Denied"},
+	}
+	for _, tt := range tests {
+		code, err := parseAuthorizationCode(tt.input)
+		if tt.err != "" {
+			c.Check(err, check.ErrorMatches, tt.err, check.Commentf("input %q", tt.input))
+			continue
+		}
+		c.Check(err, check.IsNil, check.Commentf("input %q", tt.input))
+		c.Check(code, check.Equals, tt.code, check.Commentf("input %q", tt.input))
+	}
+}
+
+func (s *S) TestReadAuthorizationCodeEmpty(c *check.C) {
+	_, err := readAuthorizationCode(nil)
+	c.Assert(err, check.Equals, errNoAuthorizationCode)
+	_, err = readAuthorizationCode(strings.NewReader("\n"))
+	c.Assert(err, check.Equals, errNoAuthorizationCode)
+}
+
+func newCodeExchangeIDP(c *check.C, redirectURL string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		b, err := io.ReadAll(req.Body)
+		c.Assert(err, check.IsNil)
+		body, err := url.ParseQuery(string(b))
+		c.Assert(err, check.IsNil)
+		c.Assert(body.Get("code"), check.Equals, "321")
+		c.Assert(body.Get("redirect_uri"), check.Equals, redirectURL)
+		c.Assert(body.Get("code_verifier"), check.Not(check.Equals), "")
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Write([]byte(`{"access_token":"mytoken", "refresh_token": "refreshtoken"}`))
+	}))
+}
+
+func (s *S) TestOIDCLoginNoBrowser(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	execut = &fakeExecutor{
+		DoExecute: func(opts exec.ExecuteOptions) error {
+			c.Fatal("the browser must not be opened")
+			return nil
+		},
+	}
+	defer func() {
+		config.ResetFileSystem()
+		execut = nil
+	}()
+
+	fakeIDP := newCodeExchangeIDP(c, "http://localhost:41003")
+	defer fakeIDP.Close()
+
+	stdout := &bytes.Buffer{}
+	context := &cmd.Context{
+		Stdin:  strings.NewReader("http://localhost:41003/?code=321&state=\n"),
+		Stdout: stdout,
+		Stderr: &bytes.Buffer{},
+	}
+	err := oidcLogin(context, &auth.SchemeInfo{
+		Data: auth.SchemeData{
+			Port:     "41003",
+			AuthURL:  "https://idp.example.com/authorize",
+			TokenURL: fakeIDP.URL,
+			ClientID: "test-tsuru",
+		},
+	}, "test", true)
+	c.Assert(err, check.IsNil)
+	c.Assert(strings.Contains(stdout.String(), "https://idp.example.com/authorize?client_id=test-tsuru"), check.Equals, true)
+	c.Assert(strings.Contains(stdout.String(), "Paste that URL"), check.Equals, true)
+
+	tokenV2, err := credentials.ReadTokenV2("test")
+	c.Assert(err, check.IsNil)
+	c.Assert(tokenV2.OAuth2Token.AccessToken, check.Equals, "mytoken")
+	c.Assert(tokenV2.OAuth2Config.RedirectURL, check.Equals, "http://localhost:41003")
+}
+
+func (s *S) TestOIDCLoginNoBrowserWithoutCode(c *check.C) {
+	context := &cmd.Context{
+		Stdin:  strings.NewReader(""),
+		Stdout: &bytes.Buffer{},
+		Stderr: &bytes.Buffer{},
+	}
+	err := oidcLogin(context, &auth.SchemeInfo{}, "test", true)
+	c.Assert(err, check.Equals, errNoAuthorizationCode)
+}
+
+func (s *S) TestOIDCLoginPasteWhenBrowserFails(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	execut = &fakeExecutor{
+		DoExecute: func(opts exec.ExecuteOptions) error {
+			return errors.New("no display")
+		},
+	}
+	defer func() {
+		config.ResetFileSystem()
+		execut = nil
+	}()
+
+	fakeIDP := newCodeExchangeIDP(c, "http://localhost:41004")
+	defer fakeIDP.Close()
+
+	stdout := &bytes.Buffer{}
+	context := &cmd.Context{
+		Stdin:  strings.NewReader("321\n"),
+		Stdout: stdout,
+		Stderr: &bytes.Buffer{},
+	}
+	err := oidcLogin(context, &auth.SchemeInfo{
+		Data: auth.SchemeData{
+			Port:     "41004",
+			TokenURL: fakeIDP.URL,
+			ClientID: "test-tsuru",
+		},
+	}, "test", false)
+	c.Assert(err, check.IsNil)
+	c.Assert(strings.Contains(stdout.String(), "Failed to start your browser."), check.Equals, true)
+	tokenV1, err := credentials.ReadTokenV1("test")
+	c.Assert(err, check.IsNil)
+	c.Assert(tokenV1, check.Equals, "mytoken")
+}
+
+func (s *S) TestOAuthLoginNoBrowser(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+
+	fakeTsuruServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		c.Assert(req.URL.Path, check.Equals, "/1.0/auth/login")
+		c.Assert(req.FormValue("code"), check.Equals, "321")
+		c.Assert(req.FormValue("redirectUrl"), check.Equals, "http://localhost")
+		rw.Write([]byte(`{"token":"mytoken"}`))
+	}))
+	defer fakeTsuruServer.Close()
+	os.Setenv("TSURU_TARGET", fakeTsuruServer.URL)
+	defer os.Unsetenv("TSURU_TARGET")
+
+	stdout := &bytes.Buffer{}
+	context := &cmd.Context{
+		Stdin:  strings.NewReader("http://localhost/?code=321\n"),
+		Stdout: stdout,
+	}
+	err := oauthLogin(context, &auth.SchemeInfo{
+		Data: auth.SchemeData{
+			AuthorizeURL: "https://provider.example.com/auth?redirect_uri=__redirect_url__",
+		},
+	}, "test", true)
+	c.Assert(err, check.IsNil)
+	c.Assert(strings.Contains(stdout.String(), "https://provider.example.com/auth?redirect_uri=http://localhost\n"), check.Equals, true)
+	c.Assert(strings.Contains(stdout.String(), "Successfully logged in!"), check.Equals, true)
+	tokenV1, err := credentials.ReadTokenV1("test")
+	c.Assert(err, check.IsNil)
+	c.Assert(tokenV1, check.Equals, "mytoken")
+}
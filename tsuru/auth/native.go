@@ -14,10 +14,11 @@ import (
 
 	"github.com/tsuru/go-tsuruclient/pkg/config"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
 	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
 )
 
-func nativeLogin(ctx *cmd.Context) error {
+func nativeLogin(ctx *cmd.Context, key string) error {
 	var email string
 
 	// Use raw output to avoid missing the input prompt messages
@@ -72,9 +73,9 @@ func nativeLogin(ctx *cmd.Context) error {
 		return err
 	}
 	fmt.Fprintln(ctx.Stdout, "Successfully logged in!")
-	err = config.RemoveTokenV2()
+	err = credentials.RemoveTokenV2(key)
 	if err != nil {
 		return err
 	}
-	return config.WriteTokenV1(out["token"].(string))
+	return credentials.WriteTokenV1(key, out["token"].(string))
 }
@@ -9,7 +9,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -18,39 +17,44 @@ import (
 	"github.com/pkg/errors"
 	"github.com/tsuru/go-tsuruclient/pkg/config"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
-	tsuruNet "github.com/tsuru/tsuru/net"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
+	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
 	authTypes "github.com/tsuru/tsuru/types/auth"
 )
 
-func oauthLogin(ctx *cmd.Context, loginInfo *authTypes.SchemeInfo) error {
-	finish := make(chan bool)
-	l, err := net.Listen("tcp", port(loginInfo))
+func oauthLogin(ctx *cmd.Context, loginInfo *authTypes.SchemeInfo, key string, noBrowser bool) error {
+	l, redirectURL, err := callbackListener(ctx, loginInfo, noBrowser)
 	if err != nil {
 		return err
 	}
-	_, port, err := net.SplitHostPort(l.Addr().String())
-	if err != nil {
-		return err
-	}
-	redirectURL := fmt.Sprintf("http://localhost:%s", port)
 	authURL := strings.Replace(loginInfo.Data.AuthorizeURL, "__redirect_url__", redirectURL, 1)
+	complete := func(code string) error {
+		token, err := convertOAuthToken(code, redirectURL)
+		if err != nil {
+			return err
+		}
+		err = credentials.WriteTokenV1(key, token)
+		if err != nil {
+			return err
+		}
+		// The scheme is recorded so the token can be revoked on logout.
+		return credentials.WriteTokenV2(key, config.TokenV2{Scheme: "oauth"})
+	}
+	if l == nil {
+		if err = promptAuthorizationCode(ctx, authURL, complete); err != nil {
+			return err
+		}
+		fmt.Fprintln(ctx.Stdout, "Successfully logged in!")
+		return nil
+	}
+	finish := make(chan error, 2)
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
-			finish <- true
+			finish <- nil
 		}()
 		var page string
-		token, handlerErr := convertOAuthToken(r.URL.Query().Get("code"), redirectURL)
-		if handlerErr != nil {
-			writeHTMLError(w, handlerErr)
-			return
-		}
-		handlerErr = config.WriteTokenV1(token)
-		if handlerErr != nil {
-			writeHTMLError(w, handlerErr)
-			return
-		}
-		handlerErr = config.RemoveTokenV2()
+		handlerErr := complete(r.URL.Query().Get("code"))
 		if handlerErr != nil {
 			writeHTMLError(w, handlerErr)
 			return
@@ -68,11 +72,16 @@ func oauthLogin(ctx *cmd.Context, loginInfo *authTypes.SchemeInfo) error {
 	if err != nil {
 		fmt.Fprintln(ctx.Stdout, "Failed to start your browser.")
 		fmt.Fprintf(ctx.Stdout, "Please open the following URL in your browser: %s\n", authURL)
+		fmt.Fprintln(ctx.Stdout, "If the browser runs on another machine, paste here the URL it is redirected to, or just its code parameter.")
+		go pasteAuthorizationCode(ctx, complete, finish)
 	}
-	<-finish
+	err = <-finish
 	timedCtx, cancel := stdContext.WithTimeout(stdContext.Background(), 15*time.Second)
 	defer cancel()
 	server.Shutdown(timedCtx)
+	if err != nil {
+		return err
+	}
 	fmt.Fprintln(ctx.Stdout, "Successfully logged in!")
 	return nil
 }
@@ -86,7 +95,7 @@ func convertOAuthToken(code, redirectURL string) (string, error) {
 	if err != nil {
 		return token, errors.Wrap(err, "Error in GetURL")
 	}
-	resp, err := tsuruNet.Dial15Full300Client.Post(u, "application/x-www-form-urlencoded", strings.NewReader(v.Encode()))
+	resp, err := tsuruHTTP.UnauthenticatedClient.Post(u, "application/x-www-form-urlencoded", strings.NewReader(v.Encode()))
 	if err != nil {
 		return token, errors.Wrap(err, "Error during login post")
 	}
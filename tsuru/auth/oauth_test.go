@@ -12,6 +12,7 @@ import (
 
 	"github.com/tsuru/go-tsuruclient/pkg/config"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
 	"github.com/tsuru/tsuru/exec"
 	"github.com/tsuru/tsuru/fs/fstest"
 
@@ -65,10 +66,13 @@ func (s *S) TestOAuthLogin(c *check.C) {
 		Data: auth.SchemeData{
 			Port: "41000",
 		},
-	})
+	}, "test", false)
 
 	c.Assert(err, check.IsNil)
-	tokenV1, err := config.ReadTokenV1()
+	tokenV1, err := credentials.ReadTokenV1("test")
 	c.Assert(err, check.IsNil)
 	c.Assert(tokenV1, check.Equals, "mytoken")
+	tokenV2, err := credentials.ReadTokenV2("test")
+	c.Assert(err, check.IsNil)
+	c.Assert(tokenV2.Scheme, check.Equals, "oauth")
 }
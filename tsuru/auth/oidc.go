@@ -6,7 +6,6 @@ package auth
 
 import (
 	stdContext "context"
-	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -14,20 +13,17 @@ import (
 
 	"github.com/tsuru/go-tsuruclient/pkg/config"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
 	authTypes "github.com/tsuru/tsuru/types/auth"
 	"golang.org/x/oauth2"
 )
 
-func oidcLogin(ctx *cmd.Context, loginInfo *authTypes.SchemeInfo) error {
+func oidcLogin(ctx *cmd.Context, loginInfo *authTypes.SchemeInfo, key string, noBrowser bool) error {
 	pkceVerifier := oauth2.GenerateVerifier()
 
 	fmt.Fprintln(ctx.Stderr, "Starting OIDC login")
 
-	l, err := net.Listen("tcp", port(loginInfo))
-	if err != nil {
-		return err
-	}
-	_, port, err := net.SplitHostPort(l.Addr().String())
+	l, redirectURL, err := callbackListener(ctx, loginInfo, noBrowser)
 	if err != nil {
 		return err
 	}
@@ -35,7 +31,7 @@ func oidcLogin(ctx *cmd.Context, loginInfo *authTypes.SchemeInfo) error {
 	oauth2Config := oauth2.Config{
 		ClientID:    loginInfo.Data.ClientID,
 		Scopes:      loginInfo.Data.Scopes,
-		RedirectURL: fmt.Sprintf("http://localhost:%s", port),
+		RedirectURL: redirectURL,
 		Endpoint: oauth2.Endpoint{
 			AuthURL:  loginInfo.Data.AuthURL,
 			TokenURL: loginInfo.Data.TokenURL,
@@ -44,58 +40,37 @@ func oidcLogin(ctx *cmd.Context, loginInfo *authTypes.SchemeInfo) error {
 
 	authURL := oauth2Config.AuthCodeURL("", oauth2.S256ChallengeOption(pkceVerifier))
 
-	finish := make(chan bool)
+	complete := func(code string) error {
+		t, err := oauth2Config.Exchange(stdContext.Background(), code, oauth2.VerifierOption(pkceVerifier))
+		if err != nil {
+			return err
+		}
+		return storeOIDCSession(ctx, key, t, &oauth2Config)
+	}
+
+	if l == nil {
+		return promptAuthorizationCode(ctx, authURL, complete)
+	}
+
+	finish := make(chan error, 2)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 
 		defer func() {
-			finish <- true
+			finish <- nil
 		}()
 
 		w.Header().Add("Content-Type", "text/html")
 
-		// RFC 6749 section 4.1.2.1: the authorization server may redirect
-		// back with an error instead of a code.
-		query := r.URL.Query()
-		if errCode := query.Get("error"); errCode != "" {
-			handlerErr := fmt.Errorf("authorization server returned an error: %s: %s", errCode, query.Get("error_description"))
-			fmt.Fprintf(ctx.Stderr, "Login failed: %v\n", handlerErr)
-			writeHTMLError(w, handlerErr)
-			return
-		}
-		code := query.Get("code")
-		if code == "" {
-			handlerErr := errors.New("authorization response missing 'code' parameter")
-			fmt.Fprintf(ctx.Stderr, "Login failed: %v\n", handlerErr)
-			writeHTMLError(w, handlerErr)
-			return
-		}
-
-		t, handlerErr := oauth2Config.Exchange(stdContext.Background(), code, oauth2.VerifierOption(pkceVerifier))
-
-		if handlerErr != nil {
-			writeHTMLError(w, handlerErr)
-			return
-		}
-
-		fmt.Fprintln(ctx.Stderr, "Successfully logged in via OIDC!")
-		tokenExpiry := time.Since(t.Expiry) * -1
-		fmt.Fprintf(ctx.Stderr, "The OIDC token will expire in %s\n", tokenExpiry.Round(time.Second))
-
-		handlerErr = config.WriteTokenV2(config.TokenV2{
-			Scheme:       "oidc",
-			OAuth2Token:  t,
-			OAuth2Config: &oauth2Config,
-		})
-
+		code, handlerErr := codeFromQuery(r.URL.Query())
 		if handlerErr != nil {
+			fmt.Fprintf(ctx.Stderr, "Login failed: %v\n", handlerErr)
 			writeHTMLError(w, handlerErr)
 			return
 		}
 
-		// legacy token
-		handlerErr = config.WriteTokenV1(t.AccessToken)
+		handlerErr = complete(code)
 
 		if handlerErr != nil {
 			writeHTMLError(w, handlerErr)
@@ -113,10 +88,52 @@ func oidcLogin(ctx *cmd.Context, loginInfo *authTypes.SchemeInfo) error {
 	if err != nil {
 		fmt.Fprintln(ctx.Stdout, "Failed to start your browser.")
 		fmt.Fprintf(ctx.Stdout, "Please open the following URL in your browser: %s\n", authURL)
+		fmt.Fprintln(ctx.Stdout, "If the browser runs on another machine, paste here the URL it is redirected to, or just its code parameter.")
+		go pasteAuthorizationCode(ctx, complete, finish)
 	}
-	<-finish
+	err = <-finish
 	timedCtx, cancel := stdContext.WithTimeout(stdContext.Background(), 15*time.Second)
 	defer cancel()
 	server.Shutdown(timedCtx)
-	return nil
+	return err
+}
+
+// callbackListener starts listening for the redirect of the authorization
+// server, returning the redirect URL. The listener is nil when the code must
+// be pasted by the user: with --no-browser or when the port can't be bound.
+func callbackListener(ctx *cmd.Context, loginInfo *authTypes.SchemeInfo, noBrowser bool) (net.Listener, string, error) {
+	if noBrowser {
+		return nil, manualRedirectURL(loginInfo), nil
+	}
+	l, err := net.Listen("tcp", port(loginInfo))
+	if err != nil {
+		fmt.Fprintf(ctx.Stderr, "Unable to start the callback server: %v\n", err)
+		return nil, manualRedirectURL(loginInfo), nil
+	}
+	_, port, err := net.SplitHostPort(l.Addr().String())
+	if err != nil {
+		l.Close()
+		return nil, "", err
+	}
+	return l, fmt.Sprintf("http://localhost:%s", port), nil
+}
+
+// storeOIDCSession stores the token obtained by an OIDC login as the session
+// of the target identified by key.
+func storeOIDCSession(ctx *cmd.Context, key string, t *oauth2.Token, oauth2Config *oauth2.Config) error {
+	fmt.Fprintln(ctx.Stderr, "Successfully logged in via OIDC!")
+	tokenExpiry := time.Since(t.Expiry) * -1
+	fmt.Fprintf(ctx.Stderr, "The OIDC token will expire in %s\n", tokenExpiry.Round(time.Second))
+
+	err := credentials.WriteTokenV2(key, config.TokenV2{
+		Scheme:       "oidc",
+		OAuth2Token:  t,
+		OAuth2Config: oauth2Config,
+	})
+	if err != nil {
+		return err
+	}
+
+	// legacy token
+	return credentials.WriteTokenV1(key, t.AccessToken)
 }
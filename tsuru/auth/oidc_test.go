@@ -15,6 +15,7 @@ import (
 
 	"github.com/tsuru/go-tsuruclient/pkg/config"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
 	"github.com/tsuru/tsuru/exec"
 	"github.com/tsuru/tsuru/fs/fstest"
 	"golang.org/x/oauth2"
@@ -70,15 +71,15 @@ func (s *S) TestOIDChLogin(c *check.C) {
 			ClientID: "test-tsuru",
 			Scopes:   []string{"scope1"},
 		},
-	})
+	}, "test", false)
 
 	c.Assert(err, check.IsNil)
 	c.Assert(strings.Contains(context.Stderr.(*bytes.Buffer).String(), "The OIDC token will expire in"), check.Equals, true)
-	tokenV1, err := config.ReadTokenV1()
+	tokenV1, err := credentials.ReadTokenV1("test")
 	c.Assert(err, check.IsNil)
 	c.Assert(tokenV1, check.Equals, "mytoken")
 
-	tokenV2, err := config.ReadTokenV2()
+	tokenV2, err := credentials.ReadTokenV2("test")
 	c.Assert(err, check.IsNil)
 	c.Assert(tokenV2, check.DeepEquals, &config.TokenV2{
 		Scheme: "oidc",
@@ -142,7 +143,7 @@ func (s *S) TestOIDCLoginErrorRedirect(c *check.C) {
 			ClientID: "test-tsuru",
 			Scopes:   []string{"scope1"},
 		},
-	})
+	}, "test", false)
 	c.Assert(err, check.IsNil)
 
 	body := <-bodyCh
@@ -153,7 +154,7 @@ func (s *S) TestOIDCLoginErrorRedirect(c *check.C) {
 	c.Assert(strings.Contains(stderr.String(), "Invalid scopes: openid"), check.Equals, true)
 	c.Assert(atomic.LoadInt32(&tokenEndpointCalls), check.Equals, int32(0))
 
-	tokenV1, _ := config.ReadTokenV1()
+	tokenV1, _ := credentials.ReadTokenV1("test")
 	c.Assert(tokenV1, check.Equals, "")
 }
 
@@ -202,7 +203,7 @@ func (s *S) TestOIDCLoginMissingCode(c *check.C) {
 			ClientID: "test-tsuru",
 			Scopes:   []string{"scope1"},
 		},
-	})
+	}, "test", false)
 	c.Assert(err, check.IsNil)
 
 	body := <-bodyCh
@@ -0,0 +1,221 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tsuru/go-tsuruclient/pkg/config"
+	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
+	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
+	"golang.org/x/oauth2"
+)
+
+var errTokenRejected = errors.New("the token was rejected by the server")
+
+type AuthStatus struct{}
+
+func (c *AuthStatus) Info() *cmd.Info {
+	return &cmd.Info{
+		Name: "auth-status",
+		Desc: `Displays the session on the current target.
+
+The auth scheme, the scopes and issuer of OIDC tokens, the time until the
+token expires and whether it can be refreshed are displayed. Team tokens set in
+the TSURU_TOKEN environment variable are reported as well.
+
+Expired OIDC tokens are refreshed, the same way any other command would do.
+Tokens without an expiry are checked against the tsuru server.
+
+The command fails when there's no valid session, so scripts and CI jobs can
+check the credentials before doing anything else.`,
+	}
+}
+
+// sessionStatus holds the fields displayed by auth-status, in order.
+type sessionStatus struct {
+	fields [][2]string
+	err    error
+}
+
+func (s *sessionStatus) add(name, value string) {
+	if value != "" {
+		s.fields = append(s.fields, [2]string{name, value})
+	}
+}
+
+func (s *sessionStatus) invalid(status string, err error) {
+	s.add("Status", status)
+	s.err = err
+}
+
+func (s *sessionStatus) print(w io.Writer) {
+	for _, f := range s.fields {
+		fmt.Fprintf(w, "%-15s%s\n", f[0]+":", f[1])
+	}
+}
+
+func (c *AuthStatus) Run(ctx *cmd.Context) error {
+	target, err := config.GetTarget()
+	if err != nil {
+		return err
+	}
+	key, err := credentials.CurrentKey()
+	if err != nil {
+		return err
+	}
+	var status sessionStatus
+	status.add("Target", fmt.Sprintf("%s (%s)", key, target))
+	if token := config.ReadTeamToken(); token != "" {
+		status.add("Session", "team token, from the TSURU_TOKEN environment variable")
+		checkTokenStatus(&status, "bearer "+token)
+	} else if err = storedSessionStatus(&status, key); err != nil {
+		return err
+	}
+	status.print(ctx.Stdout)
+	if status.err != nil {
+		return fmt.Errorf("invalid session on target %q: %s, run \"tsuru login\" to start a new one", key, status.err)
+	}
+	return nil
+}
+
+func storedSessionStatus(status *sessionStatus, key string) error {
+	tokenV2, err := credentials.ReadTokenV2(key)
+	if err != nil {
+		return err
+	}
+	if tokenV2 != nil && tokenV2.Scheme == "oidc" && tokenV2.OAuth2Token != nil {
+		oidcSessionStatus(status, key, tokenV2)
+		return nil
+	}
+	token, err := credentials.ReadTokenV1(key)
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		status.invalid("not logged in", errors.New("no session"))
+		return nil
+	}
+	scheme := "native or oauth"
+	if tokenV2 != nil && tokenV2.Scheme != "" {
+		scheme = tokenV2.Scheme
+	}
+	status.add("Scheme", scheme)
+	status.add("Expires", "unknown, checked by the server")
+	checkTokenStatus(status, "bearer "+token)
+	return nil
+}
+
+func oidcSessionStatus(status *sessionStatus, key string, tokenV2 *config.TokenV2) {
+	t := tokenV2.OAuth2Token
+	claims := credentials.JWTClaims(t.AccessToken)
+	status.add("Scheme", "oidc")
+	status.add("User", claimString(claims, "email", "preferred_username", "sub"))
+	status.add("Issuer", claimString(claims, "iss"))
+	if tokenV2.OAuth2Config != nil {
+		status.add("Client ID", tokenV2.OAuth2Config.ClientID)
+		status.add("Scopes", strings.Join(tokenV2.OAuth2Config.Scopes, ", "))
+		if claimString(claims, "iss") == "" {
+			status.add("Token URL", tokenV2.OAuth2Config.Endpoint.TokenURL)
+		}
+	}
+	status.add("Expires", formatExpiry(t.Expiry))
+	refreshable := t.RefreshToken != "" && tokenV2.OAuth2Config != nil
+	refreshStatus := yesNo(refreshable)
+	if expiry := credentials.RefreshTokenExpiry(t); refreshable && !expiry.IsZero() {
+		refreshStatus += ", expires " + formatExpiry(expiry)
+	}
+	status.add("Refresh token", refreshStatus)
+	if t.Valid() {
+		status.add("Status", "valid")
+		return
+	}
+	if !refreshable {
+		status.invalid("expired", errors.New("the token expired and can't be refreshed"))
+		return
+	}
+	refreshed, err := credentials.NewOIDCTokenSource(key, tokenV2).Token()
+	if err != nil {
+		status.invalid("expired, refresh failed", fmt.Errorf("unable to refresh the token: %s", refreshError(err)))
+		return
+	}
+	status.add("Status", "valid, refreshed until "+formatExpiry(refreshed.Expiry))
+}
+
+// checkTokenStatus asks the server whether it accepts authorization, for
+// tokens whose validity can't be told by the client.
+func checkTokenStatus(status *sessionStatus, authorization string) {
+	url, err := config.GetURLVersion("1.0", "/users/info")
+	if err != nil {
+		status.invalid("unknown", err)
+		return
+	}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		status.invalid("unknown", err)
+		return
+	}
+	req.Header.Set("Authorization", authorization)
+	resp, err := tsuruHTTP.UnauthenticatedClient.Do(req)
+	if err != nil {
+		status.invalid("unknown", fmt.Errorf("unable to reach the server: %s", tsuruHTTP.UnwrapErr(err)))
+		return
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		status.add("Status", "valid")
+	case http.StatusUnauthorized:
+		status.invalid("rejected", errTokenRejected)
+	default:
+		status.invalid("unknown", fmt.Errorf("unexpected status %d from the server", resp.StatusCode))
+	}
+}
+
+func formatExpiry(expiry time.Time) string {
+	if expiry.IsZero() {
+		return "never"
+	}
+	until := time.Until(expiry).Round(time.Second)
+	if until < 0 {
+		return fmt.Sprintf("%s (expired %s ago)", expiry.Local().Format(time.RFC3339), -until)
+	}
+	return fmt.Sprintf("%s (in %s)", expiry.Local().Format(time.RFC3339), until)
+}
+
+// claimString returns the first of the claims names that is a non-empty
+// string.
+func claimString(claims map[string]interface{}, names ...string) string {
+	for _, name := range names {
+		if value, ok := claims[name].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func refreshError(err error) string {
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) && retrieveErr.ErrorCode != "" {
+		if retrieveErr.ErrorDescription != "" {
+			return retrieveErr.ErrorCode + ": " + retrieveErr.ErrorDescription
+		}
+		return retrieveErr.ErrorCode
+	}
+	return err.Error()
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
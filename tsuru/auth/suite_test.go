@@ -5,6 +5,7 @@ package auth
 
 import (
 	"net/http"
+	"os"
 	"testing"
 
 	"github.com/tsuru/go-tsuruclient/pkg/config"
@@ -21,6 +22,8 @@ type S struct{}
 
 func (s *S) SetUpTest(c *check.C) {
 	config.ResetFileSystem()
+	os.Unsetenv("TSURU_TARGET")
+	tsuruHTTP.UnauthenticatedClient = &http.Client{}
 }
 
 func setupFakeTransport(rt http.RoundTripper) {
@@ -31,6 +34,6 @@ func setupFakeTransport(rt http.RoundTripper) {
 	})
 
 	tsuruHTTP.UnauthenticatedClient = &http.Client{
-		Transport: rt,
+		Transport: &tsuruHTTP.DryRunRoundTripper{RoundTripper: rt},
 	}
 }
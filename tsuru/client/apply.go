@@ -0,0 +1,721 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/cezarsa/form"
+	"github.com/spf13/pflag"
+	"github.com/tsuru/go-tsuruclient/pkg/config"
+	"github.com/tsuru/go-tsuruclient/pkg/tsuru"
+	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	"github.com/tsuru/tsuru-client/tsuru/cmd/standards"
+	"github.com/tsuru/tsuru-client/tsuru/config/diff"
+	"github.com/tsuru/tsuru-client/tsuru/formatter"
+	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
+	apiTypes "github.com/tsuru/tsuru/types/api"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+)
+
+type AppApply struct {
+	cmd.ConfirmationCommand
+	file      string
+	noRestart bool
+	fs        *pflag.FlagSet
+}
+
+func (c *AppApply) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:  "apply",
+		Usage: "<-f/--file manifest.yaml> [-y/--assume-yes] [--no-restart]",
+		Desc: `Converges an app to the configuration described in a manifest file.
+
+The manifest is a YAML (or JSON) document describing the app. Only the fields
+present in the manifest are managed, so a list left out of the manifest is
+not changed while an empty list removes every existing item. Example:
+
+  name: myapp
+  platform: python
+  plan: c1m1
+  pool: main
+  teamOwner: myteam
+  description: my app
+  tags: [web]
+  cnames: [myapp.example.com]
+  routers:
+  - name: http
+  env:
+  - name: LOG_LEVEL
+    value: info
+  - name: DATABASE_PASSWORD
+    value: secret
+    private: true
+  metadata:
+    labels:
+    - name: tier
+      value: frontend
+  autoscale:
+  - process: web
+    minUnits: 1
+    maxUnits: 5
+    averageCPU: 70%
+  serviceBinds:
+  - service: mysql
+    instance: myapp-db
+  volumeBinds:
+  - volume: myvolume
+    mountPoint: /mnt/data
+    readOnly: true
+
+The app is created when it does not exist. The changes are displayed as a diff
+and are only applied after confirmation. Values of private environment
+variables cannot be read back, so the ones holding the "` + privateEnvMask + `" placeholder,
+as exported by "tsuru app export", are kept as they are, while any other value
+replaces the current one.
+
+A manifest for an existing app can be generated with "tsuru app export". The
+manifest may be read from stdin using "-" as the file name.`,
+	}
+}
+
+func (c *AppApply) Flags() *pflag.FlagSet {
+	if c.fs == nil {
+		flagSet := pflag.NewFlagSet("", pflag.ExitOnError)
+		flagSet.StringVarP(&c.file, "file", "f", "", "Path to the app manifest")
+		flagSet.BoolVar(&c.noRestart, standards.FlagNoRestart, false, "Apply the changes without restarting the application")
+		c.fs = mergeFlagSet(
+			c.ConfirmationCommand.Flags(),
+			flagSet,
+		)
+	}
+	return c.fs
+}
+
+func (c *AppApply) Run(ctx *cmd.Context) error {
+	ctx.RawOutput()
+	if c.file == "" {
+		return errors.New("please use the -f/--file flag to specify the app manifest")
+	}
+	desired, err := readAppManifest(c.file)
+	if err != nil {
+		return err
+	}
+	current, err := fetchAppManifest(desired.Name)
+	if err != nil {
+		return err
+	}
+	if err = checkPlaceholders(current, desired); err != nil {
+		return err
+	}
+
+	changes, err := manifestDiff(current.scoped(desired), desired)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Fprintf(ctx.Stdout, "App %q is up to date.\n", desired.Name)
+		return nil
+	}
+	fmt.Fprintln(ctx.Stdout, string(changes))
+
+	if !c.Confirm(ctx, fmt.Sprintf("Are you sure you want to apply these changes to app %q?", desired.Name)) {
+		return nil
+	}
+
+	if current == nil {
+		if err = createAppFromManifest(ctx.Stdout, desired); err != nil {
+			return err
+		}
+		current, err = fetchAppManifest(desired.Name)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return fmt.Errorf("app %q was not found after creation", desired.Name)
+		}
+	}
+
+	applier := &manifestApplier{
+		w:         ctx.Stdout,
+		current:   current,
+		desired:   desired,
+		noRestart: c.noRestart,
+	}
+	if err = applier.apply(); err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Stdout, "App %q has been applied!\n", desired.Name)
+	return nil
+}
+
+// checkPlaceholders fails when desired still has the placeholder of private
+// variables that must be set, as exported by app-export, so nothing is
+// changed before the manifest is fixed. Placeholders of private variables the
+// app already has are kept as they are.
+func checkPlaceholders(current, desired *AppManifest) error {
+	currentPrivate := map[string]bool{}
+	if current != nil {
+		for _, e := range current.Env {
+			currentPrivate[e.Name] = e.Private
+		}
+	}
+	var names []string
+	for _, e := range desired.Env {
+		if e.Private && e.Value == privateEnvMask && !currentPrivate[e.Name] {
+			names = append(names, strconv.Quote(e.Name))
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	if len(names) == 1 {
+		return fmt.Errorf("private variable %s must have its placeholder value replaced", names[0])
+	}
+	return fmt.Errorf("private variables %s must have their placeholder values replaced", strings.Join(names, ", "))
+}
+
+func manifestDiff(current, desired *AppManifest) ([]byte, error) {
+	currentData, err := current.yaml()
+	if err != nil {
+		return nil, err
+	}
+	desiredData, err := desired.yaml()
+	if err != nil {
+		return nil, err
+	}
+	return diff.Diff(bytes.NewReader(currentData), bytes.NewReader(desiredData))
+}
+
+func createAppFromManifest(w io.Writer, m *AppManifest) error {
+	v := url.Values{}
+	if len(m.Routers) > 0 {
+		var err error
+		v, err = form.EncodeToValues(map[string]interface{}{"routeropts": m.Routers[0].Opts})
+		if err != nil {
+			return err
+		}
+		v.Set("router", m.Routers[0].Name)
+	}
+	v.Set("name", m.Name)
+	v.Set("platform", m.Platform)
+	v.Set("plan", m.Plan)
+	v.Set("teamOwner", m.TeamOwner)
+	v.Set("pool", m.Pool)
+	v.Set("description", m.Description)
+	for _, tag := range m.Tags {
+		v.Add("tag", tag)
+	}
+	u, err := config.GetURL("/apps")
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest("POST", u, strings.NewReader(v.Encode()))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	response, err := tsuruHTTP.AuthenticatedClient.Do(request)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	fmt.Fprintf(w, "App %q has been created!\n", m.Name)
+	return nil
+}
+
+type manifestApplier struct {
+	w         io.Writer
+	current   *AppManifest
+	desired   *AppManifest
+	noRestart bool
+	apiClient *tsuru.APIClient
+}
+
+func (a *manifestApplier) apply() error {
+	var err error
+	a.apiClient, err = tsuruHTTP.TsuruClientFromEnvironment()
+	if err != nil {
+		return err
+	}
+	steps := []func() error{
+		a.applyUpdate,
+		a.applyCNames,
+		a.applyRouters,
+		a.applyEnvs,
+		a.applyAutoScale,
+		a.applyServiceBinds,
+		a.applyVolumeBinds,
+	}
+	for _, step := range steps {
+		if err = step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *manifestApplier) applyUpdate() error {
+	current, desired := a.current, a.desired
+	update := tsuru.UpdateApp{NoRestart: a.noRestart}
+	changed := false
+	setIfChanged := func(dst *string, currentValue, desiredValue string) {
+		if desiredValue != "" && desiredValue != currentValue {
+			*dst = desiredValue
+			changed = true
+		}
+	}
+	setIfChanged(&update.Platform, current.Platform, desired.Platform)
+	setIfChanged(&update.Plan, current.Plan, desired.Plan)
+	setIfChanged(&update.Pool, current.Pool, desired.Pool)
+	setIfChanged(&update.TeamOwner, current.TeamOwner, desired.TeamOwner)
+	setIfChanged(&update.Description, current.Description, desired.Description)
+	if desired.Tags != nil && !reflect.DeepEqual(current.Tags, desired.Tags) {
+		update.Tags = desired.Tags
+		changed = true
+	}
+	if desired.Metadata != nil {
+		update.Metadata = metadataChanges(current.Metadata, desired.Metadata)
+		changed = changed || len(update.Metadata.Labels) > 0 || len(update.Metadata.Annotations) > 0
+	}
+	if desired.Processes != nil {
+		currentProcesses := map[string]manifestProcess{}
+		for _, p := range current.Processes {
+			currentProcesses[p.Name] = p
+		}
+		for _, p := range desired.Processes {
+			cp := currentProcesses[p.Name]
+			process := tsuru.AppProcess{Name: p.Name}
+			if p.Plan != cp.Plan {
+				process.Plan = p.Plan
+				if process.Plan == "" {
+					process.Plan = "$default"
+				}
+			}
+			process.Metadata = metadataChanges(cp.Metadata, p.Metadata)
+			if process.Plan != "" || len(process.Metadata.Labels) > 0 || len(process.Metadata.Annotations) > 0 {
+				update.Processes = append(update.Processes, process)
+			}
+		}
+		changed = changed || len(update.Processes) > 0
+	}
+	if !changed {
+		return nil
+	}
+	fmt.Fprintln(a.w, "Updating app...")
+	response, err := a.apiClient.AppApi.AppUpdate(context.Background(), desired.Name, update)
+	if err != nil {
+		return err
+	}
+	return formatter.StreamJSONResponse(a.w, response)
+}
+
+func metadataChanges(current, desired *manifestMetadata) tsuru.Metadata {
+	var result tsuru.Metadata
+	if desired == nil {
+		return result
+	}
+	if current == nil {
+		current = &manifestMetadata{}
+	}
+	result.Labels = metadataItemChanges(current.Labels, desired.Labels)
+	result.Annotations = metadataItemChanges(current.Annotations, desired.Annotations)
+	return result
+}
+
+func metadataItemChanges(current, desired []appTypes.MetadataItem) []tsuru.MetadataItem {
+	var result []tsuru.MetadataItem
+	currentValues := map[string]string{}
+	for _, item := range current {
+		currentValues[item.Name] = item.Value
+	}
+	desiredNames := map[string]bool{}
+	for _, item := range desired {
+		desiredNames[item.Name] = true
+		if value, ok := currentValues[item.Name]; !ok || value != item.Value {
+			result = append(result, tsuru.MetadataItem{Name: item.Name, Value: item.Value})
+		}
+	}
+	for _, item := range current {
+		if !desiredNames[item.Name] {
+			result = append(result, tsuru.MetadataItem{Name: item.Name, Delete: true})
+		}
+	}
+	return result
+}
+
+func (a *manifestApplier) applyCNames() error {
+	if a.desired.CNames == nil {
+		return nil
+	}
+	toAdd, toRemove := stringSetChanges(a.current.CNames, a.desired.CNames)
+	if len(toAdd) > 0 {
+		fmt.Fprintf(a.w, "Adding cnames: %s\n", strings.Join(toAdd, ", "))
+		if err := addCName(a.desired.Name, toAdd); err != nil {
+			return err
+		}
+	}
+	if len(toRemove) > 0 {
+		fmt.Fprintf(a.w, "Removing cnames: %s\n", strings.Join(toRemove, ", "))
+		if err := unsetCName(a.desired.Name, toRemove); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func stringSetChanges(current, desired []string) (toAdd, toRemove []string) {
+	currentSet := map[string]bool{}
+	for _, s := range current {
+		currentSet[s] = true
+	}
+	desiredSet := map[string]bool{}
+	for _, s := range desired {
+		desiredSet[s] = true
+		if !currentSet[s] {
+			toAdd = append(toAdd, s)
+		}
+	}
+	for _, s := range current {
+		if !desiredSet[s] {
+			toRemove = append(toRemove, s)
+		}
+	}
+	return toAdd, toRemove
+}
+
+func (a *manifestApplier) applyRouters() error {
+	if a.desired.Routers == nil {
+		return nil
+	}
+	currentRouters := map[string]manifestRouter{}
+	for _, r := range a.current.Routers {
+		currentRouters[r.Name] = r
+	}
+	desiredRouters := map[string]bool{}
+	for _, r := range a.desired.Routers {
+		desiredRouters[r.Name] = true
+		opts := map[string]interface{}{}
+		for k, v := range r.Opts {
+			opts[k] = v
+		}
+		appRouter := tsuru.AppRouter{Name: r.Name, Opts: opts}
+		cr, found := currentRouters[r.Name]
+		var err error
+		switch {
+		case !found:
+			fmt.Fprintf(a.w, "Adding router %q...\n", r.Name)
+			_, err = a.apiClient.AppApi.AppRouterAdd(context.Background(), a.desired.Name, appRouter)
+		case !reflect.DeepEqual(cr.Opts, r.Opts) && (len(cr.Opts) > 0 || len(r.Opts) > 0):
+			fmt.Fprintf(a.w, "Updating router %q...\n", r.Name)
+			_, err = a.apiClient.AppApi.AppRouterUpdate(context.Background(), a.desired.Name, r.Name, appRouter)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range a.current.Routers {
+		if desiredRouters[r.Name] {
+			continue
+		}
+		fmt.Fprintf(a.w, "Removing router %q...\n", r.Name)
+		if _, err := a.apiClient.AppApi.AppRouterDelete(context.Background(), a.desired.Name, r.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *manifestApplier) applyEnvs() error {
+	if a.desired.Env == nil {
+		return nil
+	}
+	currentEnvs := map[string]manifestEnv{}
+	for _, e := range a.current.Env {
+		currentEnvs[e.Name] = e
+	}
+	var public, private []apiTypes.Env
+	desiredNames := map[string]bool{}
+	for _, e := range a.desired.Env {
+		desiredNames[e.Name] = true
+		ce, found := currentEnvs[e.Name]
+		if found && ce.Private == e.Private && (e.Private && e.Value == privateEnvMask || !e.Private && ce.Value == e.Value) {
+			continue
+		}
+		env := apiTypes.Env{Name: e.Name, Value: e.Value}
+		if e.Private {
+			private = append(private, env)
+		} else {
+			public = append(public, env)
+		}
+	}
+	if len(public) > 0 {
+		fmt.Fprintln(a.w, "Setting environment variables...")
+		if err := setAppEnvs(a.w, a.desired.Name, apiTypes.Envs{Envs: public, NoRestart: a.noRestart}); err != nil {
+			return err
+		}
+	}
+	if len(private) > 0 {
+		fmt.Fprintln(a.w, "Setting private environment variables...")
+		if err := setAppEnvs(a.w, a.desired.Name, apiTypes.Envs{Envs: private, Private: true, NoRestart: a.noRestart}); err != nil {
+			return err
+		}
+	}
+	var toUnset []string
+	for _, e := range a.current.Env {
+		if !desiredNames[e.Name] {
+			toUnset = append(toUnset, e.Name)
+		}
+	}
+	if len(toUnset) == 0 {
+		return nil
+	}
+	fmt.Fprintln(a.w, "Unsetting environment variables...")
+	v := url.Values{}
+	for _, name := range toUnset {
+		v.Add("env", name)
+	}
+	v.Set("noRestart", strconv.FormatBool(a.noRestart))
+	u, err := config.GetURL(fmt.Sprintf("/apps/%s/env?%s", a.desired.Name, v.Encode()))
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return err
+	}
+	response, err := tsuruHTTP.AuthenticatedClient.Do(request)
+	if err != nil {
+		return err
+	}
+	return formatter.StreamJSONResponse(a.w, response)
+}
+
+func setAppEnvs(w io.Writer, appName string, envs apiTypes.Envs) error {
+	u, err := config.GetURL(fmt.Sprintf("/apps/%s/env", appName))
+	if err != nil {
+		return err
+	}
+	v, err := form.EncodeToValues(&envs)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest("POST", u, strings.NewReader(v.Encode()))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	response, err := tsuruHTTP.AuthenticatedClient.Do(request)
+	if err != nil {
+		return err
+	}
+	return formatter.StreamJSONResponse(w, response)
+}
+
+func (a *manifestApplier) applyAutoScale() error {
+	if a.desired.Autoscale == nil {
+		return nil
+	}
+	currentSpecs := map[string]manifestAutoScale{}
+	for _, as := range a.current.Autoscale {
+		currentSpecs[as.Process] = as
+	}
+	desiredProcesses := map[string]bool{}
+	for _, as := range a.desired.Autoscale {
+		desiredProcesses[as.Process] = true
+		if cs, found := currentSpecs[as.Process]; found && reflect.DeepEqual(cs, as) {
+			continue
+		}
+		spec, err := autoScaleFromManifest(as)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(a.w, "Setting autoscale of process %q...\n", as.Process)
+		if _, err = a.apiClient.AppApi.AutoScaleAdd(context.Background(), a.desired.Name, spec); err != nil {
+			return err
+		}
+	}
+	for _, as := range a.current.Autoscale {
+		if desiredProcesses[as.Process] {
+			continue
+		}
+		fmt.Fprintf(a.w, "Unsetting autoscale of process %q...\n", as.Process)
+		if _, err := a.apiClient.AppApi.AutoScaleRemove(context.Background(), a.desired.Name, as.Process); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func autoScaleFromManifest(as manifestAutoScale) (tsuru.AutoScaleSpec, error) {
+	var spec tsuru.AutoScaleSpec
+	data, err := json.Marshal(provTypes.AutoScaleSpec{
+		Process:    as.Process,
+		MinUnits:   as.MinUnits,
+		MaxUnits:   as.MaxUnits,
+		AverageCPU: as.AverageCPU,
+		Schedules:  as.Schedules,
+		Prometheus: as.Prometheus,
+		Behavior:   provTypes.BehaviorAutoScaleSpec{ScaleDown: as.ScaleDown},
+	})
+	if err != nil {
+		return spec, err
+	}
+	err = json.Unmarshal(data, &spec)
+	return spec, err
+}
+
+func (a *manifestApplier) applyServiceBinds() error {
+	if a.desired.ServiceBinds == nil {
+		return nil
+	}
+	key := func(b manifestServiceBind) string {
+		return b.Service + "/" + b.Instance
+	}
+	current := map[string]bool{}
+	for _, b := range a.current.ServiceBinds {
+		current[key(b)] = true
+	}
+	desired := map[string]bool{}
+	for _, b := range a.desired.ServiceBinds {
+		desired[key(b)] = true
+		if current[key(b)] {
+			continue
+		}
+		fmt.Fprintf(a.w, "Binding service instance %q...\n", key(b))
+		if err := a.requestServiceBind(http.MethodPut, b); err != nil {
+			return err
+		}
+	}
+	for _, b := range a.current.ServiceBinds {
+		if desired[key(b)] {
+			continue
+		}
+		fmt.Fprintf(a.w, "Unbinding service instance %q...\n", key(b))
+		if err := a.requestServiceBind(http.MethodDelete, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *manifestApplier) requestServiceBind(method string, b manifestServiceBind) error {
+	path := "/services/" + b.Service + "/instances/" + b.Instance + "/apps/" + a.desired.Name
+	u, err := config.GetURLVersion("1.13", path)
+	if err != nil {
+		return err
+	}
+	v := url.Values{}
+	v.Set("noRestart", strconv.FormatBool(a.noRestart))
+	var request *http.Request
+	if method == http.MethodDelete {
+		request, err = http.NewRequest(method, u+"?"+v.Encode(), nil)
+	} else {
+		request, err = http.NewRequest(method, u, strings.NewReader(v.Encode()))
+		if request != nil {
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+	if err != nil {
+		return err
+	}
+	response, err := tsuruHTTP.AuthenticatedClient.Do(request)
+	if err != nil {
+		return err
+	}
+	return formatter.StreamJSONResponse(a.w, response)
+}
+
+func (a *manifestApplier) applyVolumeBinds() error {
+	if a.desired.VolumeBinds == nil {
+		return nil
+	}
+	key := func(b manifestVolumeBind) string {
+		return b.Volume + ":" + b.MountPoint
+	}
+	current := map[string]manifestVolumeBind{}
+	for _, b := range a.current.VolumeBinds {
+		current[key(b)] = b
+	}
+	desired := map[string]bool{}
+	for _, b := range a.desired.VolumeBinds {
+		desired[key(b)] = true
+		cb, found := current[key(b)]
+		if found && cb.ReadOnly == b.ReadOnly {
+			continue
+		}
+		if found {
+			fmt.Fprintf(a.w, "Unbinding volume %q...\n", key(cb))
+			if err := a.requestVolumeBind(http.MethodDelete, cb); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(a.w, "Binding volume %q...\n", key(b))
+		if err := a.requestVolumeBind(http.MethodPost, b); err != nil {
+			return err
+		}
+	}
+	for _, b := range a.current.VolumeBinds {
+		if desired[key(b)] {
+			continue
+		}
+		fmt.Fprintf(a.w, "Unbinding volume %q...\n", key(b))
+		if err := a.requestVolumeBind(http.MethodDelete, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *manifestApplier) requestVolumeBind(method string, b manifestVolumeBind) error {
+	bind := struct {
+		App        string
+		MountPoint string
+		ReadOnly   bool
+		NoRestart  bool
+	}{
+		App:        a.desired.Name,
+		MountPoint: b.MountPoint,
+		ReadOnly:   b.ReadOnly,
+		NoRestart:  a.noRestart,
+	}
+	v, err := form.EncodeToValues(bind)
+	if err != nil {
+		return err
+	}
+	u, err := config.GetURLVersion("1.4", fmt.Sprintf("/volumes/%s/bind", b.Volume))
+	if err != nil {
+		return err
+	}
+	var request *http.Request
+	if method == http.MethodDelete {
+		v.Del("ReadOnly")
+		request, err = http.NewRequest(method, u+"?"+v.Encode(), nil)
+	} else {
+		request, err = http.NewRequest(method, u, strings.NewReader(v.Encode()))
+		if request != nil {
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+	if err != nil {
+		return err
+	}
+	response, err := tsuruHTTP.AuthenticatedClient.Do(request)
+	if err != nil {
+		return err
+	}
+	return formatter.StreamJSONResponse(a.w, response)
+}
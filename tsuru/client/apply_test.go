@@ -0,0 +1,256 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	"github.com/tsuru/tsuru-client/tsuru/cmd/cmdtest"
+	"gopkg.in/check.v1"
+)
+
+const applyAppInfo = `{
+  "name": "app1",
+  "platform": "python",
+  "teamowner": "myteam",
+  "pool": "main",
+  "description": "my app",
+  "plan": {"name": "c1m1"},
+  "tags": ["web"],
+  "cname": ["app1.example.com"],
+  "routers": [{"name": "http"}]
+}`
+
+const applyAppEnvs = `[
+  {"name": "LOG_LEVEL", "value": "info", "public": true},
+  {"name": "DATABASE_PASSWORD", "value": "", "public": false},
+  {"name": "TSURU_SERVICES", "value": "{}", "public": true, "managedBy": "tsuru"}
+]`
+
+func (s *S) writeManifest(c *check.C, content string) string {
+	path := filepath.Join(c.MkDir(), "app.yaml")
+	err := os.WriteFile(path, []byte(content), 0600)
+	c.Assert(err, check.IsNil)
+	return path
+}
+
+func applyGetTransports() []cmdtest.ConditionalTransport {
+	return []cmdtest.ConditionalTransport{
+		{
+			Transport: cmdtest.Transport{Message: applyAppInfo, Status: http.StatusOK},
+			CondFunc: func(req *http.Request) bool {
+				return req.Method == "GET" && strings.HasSuffix(req.URL.Path, "/apps/app1")
+			},
+		},
+		{
+			Transport: cmdtest.Transport{Message: applyAppEnvs, Status: http.StatusOK},
+			CondFunc: func(req *http.Request) bool {
+				return req.Method == "GET" && strings.HasSuffix(req.URL.Path, "/apps/app1/env")
+			},
+		},
+	}
+}
+
+func (s *S) TestAppApplyInfo(c *check.C) {
+	c.Assert((&AppApply{}).Info(), check.NotNil)
+}
+
+func (s *S) TestAppApplyRequiresFile(c *check.C) {
+	var stdout, stderr bytes.Buffer
+	context := cmd.Context{Stdout: &stdout, Stderr: &stderr}
+	command := AppApply{}
+	err := command.Run(&context)
+	c.Assert(err, check.ErrorMatches, "please use the -f/--file flag to specify the app manifest")
+}
+
+func (s *S) TestAppApplyManifestWithoutName(c *check.C) {
+	var stdout, stderr bytes.Buffer
+	context := cmd.Context{Stdout: &stdout, Stderr: &stderr}
+	path := s.writeManifest(c, "plan: c1m1\n")
+	command := AppApply{}
+	err := command.Flags().Parse([]string{"-f", path})
+	c.Assert(err, check.IsNil)
+	err = command.Run(&context)
+	c.Assert(err, check.ErrorMatches, `manifest ".*" must define the app name`)
+}
+
+func (s *S) TestAppApplyUpToDate(c *check.C) {
+	var stdout, stderr bytes.Buffer
+	context := cmd.Context{Stdout: &stdout, Stderr: &stderr}
+	path := s.writeManifest(c, `name: app1
+plan: c1m1
+tags: [web]
+cnames: [app1.example.com]
+env:
+- name: LOG_LEVEL
+  value: info
+- name: DATABASE_PASSWORD
+  value: "***"
+  private: true
+`)
+	s.setupFakeTransport(&cmdtest.AnyConditionalTransport{ConditionalTransports: applyGetTransports()})
+	command := AppApply{}
+	err := command.Flags().Parse([]string{"-f", path})
+	c.Assert(err, check.IsNil)
+	err = command.Run(&context)
+	c.Assert(err, check.IsNil)
+	c.Assert(stdout.String(), check.Equals, "App \"app1\" is up to date.\n")
+}
+
+func (s *S) TestAppApplyUpdate(c *check.C) {
+	var stdout, stderr bytes.Buffer
+	context := cmd.Context{Stdout: &stdout, Stderr: &stderr}
+	path := s.writeManifest(c, `name: app1
+plan: c2m2
+cnames: [app1.example.org]
+env:
+- name: LOG_LEVEL
+  value: debug
+`)
+	var calls []string
+	transports := append(applyGetTransports(),
+		cmdtest.ConditionalTransport{
+			Transport: cmdtest.Transport{Status: http.StatusOK},
+			CondFunc: func(req *http.Request) bool {
+				if req.Method != "PUT" || !strings.HasSuffix(req.URL.Path, "/apps/app1") {
+					return false
+				}
+				var result map[string]interface{}
+				data, err := io.ReadAll(req.Body)
+				c.Assert(err, check.IsNil)
+				err = json.Unmarshal(data, &result)
+				c.Assert(err, check.IsNil)
+				c.Assert(result["plan"], check.Equals, "c2m2")
+				calls = append(calls, "update")
+				return true
+			},
+		},
+		cmdtest.ConditionalTransport{
+			Transport: cmdtest.Transport{Status: http.StatusOK},
+			CondFunc: func(req *http.Request) bool {
+				if !strings.HasSuffix(req.URL.Path, "/apps/app1/cname") {
+					return false
+				}
+				req.ParseForm()
+				calls = append(calls, req.Method+" cname "+strings.Join(req.Form["cname"], ","))
+				return true
+			},
+		},
+		cmdtest.ConditionalTransport{
+			Transport: cmdtest.Transport{Status: http.StatusOK},
+			CondFunc: func(req *http.Request) bool {
+				if req.Method != "POST" || !strings.HasSuffix(req.URL.Path, "/apps/app1/env") {
+					return false
+				}
+				req.ParseForm()
+				c.Assert(req.Form.Get("Envs.0.Name"), check.Equals, "LOG_LEVEL")
+				c.Assert(req.Form.Get("Envs.0.Value"), check.Equals, "debug")
+				calls = append(calls, "set env")
+				return true
+			},
+		},
+		cmdtest.ConditionalTransport{
+			Transport: cmdtest.Transport{Status: http.StatusOK},
+			CondFunc: func(req *http.Request) bool {
+				if req.Method != "DELETE" || !strings.HasSuffix(req.URL.Path, "/apps/app1/env") {
+					return false
+				}
+				calls = append(calls, "unset env "+strings.Join(req.URL.Query()["env"], ","))
+				return true
+			},
+		},
+	)
+	s.setupFakeTransport(&cmdtest.AnyConditionalTransport{ConditionalTransports: transports})
+	command := AppApply{}
+	err := command.Flags().Parse([]string{"-f", path, "-y"})
+	c.Assert(err, check.IsNil)
+	err = command.Run(&context)
+	c.Assert(err, check.IsNil)
+	c.Assert(calls, check.DeepEquals, []string{
+		"update",
+		"POST cname app1.example.org",
+		"DELETE cname app1.example.com",
+		"set env",
+		"unset env DATABASE_PASSWORD",
+	})
+	c.Assert(stdout.String(), check.Matches, `(?s).*-plan: c1m1.*\+plan: c2m2.*App "app1" has been applied!\n`)
+}
+
+func (s *S) TestAppApplyNewPrivateValue(c *check.C) {
+	var stdout, stderr bytes.Buffer
+	context := cmd.Context{Stdout: &stdout, Stderr: &stderr}
+	path := s.writeManifest(c, `name: app1
+env:
+- name: LOG_LEVEL
+  value: info
+- name: DATABASE_PASSWORD
+  value: rotated
+  private: true
+`)
+	var calls []string
+	transports := append(applyGetTransports(), cmdtest.ConditionalTransport{
+		Transport: cmdtest.Transport{Status: http.StatusOK},
+		CondFunc: func(req *http.Request) bool {
+			if req.Method != "POST" || !strings.HasSuffix(req.URL.Path, "/apps/app1/env") {
+				c.Errorf("unexpected request: %s %s", req.Method, req.URL)
+				return true
+			}
+			req.ParseForm()
+			c.Assert(req.Form.Get("Private"), check.Equals, "true")
+			c.Assert(req.Form.Get("Envs.0.Name"), check.Equals, "DATABASE_PASSWORD")
+			c.Assert(req.Form.Get("Envs.0.Value"), check.Equals, "rotated")
+			calls = append(calls, "set private env")
+			return true
+		},
+	})
+	s.setupFakeTransport(&cmdtest.AnyConditionalTransport{ConditionalTransports: transports})
+	command := AppApply{}
+	err := command.Flags().Parse([]string{"-f", path, "-y"})
+	c.Assert(err, check.IsNil)
+	err = command.Run(&context)
+	c.Assert(err, check.IsNil)
+	c.Assert(calls, check.DeepEquals, []string{"set private env"})
+	c.Assert(stdout.String(), check.Matches, `(?s).*-  value: '\*\*\*'\n\+  value: '\*\*\* \(new value\)'.*Setting private environment variables\.\.\..*`)
+	c.Assert(strings.Contains(stdout.String(), "rotated"), check.Equals, false)
+}
+
+func (s *S) TestAppApplyPlaceholderBeforeAnyChange(c *check.C) {
+	var stdout, stderr bytes.Buffer
+	context := cmd.Context{Stdout: &stdout, Stderr: &stderr}
+	path := s.writeManifest(c, `name: app1
+plan: c2m2
+env:
+- name: DATABASE_PASSWORD
+  value: "***"
+  private: true
+- name: API_KEY
+  value: "***"
+  private: true
+- name: LOG_LEVEL
+  value: "***"
+  private: true
+`)
+	transports := append(applyGetTransports(), cmdtest.ConditionalTransport{
+		Transport: cmdtest.Transport{Status: http.StatusOK},
+		CondFunc: func(req *http.Request) bool {
+			c.Errorf("unexpected request: %s %s", req.Method, req.URL)
+			return true
+		},
+	})
+	s.setupFakeTransport(&cmdtest.AnyConditionalTransport{ConditionalTransports: transports})
+	command := AppApply{}
+	err := command.Flags().Parse([]string{"-f", path, "-y"})
+	c.Assert(err, check.IsNil)
+	err = command.Run(&context)
+	c.Assert(err, check.ErrorMatches, `private variables "API_KEY", "LOG_LEVEL" must have their placeholder values replaced`)
+	c.Assert(stdout.String(), check.Equals, "")
+}
@@ -191,6 +191,13 @@ func (c *AppCreate) Run(context *cmd.Context) error {
 	if err != nil {
 		return err
 	}
+	team, pool := targetDefaults()
+	if c.teamOwner == "" {
+		c.teamOwner = team
+	}
+	if c.pool == "" {
+		c.pool = pool
+	}
 	v.Set("name", appName)
 	v.Set("platform", platform)
 	v.Set("plan", c.plan)
@@ -407,7 +414,7 @@ var _ cmd.AutoCompleteCommand = &AppInfo{}
 type AppInfo struct {
 	tsuruClientApp.AppNameMixIn
 
-	json         bool
+	output       formatter.Output
 	simplified   bool
 	flagsApplied bool
 }
@@ -425,7 +432,7 @@ func (cmd *AppInfo) Flags() *pflag.FlagSet {
 	fs := cmd.AppNameMixIn.Flags()
 	if !cmd.flagsApplied {
 		fs.BoolVarP(&cmd.simplified, "simplified", "s", false, "Show simplified view of app")
-		fs.BoolVar(&cmd.json, standards.FlagJSON, false, "Show JSON view of app")
+		cmd.output.AddFlags(fs)
 
 		cmd.flagsApplied = true
 	}
@@ -1282,8 +1289,11 @@ func translateTimestampSince(timestamp *time.Time) string {
 }
 
 func (c *AppInfo) Show(a *app, context *cmd.Context, simplified bool) error {
-	if c.json {
-		return formatter.JSON(context.Stdout, a)
+	if !c.output.IsTable() {
+		return c.output.Print(context.Stdout, formatter.Printable{
+			Data:  a,
+			Names: func() []string { return []string{a.Name} },
+		})
 	}
 	fmt.Fprintln(context.Stdout, a.String(simplified))
 	return nil
@@ -1437,7 +1447,7 @@ type AppList struct {
 	fs         *pflag.FlagSet
 	filter     appFilter
 	simplified bool
-	json       bool
+	output     formatter.Output
 }
 
 func (c *AppList) Run(context *cmd.Context) error {
@@ -1484,24 +1494,42 @@ func (c *AppList) Show(result []byte, context *cmd.Context) error {
 		}
 		return nil
 	}
-	if c.json {
-		return formatter.JSON(context.Stdout, apps)
+	if !c.output.IsTable() {
+		return c.output.Print(context.Stdout, formatter.Printable{
+			Data:  apps,
+			Names: func() []string { return appResumeNames(apps) },
+			Rows:  func() ([]string, [][]string) { return appListRows(apps) },
+		})
 	}
 
+	wide := c.output.Wide()
 	if tablecli.TableConfig.UseTabWriter {
 		table.Headers = tablecli.Row([]string{"Application", "Ready", "Reason"})
+		if wide {
+			table.Headers = append(table.Headers, "Pool", "Platform", "Team Owner")
+		}
 		for _, app := range apps {
 			stats := collectUnitStats(&app, false)
 			ready := appListReadyUnitsSummary(stats)
 			summary := appListCompactSummary(&app, stats)
-			table.AddRow([]string{app.Name, ready, summary})
+			row := []string{app.Name, ready, summary}
+			if wide {
+				row = append(row, app.Pool, app.Platform, app.TeamOwner)
+			}
+			table.AddRow(row)
 		}
 	} else {
 		table.Headers = tablecli.Row([]string{"Application", "Units", "Address"})
+		if wide {
+			table.Headers = append(table.Headers, "Pool", "Platform", "Team Owner")
+		}
 		for _, app := range apps {
 			summary := appListSummary(&app)
 			addrs := strings.ReplaceAll(AppResumeAddr(&app), ", ", "\n")
 			row := []string{app.Name, summary, addrs}
+			if wide {
+				row = append(row, app.Pool, app.Platform, app.TeamOwner)
+			}
 
 			table.AddRow(row)
 		}
@@ -1512,6 +1540,33 @@ func (c *AppList) Show(result []byte, context *cmd.Context) error {
 	return nil
 }
 
+func appResumeNames(apps []appTypes.AppResume) []string {
+	names := make([]string, len(apps))
+	for i, app := range apps {
+		names[i] = app.Name
+	}
+	return names
+}
+
+func appListRows(apps []appTypes.AppResume) ([]string, [][]string) {
+	header := []string{"name", "pool", "plan", "platform", "teamowner", "ready", "units", "address"}
+	rows := make([][]string, 0, len(apps))
+	for _, app := range apps {
+		stats := collectUnitStats(&app, false)
+		rows = append(rows, []string{
+			app.Name,
+			app.Pool,
+			app.Plan.Name,
+			app.Platform,
+			app.TeamOwner,
+			strconv.Itoa(stats.readyUnits),
+			strconv.Itoa(stats.totalUnits),
+			AppResumeAddr(&app),
+		})
+	}
+	return header, rows
+}
+
 type unitStats struct {
 	totalUnits      int
 	unitsWithErrors int
@@ -1598,15 +1653,15 @@ func (c *AppList) Flags() *pflag.FlagSet {
 		c.fs = pflag.NewFlagSet("app-list", pflag.ExitOnError)
 		c.fs.StringVarP(&c.filter.name, standards.FlagName, standards.ShortFlagName, "", "Filter applications by name")
 
-		c.fs.StringVarP(&c.filter.pool, standards.FlagPool, standards.ShortFlagPool, "", "Filter applications by pool")
+		c.fs.StringVar(&c.filter.pool, standards.FlagPool, "", "Filter applications by pool"+formatter.MovedShorthandNote)
 		c.fs.StringVarP(&c.filter.status, "status", "s", "", "Filter applications by unit status. Accepts multiple values separated by commas. Possible values can be: building, created, starting, error, started, stopped, asleep")
 		c.fs.StringVarP(&c.filter.platform, "platform", "p", "", "Filter applications by platform")
 		c.fs.StringVarP(&c.filter.teamOwner, standards.FlagTeam, standards.ShortFlagTeam, "", "Filter applications by team owner")
 		c.fs.StringVarP(&c.filter.owner, standards.FlagUser, standards.ShortFlagUser, "", "Filter applications by owner")
 		c.fs.BoolVarP(&c.simplified, standards.FlagOnlyName, standards.ShortFlagOnlyName, false, "Display only applications name")
-		c.fs.BoolVar(&c.json, standards.FlagJSON, false, "Display applications in JSON format")
 		tagMessage := "Filter applications by tag. Can be used multiple times"
 		c.fs.VarP(&c.filter.tags, standards.FlagTag, standards.ShortFlagTag, tagMessage)
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -1794,7 +1849,11 @@ type CnameAdd struct {
 }
 
 func (c *CnameAdd) Run(context *cmd.Context) error {
-	err := addCName(context.Args, c.AppNameMixIn)
+	appName, err := c.AppNameByFlag()
+	if err != nil {
+		return err
+	}
+	err = addCName(appName, context.Args)
 	if err != nil {
 		return err
 	}
@@ -1819,7 +1878,11 @@ type CnameRemove struct {
 }
 
 func (c *CnameRemove) Run(context *cmd.Context) error {
-	err := unsetCName(context.Args, c.AppNameMixIn)
+	appName, err := c.AppNameByFlag()
+	if err != nil {
+		return err
+	}
+	err = unsetCName(appName, context.Args)
 	if err != nil {
 		return err
 	}
@@ -1839,11 +1902,7 @@ After unsetting the CNAME from the app, [[tsuru app list]] and [[tsuru app info]
 	}
 }
 
-func unsetCName(cnames []string, g tsuruClientApp.AppNameMixIn) error {
-	appName, err := g.AppNameByFlag()
-	if err != nil {
-		return err
-	}
+func unsetCName(appName string, cnames []string) error {
 	v := url.Values{}
 	for _, cname := range cnames {
 		v.Add("cname", cname)
@@ -1860,11 +1919,7 @@ func unsetCName(cnames []string, g tsuruClientApp.AppNameMixIn) error {
 	return err
 }
 
-func addCName(cnames []string, g tsuruClientApp.AppNameMixIn) error {
-	appName, err := g.AppNameByFlag()
-	if err != nil {
-		return err
-	}
+func addCName(appName string, cnames []string) error {
 	u, err := config.GetURL(fmt.Sprintf("/apps/%s/cname", appName))
 	if err != nil {
 		return err
@@ -18,9 +18,12 @@ import (
 	"github.com/fatih/color"
 	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
+	"github.com/tsuru/go-tsuruclient/pkg/config"
 	tsuruApp "github.com/tsuru/tsuru-client/tsuru/app"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
 	"github.com/tsuru/tsuru-client/tsuru/cmd/cmdtest"
+	"github.com/tsuru/tsuru-client/tsuru/targets"
+	"github.com/tsuru/tsuru/fs/fstest"
 	tsuruIo "github.com/tsuru/tsuru/io"
 	appTypes "github.com/tsuru/tsuru/types/app"
 	provTypes "github.com/tsuru/tsuru/types/provision"
@@ -145,6 +148,41 @@ Use app info to check the status of the app and its units.` + "\n"
 	c.Assert(stdout.String(), check.Equals, expected)
 }
 
+func (s *S) TestAppCreateWithTargetDefaults(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+	err := targets.Update(func(list map[string]targets.Target) error {
+		list["local"] = targets.Target{URL: "http://localhost:8080", Team: "platform", Pool: "dev"}
+		return nil
+	})
+	c.Assert(err, check.IsNil)
+	var stdout, stderr bytes.Buffer
+	context := cmd.Context{
+		Args:   []string{"ble", "django"},
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	trans := cmdtest.ConditionalTransport{
+		Transport: cmdtest.Transport{Message: `{"status":"success"}`, Status: http.StatusOK},
+		CondFunc: func(r *http.Request) bool {
+			return r.FormValue("teamOwner") == "platform" && r.FormValue("pool") == "dev"
+		},
+	}
+	s.setupFakeTransport(&trans)
+	command := AppCreate{}
+	command.Flags().Parse([]string{})
+	err = command.Run(&context)
+	c.Assert(err, check.IsNil)
+
+	trans.CondFunc = func(r *http.Request) bool {
+		return r.FormValue("teamOwner") == "team" && r.FormValue("pool") == "dev"
+	}
+	command = AppCreate{}
+	command.Flags().Parse([]string{"-t", "team"})
+	err = command.Run(&context)
+	c.Assert(err, check.IsNil)
+}
+
 func (s *S) TestAppCreatePlan(c *check.C) {
 	var stdout, stderr bytes.Buffer
 	result := `{"status":"success", "repository_url":"git@tsuru.plataformas.glb.com:ble.git"}`
@@ -2487,6 +2525,38 @@ func (s *S) TestAppList(c *check.C) {
 	c.Assert(stdout.String(), check.Equals, expected)
 }
 
+func (s *S) TestAppListOutputName(c *check.C) {
+	var stdout, stderr bytes.Buffer
+	result := `[{"ip":"10.10.10.11","name":"sapp","units":[]},{"ip":"10.10.10.10","name":"app1","units":[]}]`
+	context := cmd.Context{
+		Args:   []string{},
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	s.setupFakeTransport(&cmdtest.Transport{Message: result, Status: http.StatusOK})
+	command := AppList{}
+	command.Flags().Parse([]string{"--output", "name"})
+	err := command.Run(&context)
+	c.Assert(err, check.IsNil)
+	c.Assert(stdout.String(), check.Equals, "sapp\napp1\n")
+}
+
+func (s *S) TestAppListOutputCSV(c *check.C) {
+	var stdout, stderr bytes.Buffer
+	result := `[{"ip":"10.10.10.10","name":"app1","pool":"pool1","platform":"go","teamowner":"team1","units":[{"ID":"app1/0","Status":"started","Ready":true}]}]`
+	context := cmd.Context{
+		Args:   []string{},
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	s.setupFakeTransport(&cmdtest.Transport{Message: result, Status: http.StatusOK})
+	command := AppList{}
+	command.Flags().Parse([]string{"--output", "csv"})
+	err := command.Run(&context)
+	c.Assert(err, check.IsNil)
+	c.Assert(stdout.String(), check.Equals, "name,pool,plan,platform,teamowner,ready,units,address\napp1,pool1,,go,team1,1,1,http://10.10.10.10\n")
+}
+
 func (s *S) TestAppListDisplayAppsInAlphabeticalOrder(c *check.C) {
 	var stdout, stderr bytes.Buffer
 	result := `[{"ip":"10.10.10.11","name":"sapp","units":[{"ID":"sapp1/0","Status":"started"}]},{"ip":"10.10.10.10","name":"app1","units":[{"ID":"app1/0","Status":"started"}]}]`
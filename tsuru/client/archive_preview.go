@@ -0,0 +1,120 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/tsuru/tablecli"
+	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
+)
+
+const listFilesDesc = "Lists the files that would be uploaded, with their sizes and the ignored ones, without contacting the server"
+
+// previewArchive lists the files of the archive built by newArchive and its
+// compressed size, instead of uploading it. It's used by the --list-files
+// flag and by the dry-run mode of deploys and builds, in which case
+// tsuruHTTP.ErrDryRun is returned.
+func previewArchive(ctx *cmd.Context, dryRun bool, newArchive func(opts ArchiveOptions) (archiveWriter, error)) error {
+	var entries []ArchiveEntry
+	opts := DefaultArchiveOptions(ctx.Stderr)
+	opts.OnEntry = func(e ArchiveEntry) {
+		entries = append(entries, e)
+	}
+	archive, err := newArchive(opts)
+	if err != nil {
+		return err
+	}
+	var compressed byteCounter
+	err = archive(&compressed)
+	if err != nil && !errors.Is(err, ErrMissingFilesToArchive) {
+		return err
+	}
+	renderArchivePreview(ctx.Stdout, entries, int64(compressed))
+	if err == nil && dryRun {
+		return tsuruHTTP.ErrDryRun
+	}
+	return err
+}
+
+type byteCounter int64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
+
+// ignoredGroup is an ignored path, along with the paths under it ignored by
+// the same pattern when it's a directory.
+type ignoredGroup struct {
+	entry ArchiveEntry
+	files int
+}
+
+func (g *ignoredGroup) contains(e ArchiveEntry) bool {
+	return g.entry.Dir &&
+		g.entry.IgnoreFile == e.IgnoreFile &&
+		g.entry.IgnorePattern == e.IgnorePattern &&
+		strings.HasPrefix(e.Name, strings.TrimSuffix(g.entry.Name, string(os.PathSeparator))+string(os.PathSeparator))
+}
+
+func renderArchivePreview(w io.Writer, entries []ArchiveEntry, compressed int64) {
+	included := tablecli.NewTable()
+	included.Headers = tablecli.Row{"File", "Size"}
+	var files int
+	var size int64
+	var groups []*ignoredGroup
+	for _, e := range entries {
+		if e.Ignored() {
+			if n := len(groups); n > 0 && groups[n-1].contains(e) {
+				groups[n-1].entry.Size += e.Size
+				if !e.Dir {
+					groups[n-1].files++
+				}
+				continue
+			}
+			groups = append(groups, &ignoredGroup{entry: e})
+			continue
+		}
+		if e.Dir {
+			continue
+		}
+		files++
+		size += e.Size
+		included.AddRow(tablecli.Row{e.Name, formatArchiveSize(e.Size)})
+	}
+	fmt.Fprintln(w, "Included files:")
+	fmt.Fprint(w, included.String())
+	if len(groups) > 0 {
+		ignored := tablecli.NewTable()
+		ignored.Headers = tablecli.Row{"File", "Size", "Ignored by"}
+		for _, g := range groups {
+			name := g.entry.Name
+			if g.entry.Dir {
+				name = fmt.Sprintf("%s%c (%d files)", strings.TrimSuffix(name, string(os.PathSeparator)), os.PathSeparator, g.files)
+			}
+			ignored.AddRow(tablecli.Row{name, formatArchiveSize(g.entry.Size), g.entry.IgnoreFile + ": " + g.entry.IgnorePattern})
+		}
+		fmt.Fprintln(w, "\nIgnored files:")
+		fmt.Fprint(w, ignored.String())
+	}
+	fmt.Fprintf(w, "\nTotal: %d files, %s (%s compressed)\n", files, formatArchiveSize(size), formatArchiveSize(compressed))
+}
+
+func formatArchiveSize(size int64) string {
+	const kilobyte, megabyte = 1024.0, 1024.0 * 1024.0
+	switch {
+	case size >= megabyte:
+		return fmt.Sprintf("%0.2fMB", float64(size)/megabyte)
+	case size >= kilobyte:
+		return fmt.Sprintf("%0.2fKB", float64(size)/kilobyte)
+	}
+	return fmt.Sprintf("%dB", size)
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+
+	"gopkg.in/check.v1"
+)
+
+func (s *S) TestRenderArchivePreview(c *check.C) {
+	entries := []ArchiveEntry{
+		{Name: ".tsuruignore", Size: 20},
+		{Name: "app.py", Size: 2048},
+		{Name: "node_modules", Dir: true, IgnoreFile: ".tsuruignore", IgnorePattern: "node_modules"},
+		{Name: "node_modules/lib", Dir: true, IgnoreFile: ".tsuruignore", IgnorePattern: "node_modules"},
+		{Name: "node_modules/lib/a.js", Size: 1024 * 1024, IgnoreFile: ".tsuruignore", IgnorePattern: "node_modules"},
+		{Name: "node_modules/lib/b.js", Size: 1024 * 1024, IgnoreFile: ".tsuruignore", IgnorePattern: "node_modules"},
+		{Name: "static", Dir: true},
+		{Name: "static/logo.png", Size: 3 * 1024},
+		{Name: ".env", Size: 12, IgnoreFile: ".tsuruignore", IgnorePattern: ".env"},
+	}
+	var stdout bytes.Buffer
+	renderArchivePreview(&stdout, entries, 1536)
+	expected := `Included files:
++-----------------+--------+
+| File            | Size   |
++-----------------+--------+
+| .tsuruignore    | 20B    |
+| app.py          | 2.00KB |
+| static/logo.png | 3.00KB |
++-----------------+--------+
+
+Ignored files:
++-------------------------+--------+----------------------------+
+| File                    | Size   | Ignored by                 |
++-------------------------+--------+----------------------------+
+| node_modules/ (2 files) | 2.00MB | .tsuruignore: node_modules |
+| .env                    | 12B    | .tsuruignore: .env         |
++-------------------------+--------+----------------------------+
+
+Total: 3 files, 5.02KB (1.50KB compressed)
+`
+	c.Assert(stdout.String(), check.Equals, expected)
+}
+
+func (s *S) TestRenderArchivePreviewNothingIgnored(c *check.C) {
+	var stdout bytes.Buffer
+	renderArchivePreview(&stdout, []ArchiveEntry{{Name: "Procfile", Size: 10}}, 40)
+	expected := `Included files:
++----------+------+
+| File     | Size |
++----------+------+
+| Procfile | 10B  |
++----------+------+
+
+Total: 1 files, 10B (40B compressed)
+`
+	c.Assert(stdout.String(), check.Equals, expected)
+}
@@ -24,6 +24,35 @@ type ArchiveOptions struct {
 	CompressionLevel *int      // defaults to default compression "-1"
 	IgnoreFiles      []string  // default to none
 	Stderr           io.Writer // defaults to io.Discard
+
+	// OnEntry, when set, is called for every path walked, either added to
+	// the archive or skipped by an ignore pattern. The skipped paths are
+	// not reported to Stderr then.
+	OnEntry func(ArchiveEntry)
+}
+
+// ArchiveEntry describes a path walked by Archive.
+type ArchiveEntry struct {
+	Name string
+	Size int64
+	Dir  bool
+
+	// IgnoreFile and IgnorePattern tell the pattern that excluded the path
+	// from the archive, empty for paths added to it.
+	IgnoreFile    string
+	IgnorePattern string
+}
+
+// Ignored reports whether the path was excluded from the archive.
+func (e ArchiveEntry) Ignored() bool {
+	return e.IgnorePattern != ""
+}
+
+// ignorePattern is a line of an ignore file, compiled alone to tell which
+// pattern excluded a path.
+type ignorePattern struct {
+	file, line string
+	ignore     *gitignore.GitIgnore
 }
 
 func DefaultArchiveOptions(w io.Writer) ArchiveOptions {
@@ -48,6 +77,7 @@ func Archive(dst io.Writer, filesOnly bool, paths []string, opts ArchiveOptions)
 	}
 
 	var ignoreLines []string
+	var patterns []ignorePattern
 	for _, ignoreFile := range opts.IgnoreFiles {
 		data, err := os.ReadFile(ignoreFile)
 		if errors.Is(err, os.ErrNotExist) {
@@ -60,7 +90,22 @@ func Archive(dst io.Writer, filesOnly bool, paths []string, opts ArchiveOptions)
 
 		fmt.Fprintf(opts.Stderr, "Using pattern(s) from %q to include/exclude files...\n", ignoreFile)
 
-		ignoreLines = append(ignoreLines, strings.Split(string(data), "\n")...)
+		lines := strings.Split(string(data), "\n")
+		ignoreLines = append(ignoreLines, lines...)
+		if opts.OnEntry == nil {
+			continue
+		}
+		for _, line := range lines {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+				continue
+			}
+			pattern, err := gitignore.CompileIgnoreLines(line)
+			if err != nil {
+				return fmt.Errorf("failed to compile ignore pattern %q: %w", line, err)
+			}
+			patterns = append(patterns, ignorePattern{file: ignoreFile, line: line, ignore: pattern})
+		}
 	}
 
 	ignore, err := gitignore.CompileIgnoreLines(ignoreLines...)
@@ -82,18 +127,33 @@ func Archive(dst io.Writer, filesOnly bool, paths []string, opts ArchiveOptions)
 	defer tw.Close()
 
 	a := &archiver{
-		ignore: *ignore,
-		stderr: opts.Stderr,
-		files:  map[string]struct{}{},
+		ignore:   *ignore,
+		patterns: patterns,
+		stderr:   opts.Stderr,
+		onEntry:  opts.OnEntry,
+		files:    map[string]struct{}{},
 	}
 
 	return a.archive(tw, filesOnly, paths)
 }
 
 type archiver struct {
-	ignore gitignore.GitIgnore
-	stderr io.Writer
-	files  map[string]struct{}
+	ignore   gitignore.GitIgnore
+	patterns []ignorePattern
+	stderr   io.Writer
+	onEntry  func(ArchiveEntry)
+	files    map[string]struct{}
+}
+
+// ignoredBy returns the pattern excluding filename: the last one matching
+// it, as later patterns take precedence.
+func (a *archiver) ignoredBy(filename string) ignorePattern {
+	for i := len(a.patterns) - 1; i >= 0; i-- {
+		if a.patterns[i].ignore.MatchesPath(filename) {
+			return a.patterns[i]
+		}
+	}
+	return ignorePattern{line: "(unknown)"}
 }
 
 func (a *archiver) archive(tw *tar.Writer, filesOnly bool, paths []string) error {
@@ -170,7 +230,16 @@ func (a *archiver) addFile(tw *tar.Writer, filesOnly bool, filename string, fi o
 	}
 
 	if a.ignore.MatchesPath(filename) {
-		fmt.Fprintf(a.stderr, "File %q matches with some pattern provided in the ignore file... skipping it.\n", filename)
+		if a.onEntry == nil {
+			fmt.Fprintf(a.stderr, "File %q matches with some pattern provided in the ignore file... skipping it.\n", filename)
+			return 0, nil
+		}
+		pattern := a.ignoredBy(filename)
+		entry := ArchiveEntry{Name: filename, Dir: isDir, IgnoreFile: pattern.file, IgnorePattern: pattern.line}
+		if isRegular {
+			entry.Size = fi.Size()
+		}
+		a.onEntry(entry)
 		return 0, nil
 	}
 
@@ -208,6 +277,10 @@ func (a *archiver) addFile(tw *tar.Writer, filesOnly bool, filename string, fi o
 		return 0, err
 	}
 
+	if a.onEntry != nil {
+		a.onEntry(ArchiveEntry{Name: h.Name, Size: h.Size, Dir: isDir})
+	}
+
 	if isDir || isSymlink { // there's no data to copy from dir or symlink
 		return 1, nil
 	}
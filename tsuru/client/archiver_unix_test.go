@@ -92,6 +92,69 @@ func (s *S) TestArchive_PassingWholeDir_WithTsuruIgnore(c *check.C) {
 	c.Assert(stderr.String(), check.Matches, `(?s)(.*)File "file2\.txt" matches with some pattern provided in the ignore file\.\.\. skipping it\.(.*)`)
 }
 
+func (s *S) TestArchive_OnEntry(c *check.C) {
+	workingDir, err := os.Getwd()
+	c.Assert(err, check.IsNil)
+
+	defer func() { os.Chdir(workingDir) }()
+
+	err = os.Chdir(filepath.Join(workingDir, "./testdata/deploy2/"))
+	c.Assert(err, check.IsNil)
+
+	var stderr bytes.Buffer
+	var entries []ArchiveEntry
+	opts := ArchiveOptions{
+		IgnoreFiles: []string{".tsuruignore"},
+		Stderr:      &stderr,
+		OnEntry:     func(e ArchiveEntry) { entries = append(entries, e) },
+	}
+	err = Archive(io.Discard, false, []string{"."}, opts)
+	c.Assert(err, check.IsNil)
+
+	c.Assert(entries, check.DeepEquals, []ArchiveEntry{
+		{Name: ".tsuruignore", Size: 5},
+		{Name: "directory", Dir: true},
+		{Name: "directory/dir2", Dir: true},
+		{Name: "directory/dir2/file.txt", IgnoreFile: ".tsuruignore", IgnorePattern: "*.txt"},
+		{Name: "directory/file.txt", Size: 4, IgnoreFile: ".tsuruignore", IgnorePattern: "*.txt"},
+		{Name: "file1.txt", Size: 19, IgnoreFile: ".tsuruignore", IgnorePattern: "*.txt"},
+		{Name: "file2.txt", Size: 6, IgnoreFile: ".tsuruignore", IgnorePattern: "*.txt"},
+	})
+	c.Assert(stderr.String(), check.Not(check.Matches), `(?s).*matches with some pattern.*`)
+}
+
+func (s *S) TestArchive_OnEntryLastPatternWins(c *check.C) {
+	workingDir, err := os.Getwd()
+	c.Assert(err, check.IsNil)
+	defer func() { os.Chdir(workingDir) }()
+
+	dir := c.MkDir()
+	err = os.Chdir(dir)
+	c.Assert(err, check.IsNil)
+	c.Assert(os.WriteFile(".tsuruignore", []byte("*.log\n# comment\n!keep.log\nbuild\n"), 0644), check.IsNil)
+	c.Assert(os.WriteFile("app.log", []byte("log"), 0644), check.IsNil)
+	c.Assert(os.WriteFile("keep.log", []byte("keep"), 0644), check.IsNil)
+	c.Assert(os.Mkdir("build", 0755), check.IsNil)
+	c.Assert(os.WriteFile("build/app.log", []byte("log"), 0644), check.IsNil)
+
+	ignored := map[string]string{}
+	opts := ArchiveOptions{
+		IgnoreFiles: []string{".tsuruignore"},
+		OnEntry: func(e ArchiveEntry) {
+			if e.Ignored() {
+				ignored[e.Name] = e.IgnorePattern
+			}
+		},
+	}
+	err = Archive(io.Discard, false, []string{"."}, opts)
+	c.Assert(err, check.IsNil)
+	c.Assert(ignored, check.DeepEquals, map[string]string{
+		"app.log":       "*.log",
+		"build":         "build",
+		"build/app.log": "build",
+	})
+}
+
 func (s *S) TestArchive_FilesOnly(c *check.C) {
 	var b bytes.Buffer
 	err := Archive(&b, true, []string{"./testdata/deploy/directory/file.txt", "./testdata/deploy2/file1.txt"}, ArchiveOptions{})
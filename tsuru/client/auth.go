@@ -24,6 +24,7 @@ import (
 	"github.com/tsuru/tablecli"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
 	"github.com/tsuru/tsuru-client/tsuru/cmd/standards"
+	"github.com/tsuru/tsuru-client/tsuru/formatter"
 	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
 )
@@ -289,6 +290,7 @@ and "app revoke" commands for details).`,
 type TeamList struct {
 	fs         *pflag.FlagSet
 	simplified bool
+	output     formatter.Output
 }
 
 func (c *TeamList) Info() *cmd.Info {
@@ -302,6 +304,7 @@ func (c *TeamList) Flags() *pflag.FlagSet {
 	if c.fs == nil {
 		c.fs = pflag.NewFlagSet("team-list", pflag.ExitOnError)
 		c.fs.BoolVarP(&c.simplified, standards.FlagOnlyName, standards.ShortFlagOnlyName, false, "Display only team's name")
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -330,6 +333,26 @@ func (c *TeamList) Run(ctx *cmd.Context) error {
 		return nil
 	}
 
+	if !c.output.IsTable() {
+		return c.output.Print(ctx.Stdout, formatter.Printable{
+			Data: teams,
+			Names: func() []string {
+				names := make([]string, len(teams))
+				for i, team := range teams {
+					names[i] = team.Name
+				}
+				return names
+			},
+			Rows: func() ([]string, [][]string) {
+				rows := make([][]string, len(teams))
+				for i, team := range teams {
+					rows[i] = []string{team.Name, strings.Join(team.Permissions, ";"), strings.Join(team.Tags, ";")}
+				}
+				return []string{"name", "permissions", "tags"}, rows
+			},
+		})
+	}
+
 	table := tablecli.NewTable()
 	table.Headers = tablecli.Row{"Team", "Permissions", "Tags"}
 	table.LineSeparator = true
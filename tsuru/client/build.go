@@ -1,7 +1,6 @@
 package client
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -12,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -28,6 +28,7 @@ type AppBuild struct {
 	tag       string
 	fs        *pflag.FlagSet
 	filesOnly bool
+	listFiles bool
 }
 
 func (c *AppBuild) Flags() *pflag.FlagSet {
@@ -38,6 +39,7 @@ func (c *AppBuild) Flags() *pflag.FlagSet {
 
 		filesOnly := "Enables single file build into the root of the app's tree"
 		c.fs.BoolVarP(&c.filesOnly, "files-only", "f", false, filesOnly)
+		c.fs.BoolVar(&c.listFiles, "list-files", false, listFilesDesc)
 	}
 	return c.fs
 }
@@ -48,6 +50,11 @@ You can deploy this container image to the app later.
 
 Files specified in the ".tsuruignore" file are skipped - similar to ".gitignore".
 
+The --list-files flag lists the files that would be uploaded, with their sizes,
+the ignored files along with the pattern that excluded them and the compressed
+size of the archive, without contacting the server. The same list is displayed
+in dry-run mode.
+
 Examples:
   To build using app's platform build process (just sending source code or configurations):
     Uploading all files within the current directory
@@ -64,19 +71,24 @@ Examples:
 `
 	return &cmd.Info{
 		Name:  "app-build",
-		Usage: "[-a/--app <appname>] [-t/--tag <image_tag>] [-f/--files-only] <file-or-dir-1> [file-or-dir-2] ... [file-or-dir-n]",
+		Usage: "[-a/--app <appname>] [-t/--tag <image_tag>] [-f/--files-only] [--list-files] <file-or-dir-1> [file-or-dir-2] ... [file-or-dir-n]",
 		Desc:  desc,
 	}
 }
 
 func (c *AppBuild) Run(ctx *cmd.Context) error {
 	ctx.RawOutput()
-	if c.tag == "" {
+	if c.tag == "" && !c.listFiles {
 		return errors.New("you should provide one tag to build the image")
 	}
 	if len(ctx.Args) == 0 {
 		return errors.New("you should provide at least one file to build the image")
 	}
+	if c.listFiles || tsuruHTTP.IsDryRun() {
+		return previewArchive(ctx, !c.listFiles, func(opts ArchiveOptions) (archiveWriter, error) {
+			return archiveFiles(c.filesOnly, ctx.Args, opts)
+		})
+	}
 
 	appName, err := c.AppNameByFlag()
 	if err != nil {
@@ -92,27 +104,28 @@ func (c *AppBuild) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return err
 	}
-	body := safe.NewBuffer(nil)
-	request, err := http.NewRequest("POST", u, body)
+	request, err := http.NewRequest("POST", u, nil)
 	if err != nil {
 		return err
 	}
 	buf := safe.NewBuffer(nil)
 	respBody := prepareUploadStreams(ctx, buf)
 
-	var archive bytes.Buffer
-	err = Archive(&archive, c.filesOnly, ctx.Args, DefaultArchiveOptions(nil))
-	if err != nil {
-		return err
-	}
-
 	uploadCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	if err = buildRequestBodyWithProgress(uploadCtx, ctx.Stdout, request, buf, body, values, &archive); err != nil {
+	archive, err := archiveFiles(c.filesOnly, ctx.Args, DefaultArchiveOptions(nil))
+	if err != nil {
+		return err
+	}
+	upload, err := buildRequestBodyWithProgress(uploadCtx, ctx.Stdout, request, buf, values, archive)
+	if err != nil {
 		return err
 	}
 	resp, err := tsuruHTTP.AuthenticatedClient.Do(request)
+	if uploadErr := upload.Wait(); uploadErr != nil {
+		return uploadErr
+	}
 	if err != nil {
 		return err
 	}
@@ -130,53 +143,114 @@ func (c *AppBuild) Run(ctx *cmd.Context) error {
 	return cmd.ErrAbortCommand
 }
 
-func buildRequestBodyWithProgress(ctx context.Context, stdout io.Writer, request *http.Request, buf *safe.Buffer, body *safe.Buffer, values url.Values, archive io.Reader) error {
-	if archive == nil {
-		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-		_, err := body.WriteString(values.Encode())
-		return err
-	}
+// archiveWriter writes the archive uploaded by deploys and builds to w.
+type archiveWriter func(w io.Writer) error
 
-	writer := multipart.NewWriter(body)
-	for k := range values {
-		writer.WriteField(k, values.Get(k))
+// archiveFiles returns the archiveWriter archiving paths, see Archive. The
+// paths are checked beforehand, so missing files are reported before the
+// upload starts.
+func archiveFiles(filesOnly bool, paths []string, opts ArchiveOptions) (archiveWriter, error) {
+	for _, path := range paths {
+		if _, err := os.Lstat(path); err != nil {
+			return nil, err
+		}
 	}
+	return func(w io.Writer) error {
+		return Archive(w, filesOnly, paths, opts)
+	}, nil
+}
 
-	request.Header.Set("Content-Type", writer.FormDataContentType())
+// upload is the body of a request uploading an archive, produced while it's
+// sent so the archive is never held in memory.
+type upload struct {
+	body *io.PipeReader
+	sent int64
+	done chan struct{}
+	err  error
+}
 
-	f, err := writer.CreateFormFile("file", "archive.tar.gz")
-	if err != nil {
-		return err
+func (u *upload) Read(p []byte) (int, error) {
+	n, err := u.body.Read(p)
+	atomic.AddInt64(&u.sent, int64(n))
+	return n, err
+}
+
+func (u *upload) Close() error {
+	return u.body.Close()
+}
+
+func (u *upload) finished() bool {
+	select {
+	case <-u.done:
+		return true
+	default:
+		return false
 	}
+}
 
-	if _, err = io.Copy(f, archive); err != nil {
-		return err
+// Wait stops the upload, if the server responded before reading it all, and
+// returns the error that interrupted the archive, if any.
+func (u *upload) Wait() error {
+	if u == nil {
+		return nil
 	}
+	u.body.Close()
+	<-u.done
+	if errors.Is(u.err, io.ErrClosedPipe) {
+		return nil
+	}
+	return u.err
+}
 
-	if err = writer.Close(); err != nil {
-		return err
+// buildRequestBodyWithProgress sets the body of request: the values, form
+// encoded when there's no archive, or a multipart form with the values and
+// the archive. The archive is streamed through a pipe into the chunked
+// request, with its progress reported in stdout until the response starts
+// being written to buf. The returned upload must be waited for once the
+// request is done.
+func buildRequestBodyWithProgress(ctx context.Context, stdout io.Writer, request *http.Request, buf *safe.Buffer, values url.Values, archive archiveWriter) (*upload, error) {
+	if archive == nil {
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		body := values.Encode()
+		request.Body = io.NopCloser(strings.NewReader(body))
+		request.ContentLength = int64(len(body))
+		request.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(body)), nil
+		}
+		return nil, nil
 	}
 
-	fullSize := float64(body.Len())
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	u := &upload{body: pr, done: make(chan struct{})}
+
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	request.Body = u
+	request.ContentLength = -1
+	request.GetBody = nil
+
+	go func() {
+		defer close(u.done)
+		u.err = writeMultipart(writer, values, archive)
+		pw.CloseWithError(u.err)
+	}()
+
 	megabyte := 1024.0 * 1024.0
-	fmt.Fprintf(stdout, "Uploading files (%0.2fMB)... ", fullSize/megabyte)
+	fmt.Fprintf(stdout, "Uploading files (%0.2fMB)... ", 0.0)
 	count := 0
 	go func() {
 		t0 := time.Now()
 		lastTransferred := 0.0
 		for buf.Len() == 0 {
-			remaining := body.Len()
-			transferred := fullSize - float64(remaining)
+			transferred := float64(atomic.LoadInt64(&u.sent))
 			speed := ((transferred - lastTransferred) / megabyte) / (float64(time.Since(t0)) / float64(time.Second))
 			t0 = time.Now()
 			lastTransferred = transferred
-			percent := (transferred / fullSize) * 100.0
-			fmt.Fprintf(stdout, "\rUploading files (%0.2fMB)... %0.2f%%", fullSize/megabyte, percent)
-			if remaining > 0 {
-				fmt.Fprintf(stdout, " (%0.2fMB/s)", speed)
-			}
-			if remaining == 0 && buf.Len() == 0 {
-				fmt.Fprintf(stdout, " Processing%s", strings.Repeat(".", count))
+			fmt.Fprintf(stdout, "\rUploading files (%0.2fMB)... ", transferred/megabyte)
+			if !u.finished() {
+				fmt.Fprintf(stdout, "(%0.2fMB/s)", speed)
+			} else if buf.Len() == 0 {
+				fmt.Fprintf(stdout, "100.00%% Processing%s", strings.Repeat(".", count))
 				count++
 			}
 			select {
@@ -186,10 +260,26 @@ func buildRequestBodyWithProgress(ctx context.Context, stdout io.Writer, request
 			}
 		}
 	}()
-	return nil
+	return u, nil
+}
+
+func writeMultipart(writer *multipart.Writer, values url.Values, archive archiveWriter) error {
+	for k := range values {
+		if err := writer.WriteField(k, values.Get(k)); err != nil {
+			return err
+		}
+	}
+	f, err := writer.CreateFormFile("file", "archive.tar.gz")
+	if err != nil {
+		return err
+	}
+	if err = archive(f); err != nil {
+		return err
+	}
+	return writer.Close()
 }
 
-func buildWithContainerFile(resourceName, path string, filesOnly bool, files []string, stderr io.Writer) (string, io.Reader, error) {
+func buildWithContainerFile(resourceName, path string, filesOnly bool, files []string, opts ArchiveOptions) (string, archiveWriter, error) {
 	fi, err := os.Stat(path)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to stat the file %s: %w", path, err)
@@ -220,13 +310,12 @@ func buildWithContainerFile(resourceName, path string, filesOnly bool, files []s
 		files = []string{filepath.Dir(path)}
 	}
 
-	var buildContext bytes.Buffer
-	err = Archive(&buildContext, filesOnly, files, DefaultArchiveOptions(stderr))
+	archive, err := archiveFiles(filesOnly, files, opts)
 	if err != nil {
 		return "", nil, err
 	}
 
-	return string(containerfile), &buildContext, nil
+	return string(containerfile), archive, nil
 }
 
 func guessingContainerFile(resourceName, dir string) (string, error) {
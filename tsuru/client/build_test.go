@@ -7,7 +7,10 @@ package client
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
@@ -131,54 +134,119 @@ func (s *S) TestBuildRunWithoutTag(c *check.C) {
 	c.Assert(err.Error(), check.Equals, "you should provide one tag to build the image")
 }
 
+func (s *S) TestBuildRunListFiles(c *check.C) {
+	s.setupFakeTransport(unexpectedRequestTransport(c))
+	var stdout bytes.Buffer
+	command := AppBuild{}
+	err := command.Flags().Parse([]string{"-a", "myapp", "--list-files", "testdata/deploy"})
+	c.Assert(err, check.IsNil)
+	ctx := &cmd.Context{Stdout: &stdout, Stderr: io.Discard, Args: command.Flags().Args()}
+	err = command.Run(ctx)
+	c.Assert(err, check.IsNil)
+	c.Assert(stdout.String(), check.Matches, `(?s).*\| directory/file\.txt \| 4B   \|.*`)
+	c.Assert(stdout.String(), check.Matches, `(?s).*Total: 3 files, 29B .*`)
+}
+
 func (s *S) TestBuildRequestBodyWithProgressNilArchive(c *check.C) {
-	body := safe.NewBuffer(nil)
 	buf := safe.NewBuffer(nil)
-	request, _ := http.NewRequest("POST", "/apps/myapp/build", body)
+	request, _ := http.NewRequest("POST", "/apps/myapp/build", nil)
 	values := url.Values{"tag": []string{"mytag"}}
 	var stdout bytes.Buffer
 
-	err := buildRequestBodyWithProgress(context.Background(), &stdout, request, buf, body, values, nil)
+	upload, err := buildRequestBodyWithProgress(context.Background(), &stdout, request, buf, values, nil)
 	c.Assert(err, check.IsNil)
+	c.Assert(upload.Wait(), check.IsNil)
 	c.Assert(request.Header.Get("Content-Type"), check.Equals, "application/x-www-form-urlencoded")
-	c.Assert(body.String(), check.Equals, values.Encode())
+	body, err := io.ReadAll(request.Body)
+	c.Assert(err, check.IsNil)
+	c.Assert(string(body), check.Equals, values.Encode())
+	c.Assert(request.ContentLength, check.Equals, int64(len(body)))
+}
+
+func fakeArchive(content string) archiveWriter {
+	return func(w io.Writer) error {
+		_, err := io.WriteString(w, content)
+		return err
+	}
 }
 
 func (s *S) TestBuildRequestBodyWithProgressWithArchive(c *check.C) {
-	body := safe.NewBuffer(nil)
 	buf := safe.NewBuffer(nil)
-	request, _ := http.NewRequest("POST", "/apps/myapp/build", body)
+	request, _ := http.NewRequest("POST", "/apps/myapp/build", nil)
 	values := url.Values{"tag": []string{"mytag"}}
 	stdout := safe.NewBuffer(nil)
-	archive := bytes.NewBufferString("fake-archive-content")
 
 	ctx, cancel := context.WithCancel(context.Background())
-	err := buildRequestBodyWithProgress(ctx, stdout, request, buf, body, values, archive)
+	upload, err := buildRequestBodyWithProgress(ctx, stdout, request, buf, values, fakeArchive("fake-archive-content"))
+	c.Assert(err, check.IsNil)
+	c.Assert(request.ContentLength, check.Equals, int64(-1))
+	c.Assert(request.GetBody, check.IsNil)
+
+	_, params, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	c.Assert(err, check.IsNil)
+	reader := multipart.NewReader(request.Body, params["boundary"])
+	form, err := reader.ReadForm(1024)
+	c.Assert(err, check.IsNil)
+	c.Assert(form.Value["tag"], check.DeepEquals, []string{"mytag"})
+	c.Assert(form.File["file"], check.HasLen, 1)
+	c.Assert(form.File["file"][0].Filename, check.Equals, "archive.tar.gz")
+	file, err := form.File["file"][0].Open()
 	c.Assert(err, check.IsNil)
+	content, err := io.ReadAll(file)
+	c.Assert(err, check.IsNil)
+	c.Assert(string(content), check.Equals, "fake-archive-content")
+	c.Assert(upload.Wait(), check.IsNil)
 
 	// stop the progress goroutine before reading stdout
 	cancel()
 	time.Sleep(100 * time.Millisecond)
 
 	c.Assert(request.Header.Get("Content-Type"), check.Matches, "multipart/form-data; boundary=.*")
-	c.Assert(body.Len() > 0, check.Equals, true)
 	c.Assert(stdout.String(), check.Matches, `Uploading files \(\d+\.\d+MB\)\.\.\. .*`)
 }
 
+func (s *S) TestBuildRequestBodyWithProgressArchiveError(c *check.C) {
+	buf := safe.NewBuffer(nil)
+	request, _ := http.NewRequest("POST", "/apps/myapp/build", nil)
+	archiveErr := errors.New("unable to read file")
+	archive := func(w io.Writer) error {
+		io.WriteString(w, "partial")
+		return archiveErr
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	upload, err := buildRequestBodyWithProgress(ctx, io.Discard, request, buf, url.Values{}, archive)
+	c.Assert(err, check.IsNil)
+	_, err = io.ReadAll(request.Body)
+	c.Assert(err, check.Equals, archiveErr)
+	c.Assert(upload.Wait(), check.Equals, archiveErr)
+}
+
+func (s *S) TestBuildRequestBodyWithProgressUnreadBody(c *check.C) {
+	buf := safe.NewBuffer(nil)
+	request, _ := http.NewRequest("POST", "/apps/myapp/build", nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	upload, err := buildRequestBodyWithProgress(ctx, io.Discard, request, buf, url.Values{}, fakeArchive("fake-archive-content"))
+	c.Assert(err, check.IsNil)
+	c.Assert(upload.Wait(), check.IsNil)
+}
+
 func (s *S) TestBuildRequestBodyWithProgressContextCancel(c *check.C) {
-	body := safe.NewBuffer(nil)
 	buf := safe.NewBuffer(nil)
-	request, _ := http.NewRequest("POST", "/apps/myapp/build", body)
+	request, _ := http.NewRequest("POST", "/apps/myapp/build", nil)
 	values := url.Values{"tag": []string{"mytag"}}
-	archive := bytes.NewBufferString("fake-archive-content")
 
 	writerCh := make(chan struct{}, 100)
 	pw := &probeWriter{ch: writerCh}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
-	err := buildRequestBodyWithProgress(ctx, pw, request, buf, body, values, archive)
+	upload, err := buildRequestBodyWithProgress(ctx, pw, request, buf, values, fakeArchive("fake-archive-content"))
 	c.Assert(err, check.IsNil)
+	defer upload.Wait()
 
 	// wait for the goroutine to write at least once (proving it started)
 	select {
@@ -25,7 +25,6 @@ import (
 	"github.com/tsuru/tablecli"
 	tsuruClientApp "github.com/tsuru/tsuru-client/tsuru/app"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
-	"github.com/tsuru/tsuru-client/tsuru/cmd/standards"
 	"github.com/tsuru/tsuru-client/tsuru/formatter"
 	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
 )
@@ -145,9 +144,9 @@ func (c *CertificateUnset) Run(context *cmd.Context) error {
 
 type CertificateList struct {
 	tsuruClientApp.AppNameMixIn
-	fs   *pflag.FlagSet
-	raw  bool
-	json bool
+	fs     *pflag.FlagSet
+	raw    bool
+	output formatter.Output
 }
 
 func (c *CertificateList) Info() *cmd.Info {
@@ -162,8 +161,7 @@ func (c *CertificateList) Flags() *pflag.FlagSet {
 	if c.fs == nil {
 		c.fs = c.AppNameMixIn.Flags()
 		c.fs.BoolVarP(&c.raw, "raw", "r", false, "Display raw certificates")
-		c.fs.BoolVar(&c.json, standards.FlagJSON, false, "Display JSON format")
-
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -205,8 +203,8 @@ func (c *CertificateList) Run(context *cmd.Context) error {
 		return err
 	}
 
-	if c.json {
-		return c.renderJSON(context, appCerts)
+	if !c.output.IsTable() {
+		return c.renderOutput(context, appCerts)
 	}
 
 	if c.raw {
@@ -326,7 +324,7 @@ func formatTime(t time.Time) string {
 	return t.UTC().Format(time.RFC3339)
 }
 
-func (c *CertificateList) renderJSON(context *cmd.Context, appCerts appCertificate) error {
+func (c *CertificateList) renderOutput(context *cmd.Context, appCerts appCertificate) error {
 	type certificateJSONFriendly struct {
 		Router   string     `json:"router"`
 		Domain   string     `json:"domain"`
@@ -356,8 +354,30 @@ func (c *CertificateList) renderJSON(context *cmd.Context, appCerts appCertifica
 			data = append(data, item)
 		}
 	}
+	sort.Slice(data, func(i, j int) bool {
+		if data[i].Router == data[j].Router {
+			return data[i].Domain < data[j].Domain
+		}
+		return data[i].Router < data[j].Router
+	})
 
-	return formatter.JSON(context.Stdout, data)
+	return c.output.Print(context.Stdout, formatter.Printable{
+		Data: data,
+		Names: func() []string {
+			names := make([]string, len(data))
+			for i, item := range data {
+				names[i] = item.Domain
+			}
+			return names
+		},
+		Rows: func() ([]string, [][]string) {
+			rows := make([][]string, len(data))
+			for i, item := range data {
+				rows[i] = []string{item.Router, item.Domain, item.NotAfter}
+			}
+			return []string{"router", "domain", "notAfter"}, rows
+		},
+	})
 }
 
 func parseCert(data []byte) (*x509.Certificate, error) {
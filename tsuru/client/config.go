@@ -0,0 +1,276 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"github.com/tsuru/tablecli"
+	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	v2 "github.com/tsuru/tsuru-client/tsuru/cmd/v2"
+	"github.com/tsuru/tsuru-client/tsuru/formatter"
+	"github.com/tsuru/tsuru/exec"
+)
+
+type settingInfo struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Source      string `json:"source"`
+	Type        string `json:"type"`
+	Default     string `json:"default"`
+	Description string `json:"description"`
+}
+
+type ConfigList struct {
+	fs     *pflag.FlagSet
+	output formatter.Output
+}
+
+func (c *ConfigList) Info() *cmd.Info {
+	return &cmd.Info{
+		Name: "config-list",
+		Desc: `Lists the client settings along with their effective values.
+
+The source column tells where each value comes from: "env" for the
+TSURU_<SETTING> environment variables, "file" for ~/.tsuru/client.yaml and
+"default" for settings that are not set.`,
+	}
+}
+
+func (c *ConfigList) Flags() *pflag.FlagSet {
+	if c.fs == nil {
+		c.fs = pflag.NewFlagSet("config-list", pflag.ExitOnError)
+		c.output.AddFlags(c.fs)
+	}
+	return c.fs
+}
+
+func (c *ConfigList) Run(context *cmd.Context) error {
+	settings := make([]settingInfo, len(v2.Settings))
+	for i, s := range v2.Settings {
+		value, source := s.Value()
+		settings[i] = settingInfo{
+			Key:         s.Key,
+			Value:       value,
+			Source:      source,
+			Type:        s.Type,
+			Default:     s.Default(),
+			Description: s.Description,
+		}
+	}
+	if !c.output.IsTable() {
+		return c.output.Print(context.Stdout, formatter.Printable{
+			Data: settings,
+			Names: func() []string {
+				names := make([]string, len(settings))
+				for i, s := range settings {
+					names[i] = s.Key
+				}
+				return names
+			},
+			Rows: func() ([]string, [][]string) {
+				rows := make([][]string, len(settings))
+				for i, s := range settings {
+					rows[i] = []string{s.Key, s.Value, s.Source, s.Type, s.Default}
+				}
+				return []string{"key", "value", "source", "type", "default"}, rows
+			},
+		})
+	}
+	table := tablecli.NewTable()
+	headers := []string{"Key", "Value", "Source", "Type", "Default"}
+	if c.output.Wide() {
+		headers = append(headers, "Description")
+	}
+	table.Headers = tablecli.Row(headers)
+	for _, s := range settings {
+		row := []string{s.Key, s.Value, s.Source, s.Type, s.Default}
+		if c.output.Wide() {
+			row = append(row, s.Description)
+		}
+		table.AddRow(tablecli.Row(row))
+	}
+	context.Stdout.Write(table.Bytes())
+	return nil
+}
+
+type ConfigGet struct{}
+
+func (c *ConfigGet) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "config-get",
+		Usage:   "<setting>",
+		Desc:    `Displays the effective value of a client setting.`,
+		MinArgs: 1,
+		MaxArgs: 1,
+	}
+}
+
+func (c *ConfigGet) Run(context *cmd.Context) error {
+	s, err := v2.LookupSetting(context.Args[0])
+	if err != nil {
+		return err
+	}
+	value, _ := s.Value()
+	fmt.Fprintln(context.Stdout, value)
+	return nil
+}
+
+type ConfigSet struct{}
+
+func (c *ConfigSet) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:  "config-set",
+		Usage: "<setting> <value>",
+		Desc: `Sets a client setting in ~/.tsuru/client.yaml.
+
+The value is checked against the type of the setting, use "tsuru config list"
+to see the supported settings.`,
+		MinArgs: 2,
+		MaxArgs: 2,
+	}
+}
+
+func (c *ConfigSet) Run(context *cmd.Context) error {
+	s, err := v2.LookupSetting(context.Args[0])
+	if err != nil {
+		return err
+	}
+	if err = v2.SetSetting(s.Key, context.Args[1]); err != nil {
+		return err
+	}
+	fmt.Fprintf(context.Stdout, "Setting %q updated.\n", s.Key)
+	warnSettingEnv(context, s)
+	return nil
+}
+
+type ConfigUnset struct{}
+
+func (c *ConfigUnset) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "config-unset",
+		Usage:   "<setting>",
+		Desc:    `Removes a client setting from ~/.tsuru/client.yaml, restoring its default value.`,
+		MinArgs: 1,
+		MaxArgs: 1,
+	}
+}
+
+func (c *ConfigUnset) Run(context *cmd.Context) error {
+	s, err := v2.LookupSetting(context.Args[0])
+	if err != nil {
+		return err
+	}
+	if err = v2.UnsetSetting(s.Key); err != nil {
+		return err
+	}
+	fmt.Fprintf(context.Stdout, "Setting %q removed.\n", s.Key)
+	warnSettingEnv(context, s)
+	return nil
+}
+
+func warnSettingEnv(context *cmd.Context, s v2.Setting) {
+	if os.Getenv(s.EnvName()) != "" {
+		fmt.Fprintf(context.Stderr, "Warning: the %s environment variable is set and takes precedence over the configuration file.\n", s.EnvName())
+	}
+}
+
+type ConfigEdit struct{}
+
+func (c *ConfigEdit) Info() *cmd.Info {
+	return &cmd.Info{
+		Name: "config-edit",
+		Desc: `Opens ~/.tsuru/client.yaml in the editor set in the VISUAL or EDITOR
+environment variables.
+
+The file is only saved when it is valid. Otherwise, the edited copy is kept and
+its path is displayed so the changes are not lost.`,
+	}
+}
+
+func (c *ConfigEdit) Run(context *cmd.Context) error {
+	path := v2.ConfigFile()
+	original, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	content := original
+	if len(bytes.TrimSpace(content)) == 0 {
+		content = configTemplate()
+	}
+	tmpFile, err := os.CreateTemp("", "tsuru-client-*.yaml")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+	_, err = tmpFile.Write(content)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	editor := strings.Fields(configEditor())
+	err = Executor().Execute(exec.ExecuteOptions{
+		Cmd:    editor[0],
+		Args:   append(editor[1:], tmpPath),
+		Stdin:  context.Stdin,
+		Stdout: context.Stdout,
+		Stderr: context.Stderr,
+	})
+	if err != nil {
+		os.Remove(tmpPath)
+		return errors.Wrapf(err, "unable to run editor %q", editor[0])
+	}
+	edited, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(edited, content) {
+		os.Remove(tmpPath)
+		fmt.Fprintln(context.Stdout, "Edit cancelled, no changes made.")
+		return nil
+	}
+	if err = v2.ValidateConfig(edited); err != nil {
+		return errors.Errorf("%s\nThe configuration file was not changed, your changes were kept in %s", err, tmpPath)
+	}
+	if err = v2.WriteConfig(edited); err != nil {
+		return err
+	}
+	os.Remove(tmpPath)
+	fmt.Fprintf(context.Stdout, "Configuration saved to %s.\n", path)
+	return nil
+}
+
+func configEditor() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// configTemplate is the content offered when editing an empty configuration
+// file, listing the supported settings as comments.
+func configTemplate() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("# tsuru client settings, see \"tsuru config list\" for the effective values.\n#\n")
+	for _, s := range v2.Settings {
+		fmt.Fprintf(&buf, "# %s (%s): %s\n# %s: %s\n#\n", s.Key, s.Type, s.Description, s.Key, s.Default())
+	}
+	buf.WriteString("# aliases: commands run by custom names, as in \"tsuru dp\".\n# aliases:\n#   dp: app deploy -a myapp .\n")
+	return buf.Bytes()
+}
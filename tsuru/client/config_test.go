@@ -0,0 +1,165 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	v2 "github.com/tsuru/tsuru-client/tsuru/cmd/v2"
+	"github.com/tsuru/tsuru/exec"
+	check "gopkg.in/check.v1"
+)
+
+// editorExecutor simulates an editor replacing the edited file with content.
+type editorExecutor struct {
+	content string
+	path    string
+}
+
+func (e *editorExecutor) Execute(opts exec.ExecuteOptions) error {
+	e.path = opts.Args[len(opts.Args)-1]
+	return os.WriteFile(e.path, []byte(e.content), 0600)
+}
+
+func setupConfigDir(c *check.C) func() {
+	originalDir := v2.TsuruConfigDir
+	v2.TsuruConfigDir = c.MkDir()
+	os.Unsetenv("TSURU_TAB_WRITER")
+	return func() { v2.TsuruConfigDir = originalDir }
+}
+
+func (s *S) TestConfigSetGetAndUnset(c *check.C) {
+	defer setupConfigDir(c)()
+	var stdout, stderr bytes.Buffer
+	context := &cmd.Context{Args: []string{"tab-writer", "true"}, Stdout: &stdout, Stderr: &stderr}
+	err := (&ConfigSet{}).Run(context)
+	c.Assert(err, check.IsNil)
+	c.Assert(stdout.String(), check.Equals, "Setting \"tab-writer\" updated.\n")
+	c.Assert(stderr.String(), check.Equals, "")
+
+	stdout.Reset()
+	context.Args = []string{"tab-writer"}
+	err = (&ConfigGet{}).Run(context)
+	c.Assert(err, check.IsNil)
+	c.Assert(stdout.String(), check.Equals, "true\n")
+
+	stdout.Reset()
+	err = (&ConfigUnset{}).Run(context)
+	c.Assert(err, check.IsNil)
+	c.Assert(stdout.String(), check.Equals, "Setting \"tab-writer\" removed.\n")
+	data, err := os.ReadFile(v2.ConfigFile())
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "")
+}
+
+func (s *S) TestConfigSetInvalidValue(c *check.C) {
+	defer setupConfigDir(c)()
+	context := &cmd.Context{Args: []string{"tab-writer", "often"}, Stdout: &bytes.Buffer{}}
+	err := (&ConfigSet{}).Run(context)
+	c.Assert(err, check.ErrorMatches, `invalid value "often" for tab-writer: must be true or false`)
+	context.Args = []string{"tab-writter", "true"}
+	err = (&ConfigSet{}).Run(context)
+	c.Assert(err, check.ErrorMatches, `unknown setting "tab-writter", .*`)
+}
+
+func (s *S) TestConfigSetWarnsAboutEnvironment(c *check.C) {
+	defer setupConfigDir(c)()
+	os.Setenv("TSURU_TAB_WRITER", "false")
+	defer os.Unsetenv("TSURU_TAB_WRITER")
+	var stderr bytes.Buffer
+	context := &cmd.Context{Args: []string{"tab-writer", "true"}, Stdout: &bytes.Buffer{}, Stderr: &stderr}
+	err := (&ConfigSet{}).Run(context)
+	c.Assert(err, check.IsNil)
+	c.Assert(stderr.String(), check.Equals, "Warning: the TSURU_TAB_WRITER environment variable is set and takes precedence over the configuration file.\n")
+}
+
+func (s *S) TestConfigListJSON(c *check.C) {
+	defer setupConfigDir(c)()
+	err := v2.SetSetting("tab-writer", "true")
+	c.Assert(err, check.IsNil)
+	var stdout bytes.Buffer
+	command := ConfigList{}
+	command.Flags().Parse([]string{"-o", "json"})
+	err = command.Run(&cmd.Context{Stdout: &stdout})
+	c.Assert(err, check.IsNil)
+	var settings []settingInfo
+	err = json.Unmarshal(stdout.Bytes(), &settings)
+	c.Assert(err, check.IsNil)
+	c.Assert(settings, check.HasLen, len(v2.Settings))
+	for _, setting := range settings {
+		if setting.Key == "tab-writer" {
+			c.Assert(setting, check.DeepEquals, settingInfo{
+				Key:         "tab-writer",
+				Value:       "true",
+				Source:      "file",
+				Type:        "bool",
+				Default:     "false",
+				Description: setting.Description,
+			})
+		}
+	}
+}
+
+func (s *S) TestConfigListTable(c *check.C) {
+	defer setupConfigDir(c)()
+	var stdout bytes.Buffer
+	command := ConfigList{}
+	command.Flags().Parse([]string{})
+	err := command.Run(&cmd.Context{Stdout: &stdout})
+	c.Assert(err, check.IsNil)
+	c.Assert(stdout.String(), check.Matches, `(?s).*Key.*Value.*Source.*Type.*Default.*`)
+	c.Assert(stdout.String(), check.Matches, `(?s).*\| tab-writer-padding +\| 2 +\| default +\| int +\| 2 +\|.*`)
+}
+
+func (s *S) TestConfigEdit(c *check.C) {
+	defer setupConfigDir(c)()
+	editor := &editorExecutor{content: "tab-writer: true\n"}
+	Execut = editor
+	defer func() { Execut = nil }()
+	var stdout bytes.Buffer
+	err := (&ConfigEdit{}).Run(&cmd.Context{Stdout: &stdout, Stderr: &bytes.Buffer{}})
+	c.Assert(err, check.IsNil)
+	c.Assert(stdout.String(), check.Equals, "Configuration saved to "+v2.ConfigFile()+".\n")
+	data, err := os.ReadFile(v2.ConfigFile())
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "tab-writer: true\n")
+	_, err = os.Stat(editor.path)
+	c.Assert(os.IsNotExist(err), check.Equals, true)
+}
+
+func (s *S) TestConfigEditInvalidKeepsChanges(c *check.C) {
+	defer setupConfigDir(c)()
+	err := os.WriteFile(v2.ConfigFile(), []byte("tab-writer: true\n"), 0600)
+	c.Assert(err, check.IsNil)
+	editor := &editorExecutor{content: "tab-writer: sometimes\n"}
+	Execut = editor
+	defer func() { Execut = nil }()
+	err = (&ConfigEdit{}).Run(&cmd.Context{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}})
+	c.Assert(err, check.NotNil)
+	c.Assert(strings.HasSuffix(err.Error(), "your changes were kept in "+editor.path), check.Equals, true)
+	defer os.Remove(editor.path)
+	data, err := os.ReadFile(v2.ConfigFile())
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "tab-writer: true\n")
+	data, err = os.ReadFile(editor.path)
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "tab-writer: sometimes\n")
+}
+
+func (s *S) TestConfigEditUnchanged(c *check.C) {
+	defer setupConfigDir(c)()
+	err := os.WriteFile(v2.ConfigFile(), []byte("tab-writer: true\n"), 0600)
+	c.Assert(err, check.IsNil)
+	Execut = &editorExecutor{content: "tab-writer: true\n"}
+	defer func() { Execut = nil }()
+	var stdout bytes.Buffer
+	err = (&ConfigEdit{}).Run(&cmd.Context{Stdout: &stdout, Stderr: &bytes.Buffer{}})
+	c.Assert(err, check.IsNil)
+	c.Assert(stdout.String(), check.Equals, "Edit cancelled, no changes made.\n")
+}
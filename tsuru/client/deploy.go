@@ -5,7 +5,6 @@
 package client
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -17,6 +16,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/pflag"
@@ -29,6 +29,7 @@ import (
 	v2 "github.com/tsuru/tsuru-client/tsuru/cmd/v2"
 	"github.com/tsuru/tsuru-client/tsuru/formatter"
 	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
+	"github.com/tsuru/tsuru-client/tsuru/project"
 	tsuruapp "github.com/tsuru/tsuru/app"
 	tsuruIo "github.com/tsuru/tsuru/io"
 	"github.com/tsuru/tsuru/safe"
@@ -52,7 +53,7 @@ type AppDeployList struct {
 	tsuruClientApp.AppNameMixIn
 
 	flagsApplied bool
-	json         bool
+	output       formatter.Output
 }
 
 func (c *AppDeployList) Info() *cmd.Info {
@@ -66,7 +67,7 @@ func (c *AppDeployList) Info() *cmd.Info {
 func (c *AppDeployList) Flags() *pflag.FlagSet {
 	fs := c.AppNameMixIn.Flags()
 	if !c.flagsApplied {
-		fs.BoolVar(&c.json, standards.FlagJSON, false, "Show JSON")
+		c.output.AddFlags(fs)
 
 		c.flagsApplied = true
 	}
@@ -106,8 +107,33 @@ func (c *AppDeployList) Run(context *cmd.Context) error {
 	}
 	sort.Sort(sort.Reverse(deployList(deploys)))
 
-	if c.json {
-		return formatter.JSON(context.Stdout, deploys)
+	if !c.output.IsTable() {
+		return c.output.Print(context.Stdout, formatter.Printable{
+			Data: deploys,
+			Names: func() []string {
+				names := make([]string, len(deploys))
+				for i, deploy := range deploys {
+					names[i] = deploy.Image
+				}
+				return names
+			},
+			Rows: func() ([]string, [][]string) {
+				rows := make([][]string, len(deploys))
+				for i, deploy := range deploys {
+					rows[i] = []string{
+						deploy.Image,
+						deploy.Origin,
+						deploy.Commit,
+						deploy.User,
+						deploy.Timestamp.Format(time.RFC3339),
+						deploy.Duration.String(),
+						strconv.FormatBool(deploy.CanRollback),
+						deploy.Error,
+					}
+				}
+				return []string{"image", "origin", "commit", "user", "timestamp", "duration", "canRollback", "error"}, rows
+			},
+		})
 	}
 
 	table := tablecli.NewTable()
@@ -150,6 +176,7 @@ type AppDeploy struct {
 	m          sync.Mutex
 	deployVersionArgs
 	filesOnly bool
+	listFiles bool
 }
 
 func (c *AppDeploy) Flags() *pflag.FlagSet {
@@ -165,6 +192,7 @@ func (c *AppDeploy) Flags() *pflag.FlagSet {
 		c.fs.BoolVarP(&c.filesOnly, "files-only", "f", false, filesOnly)
 		c.flags(c.fs)
 		c.fs.StringVar(&c.dockerfile, "dockerfile", "", "Container file")
+		c.fs.BoolVar(&c.listFiles, "list-files", false, listFilesDesc)
 	}
 	return c.fs
 }
@@ -172,11 +200,15 @@ func (c *AppDeploy) Flags() *pflag.FlagSet {
 func (c *AppDeploy) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:  "app-deploy",
-		Usage: "[--app <app name>] [--image <container image name>] [--dockerfile <container image file>] [--message <message>] [--files-only] [--new-version] [--override-old-versions] [file-or-dir ...]",
+		Usage: "[--app <app name>] [--image <container image name>] [--dockerfile <container image file>] [--message <message>] [--files-only] [--new-version] [--override-old-versions] [--list-files] [file-or-dir ...]",
 		Desc: `Deploy the source code and/or configurations to the application on Tsuru.
 
 Files specified in the ".tsuruignore" file are skipped - similar to ".gitignore". It also honors ".dockerignore" file if deploying with container file (--dockerfile).
 
+When neither files, image nor container file are given, the paths listed in "deploy.paths" of the project's ".tsuru.yaml" file are deployed. The app may also be set there, under "app".
+
+The --list-files flag lists the files that would be uploaded, with their sizes, the ignored files along with the pattern that excluded them and the compressed size of the archive, without contacting the server. The same list is displayed in dry-run mode.
+
 Examples:
   To deploy using app's platform build process (just sending source code and/or configurations):
     Uploading all files within the current directory
@@ -250,6 +282,14 @@ func ensureAppExists(appName string) error {
 func (c *AppDeploy) Run(ctx *cmd.Context) error {
 	ctx.RawOutput()
 
+	if c.image == "" && c.dockerfile == "" && len(ctx.Args) == 0 {
+		paths, err := projectDeployPaths()
+		if err != nil {
+			return err
+		}
+		ctx.Args = paths
+	}
+
 	if c.image == "" && c.dockerfile == "" && len(ctx.Args) == 0 {
 		return errors.New("you should provide at least one file, Docker image name or Dockerfile to deploy")
 	}
@@ -262,10 +302,25 @@ func (c *AppDeploy) Run(ctx *cmd.Context) error {
 		return errors.New("you can't deploy container image and container file at same time")
 	}
 
+	if c.listFiles && c.image != "" {
+		return errors.New("there are no files to list when deploying a container image")
+	}
+
 	appName, err := c.AppNameByFlag()
 	if err != nil {
 		return err
 	}
+
+	if c.listFiles || (tsuruHTTP.IsDryRun() && c.image == "") {
+		return previewArchive(ctx, !c.listFiles, func(opts ArchiveOptions) (archiveWriter, error) {
+			if c.dockerfile != "" {
+				_, archive, err := buildWithContainerFile(appName, c.dockerfile, c.filesOnly, ctx.Args, opts)
+				return archive, err
+			}
+			return archiveFiles(c.filesOnly, ctx.Args, opts)
+		})
+	}
+
 	err = ensureAppExists(appName)
 	if err != nil {
 		return err
@@ -290,8 +345,7 @@ func (c *AppDeploy) Run(ctx *cmd.Context) error {
 		return err
 	}
 
-	body := safe.NewBuffer(nil)
-	request, err := http.NewRequest("POST", u, body)
+	request, err := http.NewRequest("POST", u, nil)
 	if err != nil {
 		return err
 	}
@@ -302,7 +356,7 @@ func (c *AppDeploy) Run(ctx *cmd.Context) error {
 	respBody := prepareUploadStreams(ctx, buf)
 	c.m.Unlock()
 
-	var archive io.Reader
+	var archive archiveWriter
 
 	if c.image != "" {
 		fmt.Fprintln(ctx.Stdout, "Deploying container image...")
@@ -313,7 +367,7 @@ func (c *AppDeploy) Run(ctx *cmd.Context) error {
 		fmt.Fprintln(ctx.Stdout, "Deploying with Dockerfile...")
 
 		var dockerfile string
-		dockerfile, archive, err = buildWithContainerFile(appName, c.dockerfile, c.filesOnly, ctx.Args, nil)
+		dockerfile, archive, err = buildWithContainerFile(appName, c.dockerfile, c.filesOnly, ctx.Args, DefaultArchiveOptions(nil))
 		if err != nil {
 			return err
 		}
@@ -324,24 +378,26 @@ func (c *AppDeploy) Run(ctx *cmd.Context) error {
 	if c.image == "" && c.dockerfile == "" {
 		fmt.Fprintln(ctx.Stdout, "Deploying using app's platform...")
 
-		var buffer bytes.Buffer
-		err = Archive(&buffer, c.filesOnly, ctx.Args, DefaultArchiveOptions(nil))
+		archive, err = archiveFiles(c.filesOnly, ctx.Args, DefaultArchiveOptions(nil))
 		if err != nil {
 			return err
 		}
-
-		archive = &buffer
 	}
 
 	uploadCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	if err = buildRequestBodyWithProgress(uploadCtx, ctx.Stdout, request, buf, body, values, archive); err != nil {
+	upload, err := buildRequestBodyWithProgress(uploadCtx, ctx.Stdout, request, buf, values, archive)
+	if err != nil {
 		return err
 	}
 
 	c.m.Lock()
 	resp, err := tsuruHTTP.AuthenticatedClient.Do(request)
+	if uploadErr := upload.Wait(); uploadErr != nil {
+		c.m.Unlock()
+		return uploadErr
+	}
 	if err != nil {
 		c.m.Unlock()
 		return err
@@ -377,6 +433,16 @@ func (c *AppDeploy) Run(ctx *cmd.Context) error {
 	return cmd.ErrAbortCommand
 }
 
+// projectDeployPaths returns the default deploy paths set in the project
+// context file, if any.
+func projectDeployPaths() ([]string, error) {
+	projectCtx, err := project.Current()
+	if err != nil || projectCtx == nil {
+		return nil, err
+	}
+	return projectCtx.DeployPaths()
+}
+
 func (c *AppDeploy) Cancel(ctx cmd.Context) error {
 	apiClient, err := tsuruHTTP.TsuruClientFromEnvironment()
 	if err != nil {
@@ -562,6 +628,7 @@ type JobDeploy struct {
 	message    string
 	dockerfile string
 	eventID    string
+	listFiles  bool
 	fs         *pflag.FlagSet
 	m          sync.Mutex
 }
@@ -577,6 +644,7 @@ func (c *JobDeploy) Flags() *pflag.FlagSet {
 		message := "A message describing this deploy"
 		c.fs.StringVarP(&c.message, "message", "m", "", message)
 		c.fs.StringVar(&c.dockerfile, "dockerfile", "", "Container file")
+		c.fs.BoolVar(&c.listFiles, "list-files", false, listFilesDesc)
 	}
 	return c.fs
 }
@@ -584,11 +652,13 @@ func (c *JobDeploy) Flags() *pflag.FlagSet {
 func (c *JobDeploy) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:  "job-deploy",
-		Usage: "[--job <job name>] [--image <container image name>] [--dockerfile <container image file>] [--message <message>]",
+		Usage: "[--job <job name>] [--image <container image name>] [--dockerfile <container image file>] [--message <message>] [--list-files]",
 		Desc: `Deploy the source code and/or configurations to a Job on Tsuru.
 
 Files specified in the ".tsuruignore" file are skipped - similar to ".gitignore". It also honors ".dockerignore" file if deploying with container file (--dockerfile).
 
+The --list-files flag lists the files of the container build context that would be uploaded, with their sizes, the ignored files along with the pattern that excluded them and the compressed size of the archive, without contacting the server. The same list is displayed in dry-run mode.
+
 Examples:
   To deploy using a container image:
     $ tsuru job deploy -j <JOB> --image registry.example.com/my-company/my-job:v42
@@ -624,6 +694,17 @@ Use the --job/-j flag to specify it`)
 		return errors.New("you can't deploy container image and container file at same time")
 	}
 
+	if c.listFiles && c.image != "" {
+		return errors.New("there are no files to list when deploying a container image")
+	}
+
+	if c.listFiles || (tsuruHTTP.IsDryRun() && c.image == "") {
+		return previewArchive(ctx, !c.listFiles, func(opts ArchiveOptions) (archiveWriter, error) {
+			_, archive, err := buildWithContainerFile(c.jobName, c.dockerfile, false, ctx.Args, opts)
+			return archive, err
+		})
+	}
+
 	values := url.Values{}
 
 	origin := "job-deploy"
@@ -641,8 +722,7 @@ Use the --job/-j flag to specify it`)
 		return err
 	}
 
-	body := safe.NewBuffer(nil)
-	request, err := http.NewRequest("POST", u, body)
+	request, err := http.NewRequest("POST", u, nil)
 	if err != nil {
 		return err
 	}
@@ -653,7 +733,7 @@ Use the --job/-j flag to specify it`)
 	respBody := prepareUploadStreams(ctx, buf)
 	c.m.Unlock()
 
-	var archive io.Reader
+	var archive archiveWriter
 
 	if c.image != "" {
 		fmt.Fprintln(ctx.Stdout, "Deploying container image...")
@@ -664,7 +744,7 @@ Use the --job/-j flag to specify it`)
 		fmt.Fprintln(ctx.Stdout, "Deploying with Dockerfile...")
 
 		var dockerfile string
-		dockerfile, archive, err = buildWithContainerFile(c.jobName, c.dockerfile, false, ctx.Args, nil)
+		dockerfile, archive, err = buildWithContainerFile(c.jobName, c.dockerfile, false, ctx.Args, DefaultArchiveOptions(nil))
 		if err != nil {
 			return err
 		}
@@ -675,12 +755,17 @@ Use the --job/-j flag to specify it`)
 	uploadCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	if err = buildRequestBodyWithProgress(uploadCtx, ctx.Stdout, request, buf, body, values, archive); err != nil {
+	upload, err := buildRequestBodyWithProgress(uploadCtx, ctx.Stdout, request, buf, values, archive)
+	if err != nil {
 		return err
 	}
 
 	c.m.Lock()
 	resp, err := tsuruHTTP.AuthenticatedClient.Do(request)
+	if uploadErr := upload.Wait(); uploadErr != nil {
+		c.m.Unlock()
+		return uploadErr
+	}
 	if err != nil {
 		c.m.Unlock()
 		return err
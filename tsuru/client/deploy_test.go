@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -17,6 +18,7 @@ import (
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
 	"github.com/tsuru/tsuru-client/tsuru/cmd/cmdtest"
 	"github.com/tsuru/tsuru-client/tsuru/formatter"
+	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
 	tsuruIo "github.com/tsuru/tsuru/io"
 	"gopkg.in/check.v1"
 )
@@ -75,6 +77,44 @@ func (s *S) TestDeployRun(c *check.C) {
 	c.Assert(err, check.IsNil)
 }
 
+func (s *S) TestDeployRunWithProjectContext(c *check.C) {
+	wd, err := os.Getwd()
+	c.Assert(err, check.IsNil)
+	defer os.Chdir(wd)
+	root := c.MkDir()
+	c.Assert(os.Mkdir(filepath.Join(root, "src"), 0700), check.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(root, "src", "main.go"), []byte("package main"), 0600), check.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(root, ".tsuru.yaml"), []byte("app: secret\ndeploy:\n  paths: [src]\n"), 0600), check.IsNil)
+	c.Assert(os.Chdir(root), check.IsNil)
+
+	var buf bytes.Buffer
+	err = Archive(&buf, false, []string{"src"}, DefaultArchiveOptions(io.Discard))
+	c.Assert(err, check.IsNil)
+	trans := cmdtest.ConditionalTransport{
+		Transport: cmdtest.Transport{Message: "deploy worked\nOK\n", Status: http.StatusOK},
+		CondFunc: func(req *http.Request) bool {
+			if req.Body != nil {
+				defer req.Body.Close()
+			}
+			file, _, transErr := req.FormFile("file")
+			c.Assert(transErr, check.IsNil)
+			content, transErr := io.ReadAll(file)
+			c.Assert(transErr, check.IsNil)
+			c.Assert(content, check.DeepEquals, buf.Bytes())
+			return req.Method == "POST" && strings.HasSuffix(req.URL.Path, "/apps/secret/deploy")
+		},
+	}
+	s.setupFakeTransport(deployWithAppInfoTransport("secret", trans))
+	context := cmd.Context{
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	command := AppDeploy{}
+	command.Flags().Parse([]string{})
+	err = command.Run(&context)
+	c.Assert(err, check.IsNil)
+}
+
 type slowReader struct {
 	io.ReadCloser
 	Latency time.Duration
@@ -274,6 +314,63 @@ func (s *S) TestDeployRunFileNotFound(c *check.C) {
 	c.Assert(err, check.NotNil)
 }
 
+func unexpectedRequestTransport(c *check.C) http.RoundTripper {
+	return &cmdtest.ConditionalTransport{
+		Transport: cmdtest.Transport{Status: http.StatusInternalServerError},
+		CondFunc: func(req *http.Request) bool {
+			c.Errorf("unexpected request: %s %s", req.Method, req.URL)
+			return false
+		},
+	}
+}
+
+func (s *S) TestDeployRunListFiles(c *check.C) {
+	workingDir, err := os.Getwd()
+	c.Assert(err, check.IsNil)
+	defer os.Chdir(workingDir)
+	err = os.Chdir(filepath.Join(workingDir, "testdata", "deploy2"))
+	c.Assert(err, check.IsNil)
+
+	s.setupFakeTransport(unexpectedRequestTransport(c))
+	var stdout, stderr bytes.Buffer
+	command := AppDeploy{}
+	err = command.Flags().Parse([]string{"-a", "secret", "--list-files", "."})
+	c.Assert(err, check.IsNil)
+	ctx := &cmd.Context{Stdout: &stdout, Stderr: &stderr, Args: command.Flags().Args()}
+	err = command.Run(ctx)
+	c.Assert(err, check.IsNil)
+	c.Assert(stdout.String(), check.Matches, `(?s)Included files:\n.*\| \.tsuruignore \| 5B   \|.*`)
+	c.Assert(stdout.String(), check.Matches, `(?s).*Ignored files:\n.*\| file1\.txt +\| 19B +\| \.tsuruignore: \*\.txt \|.*`)
+	c.Assert(stdout.String(), check.Matches, `(?s).*\| directory/file\.txt +\| 4B +\| \.tsuruignore: \*\.txt \|.*`)
+	c.Assert(stdout.String(), check.Matches, `(?s).*Total: 1 files, 5B \(\d+B compressed\)\n`)
+	c.Assert(stderr.String(), check.Not(check.Matches), `(?s).*skipping it.*`)
+}
+
+func (s *S) TestDeployRunDryRunListsFiles(c *check.C) {
+	os.Setenv("TSURU_DRY_RUN", "true")
+	defer os.Unsetenv("TSURU_DRY_RUN")
+	s.setupFakeTransport(unexpectedRequestTransport(c))
+	var stdout bytes.Buffer
+	command := AppDeploy{}
+	err := command.Flags().Parse([]string{"-a", "secret", "testdata/deploy"})
+	c.Assert(err, check.IsNil)
+	ctx := &cmd.Context{Stdout: &stdout, Stderr: io.Discard, Args: command.Flags().Args()}
+	err = command.Run(ctx)
+	c.Assert(err, check.Equals, tsuruHTTP.ErrDryRun)
+	c.Assert(stdout.String(), check.Matches, `(?s).*\| file1\.txt +\| 19B +\|.*`)
+	c.Assert(stdout.String(), check.Matches, `(?s).*Total: 3 files, 29B .*`)
+}
+
+func (s *S) TestDeployRunListFilesWithImage(c *check.C) {
+	s.setupFakeTransport(unexpectedRequestTransport(c))
+	command := AppDeploy{}
+	err := command.Flags().Parse([]string{"-a", "secret", "--list-files", "-i", "registry.example.com/app:v1"})
+	c.Assert(err, check.IsNil)
+	ctx := &cmd.Context{Stdout: io.Discard, Stderr: io.Discard, Args: command.Flags().Args()}
+	err = command.Run(ctx)
+	c.Assert(err, check.ErrorMatches, "there are no files to list when deploying a container image")
+}
+
 func (s *S) TestDeployRunWithoutArgsAndImage(c *check.C) {
 	command := AppDeploy{}
 	err := command.Flags().Parse([]string{"-a", "secret"})
@@ -787,6 +884,20 @@ func (s *S) TestJobDeployRunFileNotFound(c *check.C) {
 	c.Assert(err, check.NotNil)
 }
 
+func (s *S) TestJobDeployRunListFiles(c *check.C) {
+	s.setupFakeTransport(unexpectedRequestTransport(c))
+	var stdout bytes.Buffer
+	command := JobDeploy{}
+	err := command.Flags().Parse([]string{"-j", "my-job", "--dockerfile", "./testdata/deploy5/", "--list-files"})
+	c.Assert(err, check.IsNil)
+	ctx := &cmd.Context{Stdout: &stdout, Stderr: io.Discard, Args: command.Flags().Args()}
+	err = command.Run(ctx)
+	c.Assert(err, check.IsNil)
+	c.Assert(stdout.String(), check.Matches, `(?s).*\| Dockerfile \| 57B  \|.*`)
+	c.Assert(stdout.String(), check.Matches, `(?s).*\| job\.sh     \| 28B  \|.*`)
+	c.Assert(stdout.String(), check.Matches, `(?s).*Total: 2 files, 85B .*`)
+}
+
 func (s *S) TestJobDeployRunWithoutArgsAndImage(c *check.C) {
 	command := JobDeploy{}
 	err := command.Flags().Parse([]string{"-j", "my-job"})
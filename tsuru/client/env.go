@@ -23,6 +23,7 @@ import (
 	"github.com/tsuru/tsuru-client/tsuru/cmd/standards"
 	"github.com/tsuru/tsuru-client/tsuru/formatter"
 	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
+	"github.com/tsuru/tsuru-client/tsuru/project"
 	apiTypes "github.com/tsuru/tsuru/types/api"
 )
 
@@ -45,8 +46,8 @@ type EnvGet struct {
 	appName string
 	jobName string
 
-	fs   *pflag.FlagSet
-	json bool
+	fs     *pflag.FlagSet
+	output formatter.Output
 }
 
 func (c *EnvGet) Flags() *pflag.FlagSet {
@@ -55,7 +56,7 @@ func (c *EnvGet) Flags() *pflag.FlagSet {
 
 		c.fs.StringVarP(&c.appName, standards.FlagApp, standards.ShortFlagApp, "", "The name of the app.")
 		c.fs.StringVarP(&c.jobName, standards.FlagJob, standards.ShortFlagJob, "", "The name of the job.")
-		c.fs.BoolVar(&c.json, standards.FlagJSON, false, "Display JSON format")
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -71,7 +72,8 @@ func (c *EnvGet) Info() *cmd.Info {
 func (c *EnvGet) Run(context *cmd.Context) error {
 	context.RawOutput()
 
-	err := checkAppAndJobInputs(c.appName, c.jobName)
+	var err error
+	c.appName, c.jobName, err = resolveAppOrJob(c.appName, c.jobName)
 	if err != nil {
 		return err
 	}
@@ -87,8 +89,8 @@ func (c *EnvGet) Run(context *cmd.Context) error {
 		return err
 	}
 
-	if c.json {
-		return c.renderJSON(context, variables)
+	if !c.output.IsTable() {
+		return c.renderOutput(context, variables)
 	}
 
 	formatted := make([]string, 0, len(variables))
@@ -118,7 +120,7 @@ func (c *EnvGet) Run(context *cmd.Context) error {
 	return nil
 }
 
-func (c *EnvGet) renderJSON(context *cmd.Context, variables []map[string]interface{}) error {
+func (c *EnvGet) renderOutput(context *cmd.Context, variables []map[string]interface{}) error {
 	type envJSON struct {
 		Name      string `json:"name"`
 		Value     string `json:"value"`
@@ -144,7 +146,23 @@ func (c *EnvGet) renderJSON(context *cmd.Context, variables []map[string]interfa
 		})
 	}
 
-	return formatter.JSON(context.Stdout, data)
+	return c.output.Print(context.Stdout, formatter.Printable{
+		Data: data,
+		Names: func() []string {
+			names := make([]string, len(data))
+			for i, env := range data {
+				names[i] = env.Name
+			}
+			return names
+		},
+		Rows: func() ([]string, [][]string) {
+			rows := make([][]string, len(data))
+			for i, env := range data {
+				rows[i] = []string{env.Name, env.Value, strconv.FormatBool(env.Private), env.ManagedBy}
+			}
+			return []string{"name", "value", "private", "managedBy"}, rows
+		},
+	})
 }
 
 type EnvSet struct {
@@ -167,7 +185,8 @@ func (c *EnvSet) Info() *cmd.Info {
 func (c *EnvSet) Run(context *cmd.Context) error {
 	context.RawOutput()
 
-	err := checkAppAndJobInputs(c.appName, c.jobName)
+	var err error
+	c.appName, c.jobName, err = resolveAppOrJob(c.appName, c.jobName)
 	if err != nil {
 		return err
 	}
@@ -291,7 +310,8 @@ func (c *EnvUnset) Info() *cmd.Info {
 func (c *EnvUnset) Run(context *cmd.Context) error {
 	context.RawOutput()
 
-	err := checkAppAndJobInputs(c.appName, c.jobName)
+	var err error
+	c.appName, c.jobName, err = resolveAppOrJob(c.appName, c.jobName)
 	if err != nil {
 		return err
 	}
@@ -364,6 +384,24 @@ func requestEnvGetURL(c *EnvGet, args []string) ([]byte, error) {
 	return b, nil
 }
 
+// resolveAppOrJob returns the app or job given in the flags or, when none is
+// given, the one set in the project context file. The app takes precedence
+// when the context sets both.
+func resolveAppOrJob(appName, jobName string) (string, string, error) {
+	if appName == "" && jobName == "" {
+		projectCtx, err := project.Current()
+		if err != nil {
+			return "", "", err
+		}
+		if projectCtx != nil && projectCtx.App != "" {
+			appName = projectCtx.App
+		} else if projectCtx != nil {
+			jobName = projectCtx.Job
+		}
+	}
+	return appName, jobName, checkAppAndJobInputs(appName, jobName)
+}
+
 func checkAppAndJobInputs(appName string, jobName string) error {
 	if appName == "" && jobName == "" {
 		return errors.New(ErrMissingAppOrJob)
@@ -8,6 +8,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/cezarsa/form"
@@ -40,6 +42,48 @@ func (s *S) TestEnvGetRun(c *check.C) {
 	c.Assert(stdout.String(), check.Equals, result)
 }
 
+func (s *S) TestEnvGetRunWithProjectContext(c *check.C) {
+	wd, err := os.Getwd()
+	c.Assert(err, check.IsNil)
+	defer os.Chdir(wd)
+	root := c.MkDir()
+	c.Assert(os.WriteFile(filepath.Join(root, ".tsuru.yaml"), []byte("job: somejob\n"), 0600), check.IsNil)
+	c.Assert(os.Chdir(root), check.IsNil)
+	var stdout bytes.Buffer
+	trans := &cmdtest.ConditionalTransport{
+		Transport: cmdtest.Transport{Message: `[{"name": "DATABASE_HOST", "value": "somehost", "public": true}]`, Status: http.StatusOK},
+		CondFunc: func(req *http.Request) bool {
+			return strings.HasSuffix(req.URL.Path, "/jobs/somejob/env")
+		},
+	}
+	s.setupFakeTransport(trans)
+	command := EnvGet{}
+	command.Flags().Parse([]string{})
+	err = command.Run(&cmd.Context{Args: []string{"DATABASE_HOST"}, Stdout: &stdout})
+	c.Assert(err, check.IsNil)
+	c.Assert(stdout.String(), check.Equals, "DATABASE_HOST=somehost\n")
+}
+
+func (s *S) TestEnvSetRunFlagOverridesProjectContext(c *check.C) {
+	wd, err := os.Getwd()
+	c.Assert(err, check.IsNil)
+	defer os.Chdir(wd)
+	root := c.MkDir()
+	c.Assert(os.WriteFile(filepath.Join(root, ".tsuru.yaml"), []byte("app: otherapp\n"), 0600), check.IsNil)
+	c.Assert(os.Chdir(root), check.IsNil)
+	trans := &cmdtest.ConditionalTransport{
+		Transport: cmdtest.Transport{Message: `{"Message":"variable set"}`, Status: http.StatusOK},
+		CondFunc: func(req *http.Request) bool {
+			return req.Method == "POST" && strings.HasSuffix(req.URL.Path, "/jobs/somejob/env")
+		},
+	}
+	s.setupFakeTransport(trans)
+	command := EnvSet{}
+	command.Flags().Parse([]string{"-j", "somejob"})
+	err = command.Run(&cmd.Context{Args: []string{"DATABASE_HOST=somehost"}, Stdout: &bytes.Buffer{}})
+	c.Assert(err, check.IsNil)
+}
+
 func (s *S) TestEnvGetRunWithMultipleParams(c *check.C) {
 	var stdout, stderr bytes.Buffer
 	jsonResult := `[{"name": "DATABASE_HOST", "value": "somehost", "public": true}, {"name": "DATABASE_USER", "value": "someuser", "public": true}]`
@@ -23,7 +23,6 @@ import (
 	"github.com/tsuru/go-tsuruclient/pkg/config"
 	"github.com/tsuru/tablecli"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
-	"github.com/tsuru/tsuru-client/tsuru/cmd/standards"
 	"github.com/tsuru/tsuru-client/tsuru/formatter"
 	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
 	"github.com/tsuru/tsuru/event"
@@ -33,7 +32,7 @@ import (
 type EventList struct {
 	fs     *pflag.FlagSet
 	filter eventFilter
-	json   bool
+	output formatter.Output
 }
 
 type eventFilter struct {
@@ -73,7 +72,7 @@ func (f *eventFilter) flags(fs *pflag.FlagSet) {
 	name = "Filter events by target value"
 	fs.StringVarP(&f.filter.Target.Value, "target-value", "v", "", name)
 	name = "Filter events by owner name"
-	fs.StringVarP(&f.filter.OwnerName, "owner", "o", "", name)
+	fs.StringVar(&f.filter.OwnerName, "owner", "", name+formatter.MovedShorthandNote)
 	name = "Shows only currently running events"
 	fs.BoolVarP(&f.running, "running", "r", false, name)
 }
@@ -81,7 +80,7 @@ func (f *eventFilter) flags(fs *pflag.FlagSet) {
 func (c *EventList) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:  "event-list",
-		Usage: "[--kind/-k kind name]... [--owner/-o owner] [--running/-r] [--include-removed/-i] [--event-target/-t target type] [--target-value/-v target value]",
+		Usage: "[--kind/-k kind name]... [--owner owner] [--running/-r] [--include-removed/-i] [--event-target/-t target type] [--target-value/-v target value]",
 		Desc: `Lists events that you have permission to see.
 
 Flags can be used to filter the list of events.`,
@@ -92,7 +91,7 @@ func (c *EventList) Flags() *pflag.FlagSet {
 	if c.fs == nil {
 		c.fs = pflag.NewFlagSet("", pflag.ExitOnError)
 		c.filter.flags(c.fs)
-		c.fs.BoolVar(&c.json, standards.FlagJSON, false, "Show JSON")
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -128,9 +127,38 @@ func (c *EventList) Run(context *cmd.Context) error {
 		return fmt.Errorf("unable to unmarshal %q: %s", string(result), err)
 	}
 
-	if c.json {
-
-		return formatter.JSON(context.Stdout, evts)
+	if !c.output.IsTable() {
+		return c.output.Print(context.Stdout, formatter.Printable{
+			Data: evts,
+			Names: func() []string {
+				names := make([]string, len(evts))
+				for i, evt := range evts {
+					names[i] = evt.UniqueID.Hex()
+				}
+				return names
+			},
+			Rows: func() ([]string, [][]string) {
+				rows := make([][]string, len(evts))
+				for i, evt := range evts {
+					var endTime string
+					if !evt.Running {
+						endTime = evt.EndTime.Format(time.RFC3339)
+					}
+					rows[i] = []string{
+						evt.UniqueID.Hex(),
+						evt.StartTime.Format(time.RFC3339),
+						endTime,
+						strconv.FormatBool(evt.Running),
+						strconv.FormatBool(!evt.Running && evt.Error == ""),
+						evt.Owner.Name,
+						evt.Kind.Name,
+						string(evt.Target.Type),
+						evt.Target.Value,
+					}
+				}
+				return []string{"id", "startTime", "endTime", "running", "success", "owner", "kind", "targetType", "targetValue"}, rows
+			},
+		})
 	}
 
 	return c.Show(evts, context)
@@ -200,14 +228,14 @@ func (c *EventList) Show(evts []eventTypes.EventData, context *cmd.Context) erro
 }
 
 type EventInfo struct {
-	fs   *pflag.FlagSet
-	json bool
+	fs     *pflag.FlagSet
+	output formatter.Output
 }
 
 func (c *EventInfo) Flags() *pflag.FlagSet {
 	if c.fs == nil {
 		c.fs = pflag.NewFlagSet("event-info", pflag.ContinueOnError)
-		c.fs.BoolVar(&c.json, standards.FlagJSON, false, "Show JSON")
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -246,8 +274,11 @@ func (c *EventInfo) Run(context *cmd.Context) error {
 		return fmt.Errorf("unable to unmarshal %q: %s", string(result), err)
 	}
 
-	if c.json {
-		return formatter.JSON(context.Stdout, evt)
+	if !c.output.IsTable() {
+		return c.output.Print(context.Stdout, formatter.Printable{
+			Data:  evt,
+			Names: func() []string { return []string{evt.UniqueID.Hex()} },
+		})
 	}
 	return c.Show(&evt, context)
 }
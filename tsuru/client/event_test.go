@@ -243,7 +243,7 @@ func (s *S) TestEventListWithFilters(c *check.C) {
 	}
 	s.setupFakeTransport(trans)
 	command := EventList{}
-	err := command.Flags().Parse([]string{"-k", "app.update", "-k", "app.deploy", "-o", "event-owner", "-t", "app", "--target-value", "appname", "-r"})
+	err := command.Flags().Parse([]string{"-k", "app.update", "-k", "app.deploy", "--owner", "event-owner", "-t", "app", "--target-value", "appname", "-r"})
 	c.Assert(err, check.IsNil)
 	err = command.Run(&context)
 	c.Assert(err, check.IsNil)
@@ -271,7 +271,7 @@ func (s *S) TestEventListWithFiltersShorthand(c *check.C) {
 	}
 	s.setupFakeTransport(trans)
 	command := EventList{}
-	err := command.Flags().Parse([]string{"-k", "app.deploy", "-o", "event-owner", "-t", "app", "-v", "myapp", "-r"})
+	err := command.Flags().Parse([]string{"-k", "app.deploy", "--owner", "event-owner", "-t", "app", "-v", "myapp", "-r"})
 	c.Assert(err, check.IsNil)
 	err = command.Run(&context)
 	c.Assert(err, check.IsNil)
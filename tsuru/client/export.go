@@ -0,0 +1,74 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	tsuruClientApp "github.com/tsuru/tsuru-client/tsuru/app"
+	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	"github.com/tsuru/tsuru-client/tsuru/formatter"
+)
+
+type AppExport struct {
+	tsuruClientApp.AppNameMixIn
+	output formatter.Output
+	fs     *pflag.FlagSet
+}
+
+func (c *AppExport) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:  "app-export",
+		Usage: "[-a/--app appname] [-o/--output json|yaml]",
+		Desc: `Exports the configuration of an app as a manifest.
+
+The manifest includes the plan, pool, platform, team owner, description, tags,
+cnames, routers, metadata, processes, autoscale, service instance binds,
+volume binds and environment variables of the app. It is written as YAML by
+default, or in any of the formats supported by the -o/--output flag, and can
+be applied back with "tsuru apply".
+
+Values of private environment variables cannot be read, so they are exported
+with the "` + privateEnvMask + `" placeholder, which must be replaced before the
+manifest is applied to a new app. Variables managed by services are left out,
+as they are handled by the service binds.`,
+		MinArgs: 0,
+	}
+}
+
+func (c *AppExport) Flags() *pflag.FlagSet {
+	if c.fs == nil {
+		c.fs = c.AppNameMixIn.Flags()
+		c.output.AddFlags(c.fs)
+	}
+	return c.fs
+}
+
+func (c *AppExport) Run(ctx *cmd.Context) error {
+	appName, err := c.AppNameByFlag()
+	if err != nil {
+		return err
+	}
+	m, err := fetchAppManifest(appName)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		return fmt.Errorf("app %q not found", appName)
+	}
+	if !c.output.IsTable() {
+		return c.output.Print(ctx.Stdout, formatter.Printable{
+			Data:  m,
+			Names: func() []string { return []string{m.Name} },
+		})
+	}
+	data, err := m.yaml()
+	if err != nil {
+		return err
+	}
+	_, err = ctx.Stdout.Write(data)
+	return err
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	"github.com/tsuru/tsuru-client/tsuru/cmd/cmdtest"
+	"gopkg.in/check.v1"
+)
+
+func (s *S) TestAppExportInfo(c *check.C) {
+	c.Assert((&AppExport{}).Info(), check.NotNil)
+}
+
+func (s *S) TestAppExport(c *check.C) {
+	var stdout, stderr bytes.Buffer
+	context := cmd.Context{Stdout: &stdout, Stderr: &stderr}
+	s.setupFakeTransport(&cmdtest.AnyConditionalTransport{ConditionalTransports: applyGetTransports()})
+	command := AppExport{}
+	err := command.Flags().Parse([]string{"-a", "app1"})
+	c.Assert(err, check.IsNil)
+	err = command.Run(&context)
+	c.Assert(err, check.IsNil)
+	expected := `cnames:
+- app1.example.com
+description: my app
+env:
+- name: DATABASE_PASSWORD
+  private: true
+  value: '***'
+- name: LOG_LEVEL
+  value: info
+name: app1
+plan: c1m1
+platform: python
+pool: main
+routers:
+- name: http
+tags:
+- web
+teamOwner: myteam
+`
+	c.Assert(stdout.String(), check.Equals, expected)
+}
+
+func (s *S) TestAppExportJSON(c *check.C) {
+	var stdout, stderr bytes.Buffer
+	context := cmd.Context{Stdout: &stdout, Stderr: &stderr}
+	trans := &cmdtest.AnyConditionalTransport{ConditionalTransports: []cmdtest.ConditionalTransport{
+		{
+			Transport: cmdtest.Transport{Message: `{"name": "app2", "volumeBinds": [{"ID": {"App": "app2", "MountPoint": "/data", "Volume": "vol1"}, "ReadOnly": true}]}`, Status: http.StatusOK},
+			CondFunc: func(req *http.Request) bool {
+				return strings.HasSuffix(req.URL.Path, "/apps/app2")
+			},
+		},
+		{
+			Transport: cmdtest.Transport{Status: http.StatusNoContent},
+			CondFunc: func(req *http.Request) bool {
+				return strings.HasSuffix(req.URL.Path, "/apps/app2/env")
+			},
+		},
+	}}
+	s.setupFakeTransport(trans)
+	command := AppExport{}
+	err := command.Flags().Parse([]string{"-a", "app2", "--json"})
+	c.Assert(err, check.IsNil)
+	err = command.Run(&context)
+	c.Assert(err, check.IsNil)
+	var m AppManifest
+	err = json.Unmarshal(stdout.Bytes(), &m)
+	c.Assert(err, check.IsNil)
+	c.Assert(m.Name, check.Equals, "app2")
+	c.Assert(m.VolumeBinds, check.DeepEquals, []manifestVolumeBind{{Volume: "vol1", MountPoint: "/data", ReadOnly: true}})
+}
+
+func (s *S) TestAppExportNotFound(c *check.C) {
+	var stdout, stderr bytes.Buffer
+	context := cmd.Context{Stdout: &stdout, Stderr: &stderr}
+	s.setupFakeTransport(&cmdtest.Transport{Message: "not found", Status: http.StatusNotFound})
+	command := AppExport{}
+	err := command.Flags().Parse([]string{"-a", "app3"})
+	c.Assert(err, check.IsNil)
+	err = command.Run(&context)
+	c.Assert(err, check.ErrorMatches, `app "app3" not found`)
+}
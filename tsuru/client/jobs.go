@@ -163,6 +163,13 @@ func (c *JobCreate) Run(ctx *cmd.Context) error {
 	if c.maxRunningTime > 0 {
 		activeDeadlineSecondsResult = &c.maxRunningTime
 	}
+	team, pool := targetDefaults()
+	if c.teamOwner == "" {
+		c.teamOwner = team
+	}
+	if c.pool == "" {
+		c.pool = pool
+	}
 	j := tsuru.InputJob{
 		Name:                  jobName,
 		Tags:                  c.tags,
@@ -186,14 +193,14 @@ func (c *JobCreate) Run(ctx *cmd.Context) error {
 }
 
 type JobInfo struct {
-	fs   *pflag.FlagSet
-	json bool
+	fs     *pflag.FlagSet
+	output formatter.Output
 }
 
 func (c *JobInfo) Flags() *pflag.FlagSet {
 	if c.fs == nil {
 		c.fs = pflag.NewFlagSet("job-info", pflag.ContinueOnError)
-		c.fs.BoolVar(&c.json, standards.FlagJSON, false, "Show JSON")
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -243,8 +250,11 @@ func (c *JobInfo) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return err
 	}
-	if c.json {
-		return formatter.JSON(ctx.Stdout, jobInfo)
+	if !c.output.IsTable() {
+		return c.output.Print(ctx.Stdout, formatter.Printable{
+			Data:  jobInfo,
+			Names: func() []string { return []string{jobInfo.Job.Name} },
+		})
 	}
 
 	var buf bytes.Buffer
@@ -340,7 +350,7 @@ type jobFilter struct {
 type JobList struct {
 	fs         *pflag.FlagSet
 	filter     jobFilter
-	json       bool
+	output     formatter.Output
 	simplified bool
 }
 
@@ -350,11 +360,11 @@ func (c *JobList) Flags() *pflag.FlagSet {
 		c.fs.SortFlags = false
 
 		c.fs.StringVarP(&c.filter.name, standards.FlagName, standards.ShortFlagName, "", "Filter jobs by name")
-		c.fs.StringVarP(&c.filter.pool, standards.FlagPool, standards.ShortFlagPool, "", "Filter jobs by pool")
+		c.fs.StringVar(&c.filter.pool, standards.FlagPool, "", "Filter jobs by pool"+formatter.MovedShorthandNote)
 		c.fs.StringVarP(&c.filter.plan, standards.FlagPlan, standards.ShortFlagPlan, "", "Filter jobs by plan")
 		c.fs.StringVarP(&c.filter.teamOwner, standards.FlagTeam, standards.ShortFlagTeam, "", "Filter jobs by team owner")
 		c.fs.BoolVarP(&c.simplified, standards.FlagOnlyName, standards.ShortFlagOnlyName, false, "Display only jobs name")
-		c.fs.BoolVar(&c.json, standards.FlagJSON, false, "Show JSON")
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -383,8 +393,32 @@ func (c *JobList) Run(ctx *cmd.Context) error {
 	}
 
 	jobs = c.clientSideFilter(jobs)
-	if c.json {
-		return formatter.JSON(ctx.Stdout, jobs)
+	if !c.output.IsTable() {
+		return c.output.Print(ctx.Stdout, formatter.Printable{
+			Data: jobs,
+			Names: func() []string {
+				names := make([]string, len(jobs))
+				for i, j := range jobs {
+					names[i] = j.Name
+				}
+				return names
+			},
+			Rows: func() ([]string, [][]string) {
+				rows := make([][]string, len(jobs))
+				for i, j := range jobs {
+					rows[i] = []string{
+						j.Name,
+						j.Pool,
+						j.Plan.Name,
+						j.TeamOwner,
+						jobSchedule(j),
+						j.Spec.Container.Image,
+						strings.Join(j.Spec.Container.Command, " "),
+					}
+				}
+				return []string{"name", "pool", "plan", "teamowner", "schedule", "image", "command"}, rows
+			},
+		})
 	}
 
 	if c.simplified {
@@ -394,20 +428,24 @@ func (c *JobList) Run(ctx *cmd.Context) error {
 		return nil
 	}
 
+	wide := c.output.Wide()
 	tbl := tablecli.NewTable()
 	tbl.Headers = tablecli.Row{"Name", "Schedule", "Image", "Command"}
+	if wide {
+		tbl.Headers = append(tbl.Headers, "Pool", "Plan", "Team Owner")
+	}
 	tbl.LineSeparator = true
 	for _, j := range jobs {
-		schedule := j.Spec.Schedule
-		if j.Spec.Manual {
-			schedule = "manual"
-		}
-		tbl.AddRow(tablecli.Row{
+		row := tablecli.Row{
 			j.Name,
-			schedule,
+			jobSchedule(j),
 			j.Spec.Container.Image,
 			strings.Join(j.Spec.Container.Command, " "),
-		})
+		}
+		if wide {
+			row = append(row, j.Pool, j.Plan.Name, j.TeamOwner)
+		}
+		tbl.AddRow(row)
 	}
 	tbl.Sort()
 	fmt.Fprint(ctx.Stdout, tbl.String())
@@ -415,6 +453,13 @@ func (c *JobList) Run(ctx *cmd.Context) error {
 	return nil
 }
 
+func jobSchedule(j tsuru.Job) string {
+	if j.Spec.Manual {
+		return "manual"
+	}
+	return j.Spec.Schedule
+}
+
 func (c *JobList) clientSideFilter(jobs []tsuru.Job) []tsuru.Job {
 	result := make([]tsuru.Job, 0, len(jobs))
 
@@ -0,0 +1,370 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	"github.com/tsuru/go-tsuruclient/pkg/config"
+	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+)
+
+// AppManifest describes the whole configuration of an app. Fields left out of
+// a manifest are not managed, while empty lists mean that every existing item
+// must be removed.
+type AppManifest struct {
+	Name         string                `json:"name"`
+	Platform     string                `json:"platform,omitempty"`
+	Plan         string                `json:"plan,omitempty"`
+	Pool         string                `json:"pool,omitempty"`
+	TeamOwner    string                `json:"teamOwner,omitempty"`
+	Description  string                `json:"description,omitempty"`
+	Tags         []string              `json:"tags,omitempty"`
+	CNames       []string              `json:"cnames,omitempty"`
+	Routers      []manifestRouter      `json:"routers,omitempty"`
+	Env          []manifestEnv         `json:"env,omitempty"`
+	Metadata     *manifestMetadata     `json:"metadata,omitempty"`
+	Processes    []manifestProcess     `json:"processes,omitempty"`
+	Autoscale    []manifestAutoScale   `json:"autoscale,omitempty"`
+	ServiceBinds []manifestServiceBind `json:"serviceBinds,omitempty"`
+	VolumeBinds  []manifestVolumeBind  `json:"volumeBinds,omitempty"`
+}
+
+type manifestRouter struct {
+	Name string            `json:"name"`
+	Opts map[string]string `json:"opts,omitempty"`
+}
+
+type manifestEnv struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Private bool   `json:"private,omitempty"`
+}
+
+type manifestMetadata struct {
+	Labels      []appTypes.MetadataItem `json:"labels,omitempty"`
+	Annotations []appTypes.MetadataItem `json:"annotations,omitempty"`
+}
+
+type manifestProcess struct {
+	Name     string            `json:"name"`
+	Plan     string            `json:"plan,omitempty"`
+	Metadata *manifestMetadata `json:"metadata,omitempty"`
+}
+
+type manifestAutoScale struct {
+	Process    string                          `json:"process"`
+	MinUnits   uint                            `json:"minUnits"`
+	MaxUnits   uint                            `json:"maxUnits"`
+	AverageCPU string                          `json:"averageCPU,omitempty"`
+	Schedules  []provTypes.AutoScaleSchedule   `json:"schedules,omitempty"`
+	Prometheus []provTypes.AutoScalePrometheus `json:"prometheus,omitempty"`
+	ScaleDown  *provTypes.ScaleDownPolicy      `json:"scaleDown,omitempty"`
+}
+
+type manifestServiceBind struct {
+	Service  string `json:"service"`
+	Instance string `json:"instance"`
+}
+
+type manifestVolumeBind struct {
+	Volume     string `json:"volume"`
+	MountPoint string `json:"mountPoint"`
+	ReadOnly   bool   `json:"readOnly,omitempty"`
+}
+
+type appEnv struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	Public    bool   `json:"public"`
+	ManagedBy string `json:"managedBy,omitempty"`
+}
+
+// privateEnvMask replaces the value of private variables, which cannot be
+// read back from the API.
+const privateEnvMask = "***"
+
+func readAppManifest(path string) (*AppManifest, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m AppManifest
+	err = yaml.Unmarshal(data, &m)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse manifest %q: %w", path, err)
+	}
+	if m.Name == "" {
+		return nil, fmt.Errorf("manifest %q must define the app name", path)
+	}
+	m.normalize()
+	return &m, nil
+}
+
+// fetchAppManifest builds the manifest of a live app, it returns a nil
+// manifest when the app does not exist.
+func fetchAppManifest(appName string) (*AppManifest, error) {
+	a, err := fetchApp(appName)
+	if err != nil || a == nil {
+		return nil, err
+	}
+	envs, err := fetchAppEnvs(appName)
+	if err != nil {
+		return nil, err
+	}
+	return manifestFromApp(a, envs), nil
+}
+
+func fetchApp(appName string) (*app, error) {
+	u, err := config.GetURL(fmt.Sprintf("/apps/%s", appName))
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := tsuruHTTP.AuthenticatedClient.Do(request)
+	if err != nil {
+		if httpErr, ok := tsuruHTTP.UnwrapErr(err).(*tsuruErrors.HTTP); ok && httpErr.Code == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer response.Body.Close()
+	var a app
+	err = json.NewDecoder(response.Body).Decode(&a)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func fetchAppEnvs(appName string) ([]appEnv, error) {
+	u, err := config.GetURL(fmt.Sprintf("/apps/%s/env", appName))
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := tsuruHTTP.AuthenticatedClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	var envs []appEnv
+	if response.StatusCode == http.StatusNoContent {
+		return envs, nil
+	}
+	err = json.NewDecoder(response.Body).Decode(&envs)
+	if err != nil {
+		return nil, err
+	}
+	return envs, nil
+}
+
+func manifestFromApp(a *app, envs []appEnv) *AppManifest {
+	m := &AppManifest{
+		Name:        a.Name,
+		Platform:    a.Platform,
+		Pool:        a.Pool,
+		TeamOwner:   a.TeamOwner,
+		Description: a.Description,
+		Tags:        append([]string{}, a.Tags...),
+		Metadata:    metadataToManifest(a.Metadata),
+	}
+	if a.Plan != nil {
+		m.Plan = a.Plan.Name
+	}
+	for _, cname := range a.CName {
+		if cname != "" {
+			m.CNames = append(m.CNames, cname)
+		}
+	}
+	for _, r := range a.Routers {
+		m.Routers = append(m.Routers, manifestRouter{Name: r.Name, Opts: r.Opts})
+	}
+	for _, e := range envs {
+		// variables managed by services are handled by serviceBinds
+		if e.ManagedBy != "" {
+			continue
+		}
+		env := manifestEnv{Name: e.Name, Value: e.Value}
+		if !e.Public {
+			env.Private = true
+			env.Value = privateEnvMask
+		}
+		m.Env = append(m.Env, env)
+	}
+	for _, p := range a.Processes {
+		m.Processes = append(m.Processes, manifestProcess{
+			Name:     p.Name,
+			Plan:     p.Plan,
+			Metadata: metadataToManifest(p.Metadata),
+		})
+	}
+	for _, as := range a.Autoscale {
+		m.Autoscale = append(m.Autoscale, autoScaleToManifest(as))
+	}
+	for _, b := range a.ServiceInstanceBinds {
+		m.ServiceBinds = append(m.ServiceBinds, manifestServiceBind{Service: b.Service, Instance: b.Instance})
+	}
+	for _, b := range a.VolumeBinds {
+		m.VolumeBinds = append(m.VolumeBinds, manifestVolumeBind{
+			Volume:     b.ID.Volume,
+			MountPoint: b.ID.MountPoint,
+			ReadOnly:   b.ReadOnly,
+		})
+	}
+	m.normalize()
+	return m
+}
+
+func metadataToManifest(metadata appTypes.Metadata) *manifestMetadata {
+	if len(metadata.Labels) == 0 && len(metadata.Annotations) == 0 {
+		return nil
+	}
+	return &manifestMetadata{
+		Labels:      append([]appTypes.MetadataItem{}, metadata.Labels...),
+		Annotations: append([]appTypes.MetadataItem{}, metadata.Annotations...),
+	}
+}
+
+func autoScaleToManifest(as provTypes.AutoScaleSpec) manifestAutoScale {
+	return manifestAutoScale{
+		Process:    as.Process,
+		MinUnits:   as.MinUnits,
+		MaxUnits:   as.MaxUnits,
+		AverageCPU: as.AverageCPU,
+		Schedules:  as.Schedules,
+		Prometheus: as.Prometheus,
+		ScaleDown:  as.Behavior.ScaleDown,
+	}
+}
+
+func (m *AppManifest) normalize() {
+	sort.Strings(m.Tags)
+	sort.Strings(m.CNames)
+	sort.Slice(m.Routers, func(i, j int) bool { return m.Routers[i].Name < m.Routers[j].Name })
+	sort.Slice(m.Env, func(i, j int) bool { return m.Env[i].Name < m.Env[j].Name })
+	sort.Slice(m.Processes, func(i, j int) bool { return m.Processes[i].Name < m.Processes[j].Name })
+	sort.Slice(m.Autoscale, func(i, j int) bool { return m.Autoscale[i].Process < m.Autoscale[j].Process })
+	sort.Slice(m.ServiceBinds, func(i, j int) bool {
+		if m.ServiceBinds[i].Service == m.ServiceBinds[j].Service {
+			return m.ServiceBinds[i].Instance < m.ServiceBinds[j].Instance
+		}
+		return m.ServiceBinds[i].Service < m.ServiceBinds[j].Service
+	})
+	sort.Slice(m.VolumeBinds, func(i, j int) bool {
+		if m.VolumeBinds[i].Volume == m.VolumeBinds[j].Volume {
+			return m.VolumeBinds[i].MountPoint < m.VolumeBinds[j].MountPoint
+		}
+		return m.VolumeBinds[i].Volume < m.VolumeBinds[j].Volume
+	})
+	m.Metadata.normalize()
+	for i := range m.Processes {
+		m.Processes[i].Metadata.normalize()
+	}
+}
+
+func (m *manifestMetadata) normalize() {
+	if m == nil {
+		return
+	}
+	sort.Slice(m.Labels, func(i, j int) bool { return m.Labels[i].Name < m.Labels[j].Name })
+	sort.Slice(m.Annotations, func(i, j int) bool { return m.Annotations[i].Name < m.Annotations[j].Name })
+}
+
+// scoped returns a copy of the manifest restricted to the fields managed by
+// the desired manifest, so unmanaged fields do not show up in diffs.
+func (m *AppManifest) scoped(desired *AppManifest) *AppManifest {
+	if m == nil {
+		return nil
+	}
+	s := *m
+	if desired.Platform == "" {
+		s.Platform = ""
+	}
+	if desired.Plan == "" {
+		s.Plan = ""
+	}
+	if desired.Pool == "" {
+		s.Pool = ""
+	}
+	if desired.TeamOwner == "" {
+		s.TeamOwner = ""
+	}
+	if desired.Description == "" {
+		s.Description = ""
+	}
+	if desired.Tags == nil {
+		s.Tags = nil
+	}
+	if desired.CNames == nil {
+		s.CNames = nil
+	}
+	if desired.Routers == nil {
+		s.Routers = nil
+	}
+	if desired.Env == nil {
+		s.Env = nil
+	}
+	if desired.Metadata == nil {
+		s.Metadata = nil
+	}
+	if desired.Processes == nil {
+		s.Processes = nil
+	}
+	if desired.Autoscale == nil {
+		s.Autoscale = nil
+	}
+	if desired.ServiceBinds == nil {
+		s.ServiceBinds = nil
+	}
+	if desired.VolumeBinds == nil {
+		s.VolumeBinds = nil
+	}
+	return &s
+}
+
+// yaml renders the manifest masking the values of private variables. Values
+// other than the placeholder are rendered as a new value, so setting them is
+// shown as a change.
+func (m *AppManifest) yaml() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	masked := *m
+	masked.Env = make([]manifestEnv, len(m.Env))
+	for i, e := range m.Env {
+		if e.Private && e.Value != privateEnvMask {
+			e.Value = privateEnvMask + " (new value)"
+		}
+		masked.Env[i] = e
+	}
+	if m.Env == nil {
+		masked.Env = nil
+	}
+	return yaml.Marshal(masked)
+}
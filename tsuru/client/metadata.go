@@ -77,7 +77,7 @@ type MetadataGet struct {
 	tsuruClientApp.AppNameMixIn
 	jobName      string
 	flagsApplied bool
-	json         bool
+	output       formatter.Output
 	fs           *pflag.FlagSet
 }
 
@@ -87,7 +87,7 @@ func (c *MetadataGet) Flags() *pflag.FlagSet {
 		c.fs.StringVarP(&c.jobName, standards.FlagJob, standards.ShortFlagJob, "", "The name of the job.")
 
 		if !c.flagsApplied {
-			c.fs.BoolVar(&c.json, standards.FlagJSON, false, "Show JSON")
+			c.output.AddFlags(c.fs)
 			c.flagsApplied = true
 		}
 	}
@@ -117,8 +117,20 @@ func (c *MetadataGet) Run(context *cmd.Context) error {
 		return err
 	}
 
-	if c.json {
-		return formatter.JSON(context.Stdout, metadata)
+	if !c.output.IsTable() {
+		return c.output.Print(context.Stdout, formatter.Printable{
+			Data: metadata,
+			Rows: func() ([]string, [][]string) {
+				var rows [][]string
+				for _, label := range metadata.Labels {
+					rows = append(rows, []string{"label", label.Name, label.Value})
+				}
+				for _, annotation := range metadata.Annotations {
+					rows = append(rows, []string{"annotation", annotation.Name, annotation.Value})
+				}
+				return []string{"type", "name", "value"}, rows
+			},
+		})
 	}
 
 	if len(metadataByProcess) > 0 {
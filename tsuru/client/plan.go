@@ -7,6 +7,7 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"sort"
 	"strconv"
@@ -17,6 +18,7 @@ import (
 	"github.com/tsuru/tablecli"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
 	"github.com/tsuru/tsuru-client/tsuru/cmd/standards"
+	"github.com/tsuru/tsuru-client/tsuru/formatter"
 	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
 	appTypes "github.com/tsuru/tsuru/types/app"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -26,6 +28,7 @@ type PlanList struct {
 	bytes               bool
 	k8sFriendly         bool
 	showMaxBurstAllowed bool
+	output              formatter.Output
 
 	fs *pflag.FlagSet
 }
@@ -38,7 +41,7 @@ func (c *PlanList) Flags() *pflag.FlagSet {
 
 		c.fs.BoolVar(&c.showMaxBurstAllowed, "show-max-cpu-burst-allowed", false, "show column about max CPU burst allowed by plan")
 		c.fs.BoolVar(&c.k8sFriendly, "kubernetes-friendly", false, "show values friendly for a kubernetes user")
-
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -46,7 +49,7 @@ func (c *PlanList) Flags() *pflag.FlagSet {
 func (c *PlanList) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:  "plan-list",
-		Usage: "[--bytes][--kubernetes-friendly][--show-max-cpu-burst-allowed]",
+		Usage: "[--bytes][--kubernetes-friendly][--show-max-cpu-burst-allowed] [-o/--output format]",
 		Desc:  "List available plans that can be used when creating an app.",
 	}
 }
@@ -290,6 +293,9 @@ func (c *PlanList) Run(context *cmd.Context) error {
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode == http.StatusNoContent {
+		if !c.output.IsTable() {
+			return c.printPlans(context.Stdout, []appTypes.Plan{})
+		}
 		fmt.Fprintln(context.Stdout, "No plans available.")
 		return nil
 	}
@@ -297,6 +303,9 @@ func (c *PlanList) Run(context *cmd.Context) error {
 	if err != nil {
 		return err
 	}
+	if !c.output.IsTable() {
+		return c.printPlans(context.Stdout, plans)
+	}
 
 	if c.k8sFriendly {
 		fmt.Fprintf(context.Stdout, "%s", renderPlansK8SFriendly(plans, c.showMaxBurstAllowed))
@@ -306,3 +315,23 @@ func (c *PlanList) Run(context *cmd.Context) error {
 
 	return nil
 }
+
+func (c *PlanList) printPlans(w io.Writer, plans []appTypes.Plan) error {
+	return c.output.Print(w, formatter.Printable{
+		Data: plans,
+		Names: func() []string {
+			names := make([]string, len(plans))
+			for i, p := range plans {
+				names[i] = p.Name
+			}
+			return names
+		},
+		Rows: func() ([]string, [][]string) {
+			rows := make([][]string, len(plans))
+			for i, p := range plans {
+				rows[i] = []string{p.Name, strconv.Itoa(p.CPUMilli), strconv.FormatInt(p.Memory, 10), strconv.FormatBool(p.Default)}
+			}
+			return []string{"name", "cpumilli", "memory", "default"}, rows
+		},
+	})
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/tsuru/go-tsuruclient/pkg/config"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
 	v2 "github.com/tsuru/tsuru-client/tsuru/cmd/v2"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
 	"github.com/tsuru/tsuru/exec"
 )
 
@@ -402,6 +403,10 @@ func runPlugin(context *cmd.Context, pluginName string, args []string) error {
 		"TSURU_TABLE_UTF8=" + strconv.FormatBool(v2.TableUTF8()),
 	}
 
+	if tlsSettings, err := credentials.CurrentTLS(); err == nil {
+		tsuruEnvs = append(tsuruEnvs, tlsSettings.Env()...)
+	}
+
 	if v2.ColorDisabled() {
 		tsuruEnvs = append(tsuruEnvs, "NO_COLOR=1")
 	}
@@ -32,7 +32,7 @@ type PoolList struct {
 	fs         *pflag.FlagSet
 	filter     poolFilter
 	simplified bool
-	json       bool
+	output     formatter.Output
 }
 
 type Pool struct {
@@ -79,8 +79,7 @@ func (c *PoolList) Flags() *pflag.FlagSet {
 		c.fs.StringVarP(&c.filter.team, standards.FlagTeam, standards.ShortFlagTeam, "", "Filter pools by team ")
 
 		c.fs.BoolVarP(&c.simplified, standards.FlagOnlyName, standards.ShortFlagOnlyName, false, "Display only pools name")
-		c.fs.BoolVar(&c.json, standards.FlagJSON, false, "Display in JSON format")
-
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -121,8 +120,30 @@ func (pl *PoolList) Run(context *cmd.Context) error {
 		return nil
 	}
 
-	if pl.json {
-		return formatter.JSON(context.Stdout, pools)
+	if !pl.output.IsTable() {
+		return pl.output.Print(context.Stdout, formatter.Printable{
+			Data: pools,
+			Names: func() []string {
+				names := make([]string, len(pools))
+				for i, pool := range pools {
+					names[i] = pool.Name
+				}
+				return names
+			},
+			Rows: func() ([]string, [][]string) {
+				rows := make([][]string, len(pools))
+				for i, pool := range pools {
+					rows[i] = []string{
+						pool.Name,
+						pool.Kind(),
+						pool.GetProvisioner(),
+						strings.Join(pool.Allowed["team"], " "),
+						strings.Join(pool.Allowed["router"], " "),
+					}
+				}
+				return []string{"name", "kind", "provisioner", "teams", "routers"}, rows
+			},
+		})
 	}
 
 	for _, pool := range pools {
@@ -183,14 +204,14 @@ func (PoolList) Info() *cmd.Info {
 }
 
 type PoolInfo struct {
-	fs   *pflag.FlagSet
-	json bool
+	fs     *pflag.FlagSet
+	output formatter.Output
 }
 
 func (c *PoolInfo) Flags() *pflag.FlagSet {
 	if c.fs == nil {
 		c.fs = pflag.NewFlagSet("pool-info", pflag.ExitOnError)
-		c.fs.BoolVar(&c.json, standards.FlagJSON, false, "Display in JSON format")
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -220,8 +241,11 @@ func (c *PoolInfo) Run(ctx *cmd.Context) error {
 	}
 	defer resp.Body.Close()
 
-	if c.json {
-		return formatter.JSON(ctx.Stdout, pool)
+	if !c.output.IsTable() {
+		return c.output.Print(ctx.Stdout, formatter.Printable{
+			Data:  pool,
+			Names: func() []string { return []string{pool.Name} },
+		})
 	}
 
 	tabWriter := tabwriter.NewWriter(ctx.Stdout, 0, 0, 2, ' ', 0)
@@ -11,6 +11,7 @@ import (
 	"io"
 	"net/http"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/cezarsa/form"
@@ -167,7 +168,7 @@ type RoutersList struct {
 	fs         *pflag.FlagSet
 	filter     routerFilter
 	simplified bool
-	json       bool
+	output     formatter.Output
 }
 
 func (c *RoutersList) Flags() *pflag.FlagSet {
@@ -176,8 +177,7 @@ func (c *RoutersList) Flags() *pflag.FlagSet {
 
 		c.fs.StringVarP(&c.filter.name, standards.FlagName, standards.ShortFlagName, "", "Filter routers by name")
 		c.fs.BoolVarP(&c.simplified, standards.FlagOnlyName, standards.ShortFlagOnlyName, false, "Display only routers name")
-		c.fs.BoolVar(&c.json, "json", false, "Display in JSON format")
-
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -208,8 +208,24 @@ func (c *RoutersList) Run(ctx *cmd.Context) error {
 		return nil
 	}
 
-	if c.json {
-		return formatter.JSON(ctx.Stdout, routers)
+	if !c.output.IsTable() {
+		return c.output.Print(ctx.Stdout, formatter.Printable{
+			Data: routers,
+			Names: func() []string {
+				names := make([]string, len(routers))
+				for i, router := range routers {
+					names[i] = router.Name
+				}
+				return names
+			},
+			Rows: func() ([]string, [][]string) {
+				rows := make([][]string, len(routers))
+				for i, router := range routers {
+					rows[i] = []string{router.Name, router.Type, strconv.FormatBool(router.Dynamic)}
+				}
+				return []string{"name", "type", "dynamic"}, rows
+			},
+		})
 	}
 
 	table := tablecli.NewTable()
@@ -307,7 +323,7 @@ type AppRoutersList struct {
 	tsuruClientApp.AppNameMixIn
 
 	flagsApplied bool
-	json         bool
+	output       formatter.Output
 }
 
 func (c *AppRoutersList) Info() *cmd.Info {
@@ -321,7 +337,7 @@ func (c *AppRoutersList) Info() *cmd.Info {
 func (c *AppRoutersList) Flags() *pflag.FlagSet {
 	fs := c.AppNameMixIn.Flags()
 	if !c.flagsApplied {
-		fs.BoolVar(&c.json, standards.FlagJSON, false, "Show JSON")
+		c.output.AddFlags(fs)
 
 		c.flagsApplied = true
 	}
@@ -346,22 +362,36 @@ func (c *AppRoutersList) Run(context *cmd.Context) error {
 		return err
 	}
 	defer response.Body.Close()
-	if response.StatusCode == http.StatusNoContent {
-		if c.json {
-			fmt.Fprintln(context.Stdout, "[]")
-			return nil
-		}
+	if response.StatusCode == http.StatusNoContent && c.output.IsTable() {
 		fmt.Fprintln(context.Stdout, "No routers available for app.")
 		return nil
 	}
-	var routers []appTypes.AppRouter
-	err = json.NewDecoder(response.Body).Decode(&routers)
-	if err != nil {
-		return err
+	routers := []appTypes.AppRouter{}
+	if response.StatusCode != http.StatusNoContent {
+		err = json.NewDecoder(response.Body).Decode(&routers)
+		if err != nil {
+			return err
+		}
 	}
 
-	if c.json {
-		return formatter.JSON(context.Stdout, routers)
+	if !c.output.IsTable() {
+		return c.output.Print(context.Stdout, formatter.Printable{
+			Data: routers,
+			Names: func() []string {
+				names := make([]string, len(routers))
+				for i, router := range routers {
+					names[i] = router.Name
+				}
+				return names
+			},
+			Rows: func() ([]string, [][]string) {
+				rows := make([][]string, len(routers))
+				for i, router := range routers {
+					rows[i] = []string{router.Name, router.Address, router.Status}
+				}
+				return []string{"name", "address", "status"}, rows
+			},
+		})
 	}
 	renderRouters(routers, context.Stdout, "Name", 0)
 	return nil
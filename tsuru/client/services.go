@@ -66,7 +66,7 @@ type ServiceList struct {
 	fs               *pflag.FlagSet
 	filter           serviceFilter
 	simplified       bool
-	json             bool
+	output           formatter.Output
 	justServiceNames bool
 }
 
@@ -84,17 +84,17 @@ func (c *ServiceList) Flags() *pflag.FlagSet {
 
 		c.fs.StringVarP(&c.filter.service, "service", "s", "", "Filter instances by service")
 		c.fs.StringVarP(&c.filter.name, standards.FlagName, standards.ShortFlagName, "", "Filter service instances by name")
-		c.fs.StringVarP(&c.filter.pool, standards.FlagPool, standards.ShortFlagPool, "", "Filter service instances by pool")
+		c.fs.StringVar(&c.filter.pool, standards.FlagPool, "", "Filter service instances by pool"+formatter.MovedShorthandNote)
 		c.fs.StringVarP(&c.filter.plan, standards.FlagPlan, standards.ShortFlagPlan, "", "Filter service instances by plan")
 		c.fs.StringVarP(&c.filter.teamOwner, standards.FlagTeam, standards.ShortFlagTeam, "", "Filter service instances by team owner")
 
 		c.fs.BoolVarP(&c.simplified, standards.FlagOnlyName, standards.ShortFlagOnlyName, false, "Display only service instances name")
 
-		c.fs.BoolVar(&c.json, standards.FlagJSON, false, "Display in JSON format")
 		c.fs.BoolVarP(&c.justServiceNames, "just-services", "j", false, "Display just service names")
 
 		tagMessage := "Filter services by tag. Can be used multiple times"
 		c.fs.VarP(&c.filter.tags, standards.FlagTag, standards.ShortFlagTag, tagMessage)
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -144,13 +144,37 @@ func (s ServiceList) Run(ctx *cmd.Context) error {
 		return nil
 	}
 
-	if s.json {
+	if !s.output.IsTable() {
 		instances := []service.ServiceInstance{}
 		for _, s := range services {
 			instances = append(instances, s.ServiceInstances...)
 		}
 
-		return formatter.JSON(ctx.Stdout, instances)
+		return s.output.Print(ctx.Stdout, formatter.Printable{
+			Data: instances,
+			Names: func() []string {
+				names := make([]string, len(instances))
+				for i, instance := range instances {
+					names[i] = instance.ServiceName + "/" + instance.Name
+				}
+				return names
+			},
+			Rows: func() ([]string, [][]string) {
+				rows := make([][]string, len(instances))
+				for i, instance := range instances {
+					rows[i] = []string{
+						instance.ServiceName,
+						instance.Name,
+						instance.PlanName,
+						instance.Pool,
+						instance.TeamOwner,
+						strings.Join(instance.Apps, " "),
+						strings.Join(instance.Jobs, " "),
+					}
+				}
+				return []string{"service", "instance", "plan", "pool", "teamowner", "apps", "jobs"}, rows
+			},
+		})
 	}
 
 	if s.justServiceNames {
@@ -164,7 +188,7 @@ func (s ServiceList) Run(ctx *cmd.Context) error {
 		return err
 	}
 
-	hasPool := false
+	hasPool := s.output.Wide()
 	for _, service := range services {
 		for _, instance := range service.ServiceInstances {
 			if instance.Pool != "" {
@@ -623,14 +647,14 @@ var _ cmd.AutoCompleteCommand = &ServiceInstanceInfo{}
 
 type ServiceInstanceInfo struct {
 	ServiceInstanceCompletionMixIn
-	fs   *pflag.FlagSet
-	json bool
+	fs     *pflag.FlagSet
+	output formatter.Output
 }
 
 func (c *ServiceInstanceInfo) Flags() *pflag.FlagSet {
 	if c.fs == nil {
 		c.fs = pflag.NewFlagSet("service-instance-info", pflag.ContinueOnError)
-		c.fs.BoolVar(&c.json, standards.FlagJSON, false, "Show JSON")
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -706,8 +730,11 @@ func (c ServiceInstanceInfo) Run(ctx *cmd.Context) error {
 
 	si.Status = string(bMsg)
 
-	if c.json {
-		return formatter.JSON(ctx.Stdout, si)
+	if !c.output.IsTable() {
+		return c.output.Print(ctx.Stdout, formatter.Printable{
+			Data:  si,
+			Names: func() []string { return []string{si.ServiceName + "/" + si.InstanceName} },
+		})
 	}
 
 	tabWriter := tabwriter.NewWriter(ctx.Stdout, 0, 0, 2, ' ', 0)
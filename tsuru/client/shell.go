@@ -19,6 +19,7 @@ import (
 	"github.com/tsuru/go-tsuruclient/pkg/config"
 	tsuruClientApp "github.com/tsuru/tsuru-client/tsuru/app"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
 	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
 	"golang.org/x/net/websocket"
 	terminal "golang.org/x/term"
@@ -116,6 +117,9 @@ func (c *ShellToContainerCmd) Run(context *cmd.Context) error {
 	if err != nil {
 		return err
 	}
+	if wsConfig.TlsConfig, err = credentials.CurrentTLSConfig(); err != nil {
+		return err
+	}
 	var token string
 	if token, err = config.DefaultTokenProvider.Token(); err == nil {
 		wsConfig.Header.Set("Authorization", "bearer "+token)
@@ -27,6 +27,11 @@ type S struct {
 func (s *S) SetUpSuite(c *check.C) {
 	form.DefaultEncoder = form.DefaultEncoder.UseJSONTags(false)
 	form.DefaultDecoder = form.DefaultDecoder.UseJSONTags(false)
+	os.Setenv("TSURU_HTTP_RETRIES", "0")
+}
+
+func (s *S) TearDownSuite(c *check.C) {
+	os.Unsetenv("TSURU_HTTP_RETRIES")
 }
 
 func (s *S) SetUpTest(c *check.C) {
@@ -8,15 +8,20 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/fatih/color"
 	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
 	"github.com/tsuru/go-tsuruclient/pkg/config"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
+	"github.com/tsuru/tsuru-client/tsuru/targets"
 )
 
 var errUndefinedTarget = errors.New(`No target defined. Please use target-add/target-set to define a target.
@@ -24,11 +29,29 @@ var errUndefinedTarget = errors.New(`No target defined. Please use target-add/ta
 For more details, please run "tsuru help target".`)
 
 type tsuruTarget struct {
-	label, url string
+	label, url  string
+	session     bool
+	description string
+	color       string
+	protected   bool
 }
 
 func (t *tsuruTarget) String() string {
-	return t.label + " (" + t.url + ")"
+	label := t.label
+	if attr, ok := targetColors[t.color]; ok {
+		label = color.New(attr).Sprint(label)
+	}
+	s := label + " (" + t.url + ")"
+	if t.session {
+		s += " [logged in]"
+	}
+	if t.protected {
+		s += " [protected]"
+	}
+	if t.description != "" {
+		s += " - " + t.description
+	}
+	return s
 }
 
 type targetSlice struct {
@@ -41,14 +64,23 @@ func newTargetSlice() *targetSlice {
 	return &targetSlice{current: -1}
 }
 
-func (t *targetSlice) add(label, url string) {
-	t.targets = append(t.targets, tsuruTarget{label: label, url: url})
+func (t *targetSlice) add(label, url string, session bool) {
+	t.targets = append(t.targets, tsuruTarget{label: label, url: url, session: session})
 	length := t.Len()
 	if length > 1 && !t.Less(t.Len()-2, t.Len()-1) {
 		t.sorted = false
 	}
 }
 
+// addTarget adds target along with the metadata shown in the list.
+func (t *targetSlice) addTarget(target targets.Target, session bool) {
+	t.add(target.Label, target.URL, session)
+	added := &t.targets[t.Len()-1]
+	added.description = target.Description
+	added.color = target.Color
+	added.protected = target.Protected
+}
+
 func (t *targetSlice) Len() int {
 	return len(t.targets)
 }
@@ -174,6 +206,20 @@ func GetURL(path string) (string, error) {
 	return GetURLVersion("1.0", path)
 }
 
+// targetDefaults returns the team and pool set as defaults for the current
+// target by target-add or target-update.
+func targetDefaults() (team, pool string) {
+	current, err := ReadTarget()
+	if err != nil {
+		return "", ""
+	}
+	target, err := targets.FindByURL(current)
+	if err != nil || target == nil {
+		return "", ""
+	}
+	return target.Team, target.Pool
+}
+
 // WriteTarget writes the given endpoint to the target file.
 func WriteTarget(t string) error {
 	targetPath := config.JoinWithUserDir(".tsuru", "target")
@@ -189,16 +235,141 @@ func WriteTarget(t string) error {
 	return nil
 }
 
+const targetTLSUsage = "[--ca-file <file>] [--client-cert <file> --client-key <file>] [--insecure-skip-verify]"
+
+// targetTLSFlags holds the TLS settings given to target-add and
+// target-update.
+type targetTLSFlags struct {
+	settings credentials.TLS
+}
+
+func (f *targetTLSFlags) register(fs *pflag.FlagSet) {
+	fs.StringVar(&f.settings.CAFile, "ca-file", "", "PEM file with the certificate authorities trusted for the target, in addition to the system ones")
+	fs.StringVar(&f.settings.ClientCert, "client-cert", "", "PEM file with the client certificate sent to the target")
+	fs.StringVar(&f.settings.ClientKey, "client-key", "", "PEM file with the key of the client certificate")
+	fs.BoolVar(&f.settings.InsecureSkipVerify, "insecure-skip-verify", false, "Do not verify the certificate of the target. Use only for testing")
+}
+
+// merge returns current updated with the flags set in fs, with paths made
+// absolute so they don't depend on the working directory.
+func (f *targetTLSFlags) merge(fs *pflag.FlagSet, current credentials.TLS) (credentials.TLS, error) {
+	if fs == nil {
+		return current, nil
+	}
+	paths := map[string]*string{
+		"ca-file":     &current.CAFile,
+		"client-cert": &current.ClientCert,
+		"client-key":  &current.ClientKey,
+	}
+	values := map[string]string{
+		"ca-file":     f.settings.CAFile,
+		"client-cert": f.settings.ClientCert,
+		"client-key":  f.settings.ClientKey,
+	}
+	for name, path := range paths {
+		if !fs.Changed(name) {
+			continue
+		}
+		*path = values[name]
+		if *path == "" {
+			continue
+		}
+		abs, err := filepath.Abs(*path)
+		if err != nil {
+			return current, err
+		}
+		*path = abs
+	}
+	if fs.Changed("insecure-skip-verify") {
+		current.InsecureSkipVerify = f.settings.InsecureSkipVerify
+	}
+	return current, current.Validate()
+}
+
+const targetMetadataUsage = "[--description <text>] [--team <team>] [--pool <pool>] [--color <color>] [--protected]"
+
+// targetColors are the colors accepted by --color.
+var targetColors = map[string]color.Attribute{
+	"black":      color.FgBlack,
+	"red":        color.FgRed,
+	"green":      color.FgGreen,
+	"yellow":     color.FgYellow,
+	"blue":       color.FgBlue,
+	"magenta":    color.FgMagenta,
+	"cyan":       color.FgCyan,
+	"white":      color.FgWhite,
+	"hi-black":   color.FgHiBlack,
+	"hi-red":     color.FgHiRed,
+	"hi-green":   color.FgHiGreen,
+	"hi-yellow":  color.FgHiYellow,
+	"hi-blue":    color.FgHiBlue,
+	"hi-magenta": color.FgHiMagenta,
+	"hi-cyan":    color.FgHiCyan,
+	"hi-white":   color.FgHiWhite,
+}
+
+// targetMetadataFlags holds the metadata given to target-add and
+// target-update.
+type targetMetadataFlags struct {
+	metadata targets.Target
+}
+
+func (f *targetMetadataFlags) register(fs *pflag.FlagSet) {
+	fs.StringVar(&f.metadata.Description, "description", "", "Description of the target")
+	fs.StringVar(&f.metadata.Team, "team", "", "Team used by default when creating apps and jobs on the target")
+	fs.StringVar(&f.metadata.Pool, "pool", "", "Pool used by default when creating apps and jobs on the target")
+	fs.StringVar(&f.metadata.Color, "color", "", `Color of the target label in target-list, like "red" or "hi-green"`)
+	fs.BoolVar(&f.metadata.Protected, "protected", false, "Require confirmation before sending any change to the target")
+}
+
+// merge returns current updated with the flags set in fs.
+func (f *targetMetadataFlags) merge(fs *pflag.FlagSet, current targets.Target) (targets.Target, error) {
+	if fs == nil {
+		return current, nil
+	}
+	values := map[string]struct {
+		dst *string
+		src string
+	}{
+		"description": {&current.Description, f.metadata.Description},
+		"team":        {&current.Team, f.metadata.Team},
+		"pool":        {&current.Pool, f.metadata.Pool},
+		"color":       {&current.Color, strings.ToLower(f.metadata.Color)},
+	}
+	for name, value := range values {
+		if fs.Changed(name) {
+			*value.dst = strings.TrimSpace(value.src)
+		}
+	}
+	if fs.Changed("protected") {
+		current.Protected = f.metadata.Protected
+	}
+	if _, ok := targetColors[current.Color]; current.Color != "" && !ok {
+		names := make([]string, 0, len(targetColors))
+		for name := range targetColors {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return current, errors.Errorf("invalid color %q, supported colors are: %s", current.Color, strings.Join(names, ", "))
+	}
+	return current, nil
+}
+
 type TargetAdd struct {
-	fs  *pflag.FlagSet
-	set bool
+	fs       *pflag.FlagSet
+	set      bool
+	tls      targetTLSFlags
+	metadata targetMetadataFlags
 }
 
 func (t *TargetAdd) Info() *cmd.Info {
 	return &cmd.Info{
-		Name:    "target-add",
-		Usage:   "<label> <target> [--set-current|-s]",
-		Desc:    "Adds a new entry to the list of available targets",
+		Name:  "target-add",
+		Usage: "<label> <target> [--set-current|-s] " + targetTLSUsage + " " + targetMetadataUsage,
+		Desc: `Adds a new entry to the list of available targets.
+
+Targets marked with --protected require confirmation before any command
+changes something on them.`,
 		MinArgs: 2,
 	}
 }
@@ -211,10 +382,30 @@ func (t *TargetAdd) Run(ctx *cmd.Context) error {
 	}
 	label = ctx.Args[0]
 	target = ctx.Args[1]
-	err := WriteOnTargetList(label, target)
+	tlsSettings, err := t.tls.merge(t.fs, credentials.TLS{})
+	if err != nil {
+		return err
+	}
+	metadata, err := t.metadata.merge(t.fs, targets.Target{})
 	if err != nil {
 		return err
 	}
+	label = strings.TrimSpace(label)
+	target = strings.TrimSpace(target)
+	err = targets.Update(func(list map[string]targets.Target) error {
+		if _, exists := list[label]; exists {
+			return errors.New("Target label provided already exists")
+		}
+		metadata.Label, metadata.URL = label, target
+		list[label] = metadata
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err = credentials.WriteTLS(strings.TrimSpace(label), tlsSettings); err != nil {
+		return err
+	}
 	fmt.Fprintf(ctx.Stdout, "New target %s -> %s added to target list", label, target)
 	if t.set {
 		WriteTarget(target)
@@ -228,13 +419,17 @@ func (t *TargetAdd) Flags() *pflag.FlagSet {
 	if t.fs == nil {
 		t.fs = pflag.NewFlagSet("target-add", pflag.ExitOnError)
 		t.fs.BoolVarP(&t.set, "set-current", "s", false, "Add and define the target as the current target")
+		t.tls.register(t.fs)
+		t.metadata.register(t.fs)
 	}
 	return t.fs
 }
 
 type TargetUpdate struct {
-	fs  *pflag.FlagSet
-	set bool
+	fs       *pflag.FlagSet
+	set      bool
+	tls      targetTLSFlags
+	metadata targetMetadataFlags
 }
 
 func (t *TargetUpdate) Info() *cmd.Info {
@@ -242,8 +437,12 @@ func (t *TargetUpdate) Info() *cmd.Info {
 		Name:    "target-update",
 		MinArgs: 2,
 		MaxArgs: 2,
-		Usage:   "<label> <target> [--set-current|-s]",
-		Desc:    "Updates an existing entry in the list of available targets",
+		Usage:   "<label> <target> [--set-current|-s] " + targetTLSUsage + " " + targetMetadataUsage,
+		Desc: `Updates an existing entry in the list of available targets.
+
+Only the TLS settings and metadata given as flags are changed, the others are
+kept. When the URL changes, the credentials stored for the target are removed,
+so they are never sent to another server, and a new login is needed.`,
 	}
 }
 
@@ -253,19 +452,49 @@ func (t *TargetUpdate) Run(ctx *cmd.Context) error {
 	}
 	targetLabelToUpdate := strings.TrimSpace(ctx.Args[0])
 	newTargetURL := strings.TrimSpace(ctx.Args[1])
-	targets, err := getTargets()
+	current, err := targets.Get(targetLabelToUpdate)
 	if err != nil {
 		return err
 	}
-	if _, ok := targets[targetLabelToUpdate]; !ok {
+	if current == nil {
 		return errors.New("Target label provided does not exist")
 	}
-	targets[targetLabelToUpdate] = newTargetURL
-	if err = resetTargetList(); err != nil {
+	currentTLS, err := credentials.ReadTLS(targetLabelToUpdate)
+	if err != nil {
 		return err
 	}
-	for label, target := range targets {
-		if err := WriteOnTargetList(label, target); err != nil {
+	tlsSettings, err := t.tls.merge(t.fs, currentTLS)
+	if err != nil {
+		return err
+	}
+	if _, err = t.metadata.merge(t.fs, *current); err != nil {
+		return err
+	}
+	err = targets.Update(func(list map[string]targets.Target) error {
+		target, ok := list[targetLabelToUpdate]
+		if !ok {
+			return errors.New("Target label provided does not exist")
+		}
+		target, err := t.metadata.merge(t.fs, target)
+		if err != nil {
+			return err
+		}
+		target.URL = newTargetURL
+		list[targetLabelToUpdate] = target
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err = credentials.WriteTLS(targetLabelToUpdate, tlsSettings); err != nil {
+		return err
+	}
+	// The credentials are kept by label, they must not be sent to another
+	// server.
+	hadSession := false
+	if newTargetURL != current.URL {
+		hadSession = credentials.HasSession(targetLabelToUpdate)
+		if err = credentials.Remove(targetLabelToUpdate); err != nil {
 			return err
 		}
 	}
@@ -277,6 +506,9 @@ func (t *TargetUpdate) Run(ctx *cmd.Context) error {
 		fmt.Fprint(ctx.Stdout, " and defined as the current target")
 	}
 	fmt.Fprintln(ctx.Stdout)
+	if hadSession {
+		fmt.Fprintf(ctx.Stdout, "The session on %s was removed as the URL changed, run \"tsuru login\" to start a new one.\n", targetLabelToUpdate)
+	}
 	return nil
 }
 
@@ -284,108 +516,65 @@ func (t *TargetUpdate) Flags() *pflag.FlagSet {
 	if t.fs == nil {
 		t.fs = pflag.NewFlagSet("target-add", pflag.ExitOnError)
 		t.fs.BoolVarP(&t.set, "set-current", "s", false, "Add and define the target as the current target")
+		t.tls.register(t.fs)
+		t.metadata.register(t.fs)
 	}
 	return t.fs
 }
 
-func resetTargetList() error {
-	targetsPath := config.JoinWithUserDir(".tsuru", "targets")
-	targetsFile, err := config.Filesystem().OpenFile(targetsPath, syscall.O_WRONLY|syscall.O_CREAT|syscall.O_TRUNC, 0o600)
-	if err != nil {
-		return err
-	}
-	defer targetsFile.Close()
-	return nil
-}
-
-// WriteOnTargetList writes the given target in the target list file.
+// WriteOnTargetList adds the given target to the target list.
 func WriteOnTargetList(label, target string) error {
 	label = strings.TrimSpace(label)
 	target = strings.TrimSpace(target)
-	targetExist, err := CheckIfTargetLabelExists(label)
-	if err != nil {
-		return err
-	}
-	if targetExist {
-		return errors.New("Target label provided already exists")
-	}
-	targetsPath := config.JoinWithUserDir(".tsuru", "targets")
-	targetsFile, err := config.Filesystem().OpenFile(targetsPath, syscall.O_RDWR|syscall.O_CREAT|syscall.O_APPEND, 0o600)
-	if err != nil {
-		return err
-	}
-	defer targetsFile.Close()
-	content := label + "\t" + target + "\n"
-	n, err := targetsFile.WriteString(content)
-	if n != len(content) || err != nil {
-		return errors.New("Failed to write the target file")
-	}
-	return nil
+	return targets.Update(func(list map[string]targets.Target) error {
+		if _, exists := list[label]; exists {
+			return errors.New("Target label provided already exists")
+		}
+		list[label] = targets.Target{Label: label, URL: target}
+		return nil
+	})
 }
 
 func CheckIfTargetLabelExists(label string) (bool, error) {
-	targets, err := getTargets()
+	target, err := targets.Get(label)
 	if err != nil {
 		return false, err
 	}
-	_, exists := targets[label]
-	if exists {
-		return true, nil
-	}
-	return false, nil
+	return target != nil, nil
 }
 
 func getTargets() (map[string]string, error) {
-	targets := map[string]string{}
-	legacyTargetsPath := config.JoinWithUserDir(".tsuru_targets")
-	targetsPath := config.JoinWithUserDir(".tsuru", "targets")
-	err := config.Filesystem().MkdirAll(config.JoinWithUserDir(".tsuru"), 0o700)
-	if err != nil {
-		return nil, err
-	}
-	var legacy bool
-	f, err := config.Filesystem().Open(targetsPath)
-	if os.IsNotExist(err) {
-		f, err = config.Filesystem().Open(legacyTargetsPath)
-		legacy = true
-	}
-	if err == nil {
-		defer f.Close()
-		if b, err := io.ReadAll(f); err == nil {
-			targetLines := strings.Split(strings.TrimSpace(string(b)), "\n")
-			for i := range targetLines {
-				targetSplit := strings.Split(targetLines[i], "\t")
-
-				if len(targetSplit) == 2 {
-					targets[targetSplit[0]] = targetSplit[1]
-				}
-			}
-		}
-	}
-	if legacy {
-		copyTargetFiles()
-	}
-	return targets, nil
+	return targets.URLs()
 }
 
+// copyTargetFiles copies the current target of older versions, kept in
+// ~/.tsuru_target. Their target lists are migrated by the targets package.
 func copyTargetFiles() {
 	config.Filesystem().MkdirAll(config.JoinWithUserDir(".tsuru"), 0o700)
-	if src, err := config.Filesystem().Open(config.JoinWithUserDir(".tsuru_targets")); err == nil {
-		defer src.Close()
-		if dst, err := config.Filesystem().OpenFile(config.JoinWithUserDir(".tsuru", "targets"), syscall.O_WRONLY|syscall.O_CREAT|syscall.O_TRUNC, 0o600); err == nil {
-			defer dst.Close()
-			io.Copy(dst, src)
-		}
-	}
 	if target, err := readTarget(config.JoinWithUserDir(".tsuru_target")); err == nil {
 		WriteTarget(target)
 	}
 }
 
-type TargetList struct{}
+type TargetList struct {
+	// ClientVersion is compared to the versions supported by the targets
+	// when checking them.
+	ClientVersion string
+
+	fs      *pflag.FlagSet
+	check   bool
+	timeout time.Duration
+}
 
 func (t *TargetList) Info() *cmd.Info {
-	desc := `Displays the list of targets, marking the current.
+	desc := `Displays the list of targets, marking the current, the protected ones and
+the ones with a session, along with their descriptions. Each target keeps its
+own session, created by [[tsuru login]].
+
+With --check, every target is probed concurrently, displaying whether it's
+reachable, its latency and version, whether this client is supported by it,
+its default authentication scheme and whether the stored session is still
+accepted. Targets not answering within --timeout are marked as unreachable.
 
 Other commands related to target:
 
@@ -398,14 +587,37 @@ Other commands related to target:
 	}
 }
 
+func (t *TargetList) Flags() *pflag.FlagSet {
+	if t.fs == nil {
+		t.fs = pflag.NewFlagSet("target-list", pflag.ExitOnError)
+		t.fs.BoolVar(&t.check, "check", false, "Probe the targets for their health, version and session")
+		t.fs.DurationVar(&t.timeout, "timeout", defaultTargetCheckTimeout, "Time to wait for each target when checking them")
+	}
+	return t.fs
+}
+
 func (t *TargetList) Run(ctx *cmd.Context) error {
-	slice := newTargetSlice()
-	targets, err := getTargets()
+	list, err := targets.List()
 	if err != nil {
 		return err
 	}
-	for label, target := range targets {
-		slice.add(label, target)
+	if t.check {
+		urls := make(map[string]string, len(list))
+		for _, target := range list {
+			urls[target.Label] = target.URL
+		}
+		current, _ := ReadTarget()
+		timeout := t.timeout
+		if timeout <= 0 {
+			timeout = defaultTargetCheckTimeout
+		}
+		results := checkTargets(urls, current, t.ClientVersion, timeout)
+		ctx.Stdout.Write(renderTargetChecks(results, t.ClientVersion))
+		return nil
+	}
+	slice := newTargetSlice()
+	for _, target := range list {
+		slice.addTarget(target, credentials.HasSession(target.Label))
 	}
 	if current, err := ReadTarget(); err == nil {
 		slice.setCurrent(current)
@@ -431,31 +643,23 @@ func (t *TargetRemove) Run(ctx *cmd.Context) error {
 		return errors.New("Invalid arguments")
 	}
 	targetLabelToRemove := strings.TrimSpace(ctx.Args[0])
-	targets, err := getTargets()
-	if err != nil {
+	target, err := targets.Get(targetLabelToRemove)
+	if err != nil || target == nil {
 		return err
 	}
-	var turl string
-	for label, url := range targets {
-		if label == targetLabelToRemove {
-			turl = url
-			delete(targets, label)
-		}
-	}
-	if turl != "" {
-		var current string
-		if current, err = ReadTarget(); err == nil && current == turl {
-			deleteTargetFile()
-		}
+	if err = credentials.Remove(targetLabelToRemove); err != nil {
+		return err
 	}
-	err = resetTargetList()
-	if err != nil {
+	if err = credentials.RemoveTLS(targetLabelToRemove); err != nil {
 		return err
 	}
-	for label, target := range targets {
-		WriteOnTargetList(label, target)
+	if current, err := ReadTarget(); err == nil && current == target.URL {
+		deleteTargetFile()
 	}
-	return nil
+	return targets.Update(func(list map[string]targets.Target) error {
+		delete(list, targetLabelToRemove)
+		return nil
+	})
 }
 
 type TargetSet struct{}
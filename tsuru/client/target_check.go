@@ -0,0 +1,221 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tsuru/tablecli"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
+	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
+	"github.com/tsuru/tsuru-client/tsuru/targets"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+)
+
+const defaultTargetCheckTimeout = 5 * time.Second
+
+// Session states reported by target-list --check.
+const (
+	sessionValid       = "valid"
+	sessionExpired     = "expired"
+	sessionRefreshable = "expired, refreshable"
+	sessionNone        = "none"
+	sessionUnknown     = "unknown"
+)
+
+// targetCheck is the result of probing a target.
+type targetCheck struct {
+	label, url string
+	current    bool
+
+	err       error
+	latency   time.Duration
+	version   string
+	supported string
+	scheme    string
+	session   string
+}
+
+// checkTargets probes every target concurrently, returning the results
+// sorted by label. URLs are compared and requested as the other commands do,
+// with the http:// scheme added when missing.
+func checkTargets(urls map[string]string, current, clientVersion string, timeout time.Duration) []targetCheck {
+	results := make([]targetCheck, 0, len(urls))
+	current = targets.NormalizeURL(current)
+	for label, url := range urls {
+		results = append(results, targetCheck{label: label, url: url, current: targets.NormalizeURL(url) == current})
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].label < results[j].label })
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(result *targetCheck) {
+			defer wg.Done()
+			result.probe(clientVersion, timeout)
+		}(&results[i])
+	}
+	wg.Wait()
+	return results
+}
+
+func (t *targetCheck) probe(clientVersion string, timeout time.Duration) {
+	settings, err := credentials.ReadTLS(t.label)
+	if err != nil {
+		t.err = err
+		return
+	}
+	transport, err := tsuruHTTP.TLSTransport(settings)
+	if err != nil {
+		t.err = fmt.Errorf("invalid TLS settings: %w", err)
+		return
+	}
+	client := &http.Client{Transport: transport, Timeout: timeout}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var info map[string]string
+	start := time.Now()
+	resp, err := t.get(ctx, client, clientVersion, "/1.0/info", "", &info)
+	t.latency = time.Since(start)
+	if err != nil {
+		t.err = err
+		return
+	}
+	t.version = info["version"]
+	t.supported = resp.Header.Get("Supported-Tsuru")
+
+	var schemes []authTypes.SchemeInfo
+	if _, err = t.get(ctx, client, clientVersion, "/1.18/auth/schemes", "", &schemes); err == nil {
+		for _, scheme := range schemes {
+			if scheme.Default || t.scheme == "" {
+				t.scheme = scheme.Name
+			}
+		}
+	}
+	t.session = t.checkSession(ctx, client, clientVersion)
+}
+
+// checkSession tells whether the server accepts the credentials stored for
+// the target. Expired OIDC tokens are not refreshed, as refreshing them
+// would change the stored session.
+func (t *targetCheck) checkSession(ctx context.Context, client *http.Client, clientVersion string) string {
+	var authorization string
+	tokenV2, err := credentials.ReadTokenV2(t.label)
+	if err != nil {
+		return sessionUnknown
+	}
+	if tokenV2 != nil && tokenV2.Scheme == "oidc" && tokenV2.OAuth2Token != nil {
+		if !tokenV2.OAuth2Token.Valid() {
+			if tokenV2.OAuth2Token.RefreshToken != "" {
+				return sessionRefreshable
+			}
+			return sessionExpired
+		}
+		authorization = tokenV2.OAuth2Token.Type() + " " + tokenV2.OAuth2Token.AccessToken
+	} else {
+		token, err := credentials.ReadTokenV1(t.label)
+		if err != nil {
+			return sessionUnknown
+		}
+		if token == "" {
+			return sessionNone
+		}
+		authorization = "bearer " + token
+	}
+	resp, err := t.get(ctx, client, clientVersion, "/1.0/users/info", authorization, nil)
+	switch {
+	case resp != nil && resp.StatusCode == http.StatusUnauthorized:
+		return sessionExpired
+	case err != nil:
+		return sessionUnknown
+	}
+	return sessionValid
+}
+
+// get sends a GET request to path on the target, decoding the JSON response
+// into result when it's not nil. Responses with error statuses are returned
+// along with the error.
+func (t *targetCheck) get(ctx context.Context, client *http.Client, clientVersion, path, authorization string, result interface{}) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targets.NormalizeURL(t.url)+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "tsuru-client/"+clientVersion)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return resp, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	if result != nil {
+		if err = json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return resp, fmt.Errorf("invalid response: %w", err)
+		}
+	}
+	return resp, nil
+}
+
+func (t *targetCheck) status() string {
+	if t.err == nil {
+		return "ok"
+	}
+	return "unreachable: " + tsuruHTTP.UnwrapErr(t.err).Error()
+}
+
+func (t *targetCheck) clientStatus(clientVersion string) string {
+	if t.err != nil {
+		return "-"
+	}
+	if tsuruHTTP.ValidateVersion(t.supported, clientVersion) {
+		return "ok"
+	}
+	return "upgrade to >= " + t.supported
+}
+
+func renderTargetChecks(results []targetCheck, clientVersion string) []byte {
+	table := tablecli.NewTable()
+	table.Headers = tablecli.Row([]string{"Target", "Status", "Latency", "Version", "Client", "Auth", "Session"})
+	for _, result := range results {
+		label := "  " + result.label
+		if result.current {
+			label = "* " + result.label
+		}
+		latency, version, scheme, session := "-", "-", "-", "-"
+		if result.err == nil {
+			latency = fmt.Sprintf("%dms", result.latency.Milliseconds())
+			version = valueOrDash(result.version)
+			scheme = valueOrDash(result.scheme)
+			session = result.session
+		}
+		table.AddRow(tablecli.Row([]string{
+			label + " (" + result.url + ")",
+			result.status(),
+			latency,
+			version,
+			result.clientStatus(clientVersion),
+			scheme,
+			session,
+		}))
+	}
+	return table.Bytes()
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -6,13 +6,21 @@ package client
 
 import (
 	"bytes"
+	"encoding/pem"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"regexp"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/tsuru/go-tsuruclient/pkg/config"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
+	"github.com/tsuru/tsuru-client/tsuru/targets"
 	"github.com/tsuru/tsuru/fs/fstest"
 	check "gopkg.in/check.v1"
 )
@@ -24,6 +32,16 @@ func readRecordedTarget(fs *fstest.RecordingFs) string {
 	return string(b)
 }
 
+// newTargetsFs returns a filesystem holding the given flat target list, as
+// written by older versions.
+func newTargetsFs(content string) *fstest.RecordingFs {
+	rfs := &fstest.RecordingFs{}
+	f, _ := rfs.Create(config.JoinWithUserDir(".tsuru", "targets"))
+	f.Write([]byte(content))
+	f.Close()
+	return rfs
+}
+
 func (s *S) TestWriteTarget(c *check.C) {
 	rfs := &fstest.RecordingFs{}
 	config.SetFileSystem(rfs)
@@ -158,7 +176,7 @@ func (s *S) TestGetTarget(c *check.C) {
 
 func (s *S) TestGetTargetLabel(c *check.C) {
 	os.Unsetenv("TSURU_TARGET")
-	rfs := &fstest.RecordingFs{FileContent: "first\thttp://tsuru.io/\nsecond\thttp://tsuru.google.com"}
+	rfs := newTargetsFs("first\thttp://tsuru.io/\nsecond\thttp://tsuru.google.com")
 	config.SetFileSystem(rfs)
 	defer func() {
 		config.ResetFileSystem()
@@ -182,7 +200,7 @@ func (s *S) TestGetTargetLabel(c *check.C) {
 
 func (s *S) TestGetTargetLabelStableWithRepeatedValues(c *check.C) {
 	os.Unsetenv("TSURU_TARGET")
-	rfs := &fstest.RecordingFs{FileContent: "2second\thttp://tsuru.io/\n1first\thttp://tsuru.io/"}
+	rfs := newTargetsFs("2second\thttp://tsuru.io/\n1first\thttp://tsuru.io/")
 	config.SetFileSystem(rfs)
 	defer func() {
 		config.ResetFileSystem()
@@ -206,7 +224,7 @@ func (s *S) TestGetTargetLabelStableWithRepeatedValues(c *check.C) {
 
 func (s *S) TestGetTargetLabelNotFound(c *check.C) {
 	os.Setenv("TSURU_TARGET", "http://notfound.io")
-	rfs := &fstest.RecordingFs{FileContent: "first\thttp://tsuru.io/\nsecond\thttp://tsuru.google.com"}
+	rfs := newTargetsFs("first\thttp://tsuru.io/\nsecond\thttp://tsuru.google.com")
 	config.SetFileSystem(rfs)
 	defer func() {
 		config.ResetFileSystem()
@@ -291,9 +309,12 @@ func (s *S) TestGetURLUndefinedTarget(c *check.C) {
 
 func (s *S) TestTargetAddInfo(c *check.C) {
 	expected := &cmd.Info{
-		Name:    "target-add",
-		Usage:   "<label> <target> [--set-current|-s]",
-		Desc:    "Adds a new entry to the list of available targets",
+		Name:  "target-add",
+		Usage: "<label> <target> [--set-current|-s] [--ca-file <file>] [--client-cert <file> --client-key <file>] [--insecure-skip-verify] [--description <text>] [--team <team>] [--pool <pool>] [--color <color>] [--protected]",
+		Desc: `Adds a new entry to the list of available targets.
+
+Targets marked with --protected require confirmation before any command
+changes something on them.`,
 		MinArgs: 2,
 	}
 	targetAdd := &TargetAdd{}
@@ -301,7 +322,7 @@ func (s *S) TestTargetAddInfo(c *check.C) {
 }
 
 func (s *S) TestTargetAddRun(c *check.C) {
-	rfs := &fstest.RecordingFs{FileContent: "default   http://tsuru.google.com"}
+	rfs := newTargetsFs("default   http://tsuru.google.com")
 	config.SetFileSystem(rfs)
 	defer func() {
 		config.ResetFileSystem()
@@ -333,7 +354,7 @@ func (s *S) TestTargetAddRunOnlyOneArg(c *check.C) {
 
 func (s *S) TestTargetAddWithSet(c *check.C) {
 	os.Unsetenv("TSURU_TARGET")
-	rfs := &fstest.RecordingFs{FileContent: "old\thttp://tsuru.io"}
+	rfs := newTargetsFs("old\thttp://tsuru.io")
 	config.SetFileSystem(rfs)
 	defer func() {
 		config.ResetFileSystem()
@@ -366,11 +387,149 @@ func (s *S) TestTargetAddFlags(c *check.C) {
 	c.Check(set.Shorthand, check.Equals, "s")
 }
 
+func writeCAFile(c *check.C) string {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	caFile := filepath.Join(c.MkDir(), "ca.pem")
+	err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600)
+	c.Assert(err, check.IsNil)
+	return caFile
+}
+
+func (s *S) TestTargetAddWithTLS(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+	caFile := writeCAFile(c)
+	context := &cmd.Context{
+		Args:   []string{"private", "https://tsuru.internal"},
+		Stdout: &bytes.Buffer{},
+	}
+	targetAdd := &TargetAdd{}
+	targetAdd.Flags().Parse([]string{"--ca-file", caFile, "--insecure-skip-verify"})
+	err := targetAdd.Run(context)
+	c.Assert(err, check.IsNil)
+	settings, err := credentials.ReadTLS("private")
+	c.Assert(err, check.IsNil)
+	c.Assert(settings, check.Equals, credentials.TLS{CAFile: caFile, InsecureSkipVerify: true})
+}
+
+func (s *S) TestTargetAddWithInvalidTLS(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+	targetAdd := &TargetAdd{}
+	targetAdd.Flags().Parse([]string{"--client-cert", "cert.pem"})
+	err := targetAdd.Run(&cmd.Context{Args: []string{"private", "https://tsuru.internal"}})
+	c.Assert(err, check.ErrorMatches, "client certificate and key must be provided together")
+	exists, err := CheckIfTargetLabelExists("private")
+	c.Assert(err, check.IsNil)
+	c.Assert(exists, check.Equals, false)
+}
+
+func (s *S) TestTargetUpdateWithTLS(c *check.C) {
+	rfs := &fstest.RecordingFs{}
+	f, _ := rfs.Create(config.JoinWithUserDir(".tsuru", "targets"))
+	f.Write([]byte("private\thttps://tsuru.internal"))
+	f.Close()
+	config.SetFileSystem(rfs)
+	defer config.ResetFileSystem()
+	caFile := writeCAFile(c)
+	c.Assert(credentials.WriteTLS("private", credentials.TLS{CAFile: caFile}), check.IsNil)
+	context := &cmd.Context{
+		Args:   []string{"private", "https://tsuru.internal"},
+		Stdout: &bytes.Buffer{},
+	}
+	targetUpdate := &TargetUpdate{}
+	targetUpdate.Flags().Parse([]string{"--insecure-skip-verify"})
+	err := targetUpdate.Run(context)
+	c.Assert(err, check.IsNil)
+	settings, err := credentials.ReadTLS("private")
+	c.Assert(err, check.IsNil)
+	c.Assert(settings, check.Equals, credentials.TLS{CAFile: caFile, InsecureSkipVerify: true})
+
+	targetUpdate = &TargetUpdate{}
+	targetUpdate.Flags().Parse([]string{"--ca-file", "", "--insecure-skip-verify=false"})
+	err = targetUpdate.Run(context)
+	c.Assert(err, check.IsNil)
+	settings, err = credentials.ReadTLS("private")
+	c.Assert(err, check.IsNil)
+	c.Assert(settings.IsZero(), check.Equals, true)
+}
+
+func (s *S) TestTargetAddWithMetadata(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+	context := &cmd.Context{
+		Args:   []string{"prod", "https://tsuru.example.com"},
+		Stdout: &bytes.Buffer{},
+	}
+	targetAdd := &TargetAdd{}
+	targetAdd.Flags().Parse([]string{"--description", "Production cluster", "--team", "platform", "--pool", "prod", "--color", "RED", "--protected"})
+	err := targetAdd.Run(context)
+	c.Assert(err, check.IsNil)
+	target, err := targets.Get("prod")
+	c.Assert(err, check.IsNil)
+	c.Assert(target, check.DeepEquals, &targets.Target{
+		Label:       "prod",
+		URL:         "https://tsuru.example.com",
+		Description: "Production cluster",
+		Team:        "platform",
+		Pool:        "prod",
+		Color:       "red",
+		Protected:   true,
+	})
+}
+
+func (s *S) TestTargetAddWithInvalidColor(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+	context := &cmd.Context{
+		Args:   []string{"prod", "https://tsuru.example.com"},
+		Stdout: &bytes.Buffer{},
+	}
+	targetAdd := &TargetAdd{}
+	targetAdd.Flags().Parse([]string{"--color", "purple"})
+	err := targetAdd.Run(context)
+	c.Assert(err, check.ErrorMatches, `invalid color "purple", supported colors are: black, blue, .*`)
+	target, err := targets.Get("prod")
+	c.Assert(err, check.IsNil)
+	c.Assert(target, check.IsNil)
+}
+
+func (s *S) TestTargetUpdateKeepsMetadata(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+	err := targets.Update(func(list map[string]targets.Target) error {
+		list["prod"] = targets.Target{URL: "https://old.example.com", Description: "Production", Team: "platform", Protected: true}
+		return nil
+	})
+	c.Assert(err, check.IsNil)
+	context := &cmd.Context{
+		Args:   []string{"prod", "https://tsuru.example.com"},
+		Stdout: &bytes.Buffer{},
+	}
+	targetUpdate := &TargetUpdate{}
+	targetUpdate.Flags().Parse([]string{"--team", "sre", "--protected=false"})
+	err = targetUpdate.Run(context)
+	c.Assert(err, check.IsNil)
+	target, err := targets.Get("prod")
+	c.Assert(err, check.IsNil)
+	c.Assert(target, check.DeepEquals, &targets.Target{
+		Label:       "prod",
+		URL:         "https://tsuru.example.com",
+		Description: "Production",
+		Team:        "sre",
+	})
+}
+
 func (s *S) TestTargetUpdateInfo(c *check.C) {
 	expected := &cmd.Info{
-		Name:    "target-update",
-		Usage:   "<label> <target> [--set-current|-s]",
-		Desc:    "Updates an existing entry in the list of available targets",
+		Name:  "target-update",
+		Usage: "<label> <target> [--set-current|-s] [--ca-file <file>] [--client-cert <file> --client-key <file>] [--insecure-skip-verify] [--description <text>] [--team <team>] [--pool <pool>] [--color <color>] [--protected]",
+		Desc: `Updates an existing entry in the list of available targets.
+
+Only the TLS settings and metadata given as flags are changed, the others are
+kept. When the URL changes, the credentials stored for the target are removed,
+so they are never sent to another server, and a new login is needed.`,
 		MinArgs: 2,
 		MaxArgs: 2,
 	}
@@ -448,6 +607,46 @@ func (s *S) TestTargetUpdateWithSetCurrent(c *check.C) {
 	c.Assert(target, check.Equals, "https://tsuru.new.google.com")
 }
 
+func (s *S) TestTargetUpdateRemovesCredentialsWhenURLChanges(c *check.C) {
+	rfs := &fstest.RecordingFs{}
+	f, err := rfs.Create(config.JoinWithUserDir(".tsuru", "targets"))
+	c.Assert(err, check.IsNil)
+	_, err = f.Write([]byte("first\thttp://tsuru.io/\ndefault\thttp://tsuru.google.com"))
+	c.Assert(err, check.IsNil)
+	c.Assert(f.Close(), check.IsNil)
+	config.SetFileSystem(rfs)
+	defer config.ResetFileSystem()
+	c.Assert(credentials.WriteTokenV1("first", "firsttoken"), check.IsNil)
+	c.Assert(credentials.WriteTokenV1("default", "defaulttoken"), check.IsNil)
+
+	var stdout bytes.Buffer
+	err = (&TargetUpdate{}).Run(&cmd.Context{Args: []string{"default", "https://evil.example.com"}, Stdout: &stdout})
+	c.Assert(err, check.IsNil)
+	c.Assert(stdout.String(), check.Equals, "Target default -> https://evil.example.com updated on target list\n"+
+		"The session on default was removed as the URL changed, run \"tsuru login\" to start a new one.\n")
+	c.Assert(credentials.HasSession("default"), check.Equals, false)
+	token, err := credentials.ReadTokenV1("default")
+	c.Assert(err, check.IsNil)
+	c.Assert(token, check.Equals, "")
+	c.Assert(credentials.HasSession("first"), check.Equals, true)
+}
+
+func (s *S) TestTargetUpdateSameURLKeepsCredentials(c *check.C) {
+	rfs := &fstest.RecordingFs{}
+	f, err := rfs.Create(config.JoinWithUserDir(".tsuru", "targets"))
+	c.Assert(err, check.IsNil)
+	_, err = f.Write([]byte("default\thttp://tsuru.google.com"))
+	c.Assert(err, check.IsNil)
+	c.Assert(f.Close(), check.IsNil)
+	config.SetFileSystem(rfs)
+	defer config.ResetFileSystem()
+	c.Assert(credentials.WriteTokenV1("default", "defaulttoken"), check.IsNil)
+
+	err = (&TargetUpdate{}).Run(&cmd.Context{Args: []string{"default", "http://tsuru.google.com"}, Stdout: io.Discard})
+	c.Assert(err, check.IsNil)
+	c.Assert(credentials.HasSession("default"), check.Equals, true)
+}
+
 func (s *S) TestTargetUpdateWithInvalidArguments(c *check.C) {
 	config.SetFileSystem(&fstest.RecordingFs{})
 	defer config.ResetFileSystem()
@@ -458,7 +657,7 @@ func (s *S) TestTargetUpdateWithInvalidArguments(c *check.C) {
 }
 
 func (s *S) TestTargetUpdateWithUnknownLabel(c *check.C) {
-	rfs := &fstest.RecordingFs{FileContent: "default\thttp://tsuru.google.com"}
+	rfs := newTargetsFs("default\thttp://tsuru.google.com")
 	config.SetFileSystem(rfs)
 	defer config.ResetFileSystem()
 
@@ -470,7 +669,7 @@ func (s *S) TestTargetUpdateWithUnknownLabel(c *check.C) {
 }
 
 func (s *S) TestIfTargetLabelExists(c *check.C) {
-	rfs := &fstest.RecordingFs{FileContent: "first\thttp://tsuru.io/\ndefault\thttp://tsuru.google.com"}
+	rfs := newTargetsFs("first\thttp://tsuru.io/\ndefault\thttp://tsuru.google.com")
 	config.SetFileSystem(rfs)
 	defer func() {
 		config.ResetFileSystem()
@@ -481,7 +680,7 @@ func (s *S) TestIfTargetLabelExists(c *check.C) {
 }
 
 func (s *S) TestIfTargetLabelDoesNotExist(c *check.C) {
-	rfs := &fstest.RecordingFs{FileContent: "first\thttp://tsuru.io/\ndefault\thttp://tsuru.google.com"}
+	rfs := newTargetsFs("first\thttp://tsuru.io/\ndefault\thttp://tsuru.google.com")
 	config.SetFileSystem(rfs)
 	defer func() {
 		config.ResetFileSystem()
@@ -492,7 +691,7 @@ func (s *S) TestIfTargetLabelDoesNotExist(c *check.C) {
 }
 
 func (s *S) TestGetTargets(c *check.C) {
-	rfs := &fstest.RecordingFs{FileContent: "first\thttp://tsuru.io/\ndefault\thttp://tsuru.google.com"}
+	rfs := newTargetsFs("first\thttp://tsuru.io/\ndefault\thttp://tsuru.google.com")
 	config.SetFileSystem(rfs)
 	defer func() {
 		config.ResetFileSystem()
@@ -531,11 +730,18 @@ func (s *S) TestGetTargetsLegacy(c *check.C) {
 	defer f.Close()
 	b, err := io.ReadAll(f)
 	c.Assert(err, check.IsNil)
-	c.Assert(string(b), check.Equals, content)
+	c.Assert(string(b), check.Equals, "default\thttp://tsuru.google.com\nfirst\thttp://tsuru.io/\n")
 }
 
 func (s *S) TestTargetInfo(c *check.C) {
-	desc := `Displays the list of targets, marking the current.
+	desc := `Displays the list of targets, marking the current, the protected ones and
+the ones with a session, along with their descriptions. Each target keeps its
+own session, created by [[tsuru login]].
+
+With --check, every target is probed concurrently, displaying whether it's
+reachable, its latency and version, whether this client is supported by it,
+its default authentication scheme and whether the stored session is still
+accepted. Targets not answering within --timeout are marked as unreachable.
 
 Other commands related to target:
 
@@ -580,24 +786,185 @@ other	http://other.tsuru.io`
 	c.Assert(got, check.Equals, expected)
 }
 
-func (s *S) TestResetTargetList(c *check.C) {
-	rfs := &fstest.RecordingFs{FileContent: "first\thttp://tsuru.io/\ndefault\thttp://tsuru.google.com"}
+func (s *S) TestTargetListRunWithSessions(c *check.C) {
+	os.Unsetenv("TSURU_TARGET")
+	rfs := &fstest.RecordingFs{}
+	f, _ := rfs.Create(config.JoinWithUserDir(".tsuru", "target"))
+	f.Write([]byte("http://tsuru.io"))
+	f.Close()
+	f, _ = rfs.Create(config.JoinWithUserDir(".tsuru", "targets"))
+	f.Write([]byte("first\thttp://tsuru.io\nother\thttp://other.tsuru.io"))
+	f.Close()
 	config.SetFileSystem(rfs)
 	defer func() {
 		config.ResetFileSystem()
 	}()
-	var expected = map[string]string{
-		"first":   "http://tsuru.io/",
-		"default": "http://tsuru.google.com",
+	c.Assert(credentials.WriteTokenV1("other", "othertoken"), check.IsNil)
+	expected := `* first (http://tsuru.io)
+  other (http://other.tsuru.io) [logged in]` + "\n"
+	context := &cmd.Context{
+		Stdout: &bytes.Buffer{},
 	}
-	got, err := getTargets()
+	err := (&TargetList{}).Run(context)
+	c.Assert(err, check.IsNil)
+	c.Assert(context.Stdout.(*bytes.Buffer).String(), check.Equals, expected)
+}
+
+func newCheckedTarget(supported, token string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/1.0/info":
+			w.Header().Set("Supported-Tsuru", supported)
+			w.Write([]byte(`{"version":"1.30.0"}`))
+		case "/1.18/auth/schemes":
+			w.Write([]byte(`[{"name":"native"},{"name":"oidc","default":true}]`))
+		case "/1.0/users/info":
+			if r.Header.Get("Authorization") != "bearer "+token {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"Email":"me@tsuru.io"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func (s *S) TestCheckTargets(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+	healthy := newCheckedTarget("1.2.0", "goodtoken")
+	defer healthy.Close()
+	outdated := newCheckedTarget("2.0.0", "othertoken")
+	defer outdated.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	c.Assert(credentials.WriteTokenV1("healthy", "goodtoken"), check.IsNil)
+	c.Assert(credentials.WriteTokenV1("outdated", "expiredtoken"), check.IsNil)
+	targets := map[string]string{
+		"healthy":  healthy.URL,
+		"outdated": outdated.URL,
+		"down":     down.URL,
+	}
+	results := checkTargets(targets, healthy.URL, "1.5.0", time.Second)
+	c.Assert(results, check.HasLen, 3)
+	c.Assert(results[0].label, check.Equals, "down")
+	c.Assert(results[0].err, check.NotNil)
+	c.Assert(results[0].status(), check.Matches, "unreachable: .*")
+	c.Assert(results[0].clientStatus("1.5.0"), check.Equals, "-")
+	c.Assert(results[1].label, check.Equals, "healthy")
+	c.Assert(results[1].current, check.Equals, true)
+	c.Assert(results[1].err, check.IsNil)
+	c.Assert(results[1].version, check.Equals, "1.30.0")
+	c.Assert(results[1].scheme, check.Equals, "oidc")
+	c.Assert(results[1].session, check.Equals, sessionValid)
+	c.Assert(results[1].clientStatus("1.5.0"), check.Equals, "ok")
+	c.Assert(results[2].label, check.Equals, "outdated")
+	c.Assert(results[2].current, check.Equals, false)
+	c.Assert(results[2].session, check.Equals, sessionExpired)
+	c.Assert(results[2].clientStatus("1.5.0"), check.Equals, "upgrade to >= 2.0.0")
+}
+
+func (s *S) TestCheckTargetsWithoutSession(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+	server := newCheckedTarget("", "token")
+	defer server.Close()
+	results := checkTargets(map[string]string{"first": server.URL}, "", "1.5.0", time.Second)
+	c.Assert(results, check.HasLen, 1)
+	c.Assert(results[0].session, check.Equals, sessionNone)
+	c.Assert(results[0].clientStatus("1.5.0"), check.Equals, "ok")
+}
+
+func (s *S) TestCheckTargetsTimeout(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer server.Close()
+	defer close(block)
+	results := checkTargets(map[string]string{"slow": server.URL}, "", "1.5.0", 50*time.Millisecond)
+	c.Assert(results, check.HasLen, 1)
+	c.Assert(results[0].err, check.NotNil)
+}
+
+func (s *S) TestTargetListRunWithMetadata(c *check.C) {
+	os.Unsetenv("TSURU_TARGET")
+	rfs := &fstest.RecordingFs{}
+	f, _ := rfs.Create(config.JoinWithUserDir(".tsuru", "target"))
+	f.Write([]byte("https://tsuru.example.com"))
+	f.Close()
+	config.SetFileSystem(rfs)
+	defer config.ResetFileSystem()
+	err := targets.Update(func(list map[string]targets.Target) error {
+		list["dev"] = targets.Target{URL: "http://localhost:8080", Color: "green"}
+		list["prod"] = targets.Target{URL: "https://tsuru.example.com", Description: "Production cluster", Protected: true}
+		return nil
+	})
 	c.Assert(err, check.IsNil)
-	c.Assert(got, check.HasLen, len(expected))
-	err = resetTargetList()
+	expected := `  dev (http://localhost:8080)
+* prod (https://tsuru.example.com) [protected] - Production cluster` + "\n"
+	context := &cmd.Context{
+		Stdout: &bytes.Buffer{},
+	}
+	err = (&TargetList{}).Run(context)
 	c.Assert(err, check.IsNil)
-	got, err = getTargets()
+	c.Assert(context.Stdout.(*bytes.Buffer).String(), check.Equals, expected)
+}
+
+func (s *S) TestTargetListRunCheck(c *check.C) {
+	os.Unsetenv("TSURU_TARGET")
+	server := newCheckedTarget("1.2.0", "goodtoken")
+	defer server.Close()
+	rfs := &fstest.RecordingFs{}
+	f, _ := rfs.Create(config.JoinWithUserDir(".tsuru", "target"))
+	f.Write([]byte(server.URL))
+	f.Close()
+	f, _ = rfs.Create(config.JoinWithUserDir(".tsuru", "targets"))
+	f.Write([]byte("first\t" + server.URL))
+	f.Close()
+	config.SetFileSystem(rfs)
+	defer config.ResetFileSystem()
+	c.Assert(credentials.WriteTokenV1("first", "goodtoken"), check.IsNil)
+	context := &cmd.Context{
+		Stdout: &bytes.Buffer{},
+	}
+	target := &TargetList{ClientVersion: "1.5.0"}
+	target.Flags().Parse([]string{"--check", "--timeout", "2s"})
+	err := target.Run(context)
+	c.Assert(err, check.IsNil)
+	got := context.Stdout.(*bytes.Buffer).String()
+	c.Assert(got, check.Matches, `(?s).*Target.*Status.*Latency.*Version.*Client.*Auth.*Session.*`)
+	c.Assert(got, check.Matches, `(?s).*\* first \(`+regexp.QuoteMeta(server.URL)+`\).*ok.*ms.*1\.30\.0.*ok.*oidc.*valid.*`)
+}
+
+func (s *S) TestTargetListRunCheckWithoutScheme(c *check.C) {
+	os.Unsetenv("TSURU_TARGET")
+	server := newCheckedTarget("1.2.0", "goodtoken")
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "http://")
+	rfs := &fstest.RecordingFs{}
+	f, _ := rfs.Create(config.JoinWithUserDir(".tsuru", "target"))
+	f.Write([]byte(server.URL))
+	f.Close()
+	f, _ = rfs.Create(config.JoinWithUserDir(".tsuru", "targets"))
+	f.Write([]byte("first\t" + address))
+	f.Close()
+	config.SetFileSystem(rfs)
+	defer config.ResetFileSystem()
+	c.Assert(credentials.WriteTokenV1("first", "goodtoken"), check.IsNil)
+	context := &cmd.Context{
+		Stdout: &bytes.Buffer{},
+	}
+	target := &TargetList{ClientVersion: "1.5.0"}
+	target.Flags().Parse([]string{"--check", "--timeout", "2s"})
+	err := target.Run(context)
 	c.Assert(err, check.IsNil)
-	c.Assert(got, check.DeepEquals, map[string]string{})
+	got := context.Stdout.(*bytes.Buffer).String()
+	c.Assert(got, check.Not(check.Matches), `(?s).*unreachable.*`)
+	c.Assert(got, check.Matches, `(?s).*\* first \(`+regexp.QuoteMeta(address)+`\).*ok.*ms.*1\.30\.0.*ok.*oidc.*valid.*`)
 }
 
 func (s *S) TestTargetRemoveInfo(c *check.C) {
@@ -613,7 +980,7 @@ func (s *S) TestTargetRemoveInfo(c *check.C) {
 }
 
 func (s *S) TestTargetRemove(c *check.C) {
-	rfs := &fstest.RecordingFs{FileContent: "first\thttp://tsuru.io/\ndefault\thttp://tsuru.google.com"}
+	rfs := newTargetsFs("first\thttp://tsuru.io/\ndefault\thttp://tsuru.google.com")
 	f, _ := rfs.Create(config.JoinWithUserDir(".tsuru", "target"))
 	f.Write([]byte("http://tsuru.google.com"))
 	f.Close()
@@ -646,6 +1013,24 @@ func (s *S) TestTargetRemove(c *check.C) {
 	c.Assert(hasKey, check.Equals, false)
 }
 
+func (s *S) TestTargetRemoveCredentials(c *check.C) {
+	os.Unsetenv("TSURU_TARGET")
+	rfs := &fstest.RecordingFs{}
+	f, _ := rfs.Create(config.JoinWithUserDir(".tsuru", "targets"))
+	f.Write([]byte("first\thttp://tsuru.io/\ndefault\thttp://tsuru.google.com"))
+	f.Close()
+	config.SetFileSystem(rfs)
+	defer func() {
+		config.ResetFileSystem()
+	}()
+	c.Assert(credentials.WriteTokenV1("first", "firsttoken"), check.IsNil)
+	c.Assert(credentials.WriteTokenV1("default", "defaulttoken"), check.IsNil)
+	err := (&TargetRemove{}).Run(&cmd.Context{Args: []string{"first"}})
+	c.Assert(err, check.IsNil)
+	c.Assert(credentials.HasSession("first"), check.Equals, false)
+	c.Assert(credentials.HasSession("default"), check.Equals, true)
+}
+
 func (s *S) TestTargetRemoveCurrentTarget(c *check.C) {
 	os.Unsetenv("TSURU_TARGET")
 	rfs := &fstest.RecordingFs{}
@@ -684,7 +1069,7 @@ func (s *S) TestTargetSetInfo(c *check.C) {
 }
 
 func (s *S) TestTargetSetRun(c *check.C) {
-	rfs := &fstest.RecordingFs{FileContent: "first\thttp://tsuru.io/\ndefault\thttp://tsuru.google.com"}
+	rfs := newTargetsFs("first\thttp://tsuru.io/\ndefault\thttp://tsuru.google.com")
 	config.SetFileSystem(rfs)
 	defer func() {
 		config.ResetFileSystem()
@@ -701,7 +1086,7 @@ func (s *S) TestTargetSetRun(c *check.C) {
 }
 
 func (s *S) TestTargetSetRunUnknowTarget(c *check.C) {
-	rfs := &fstest.RecordingFs{FileContent: "first\thttp://tsuru.io/\ndefault\thttp://tsuru.google.com"}
+	rfs := newTargetsFs("first\thttp://tsuru.io/\ndefault\thttp://tsuru.google.com")
 	config.SetFileSystem(rfs)
 	defer func() {
 		config.ResetFileSystem()
@@ -724,10 +1109,10 @@ func (s *S) TestNewTargetSlice(c *check.C) {
 func (s *S) TestTargetSliceAdd(c *check.C) {
 	var t targetSlice
 	t.sorted = true
-	t.add("default", "http://tsuru.io")
+	t.add("default", "http://tsuru.io", false)
 	c.Assert(t.targets, check.DeepEquals, []tsuruTarget{{label: "default", url: "http://tsuru.io"}})
 	c.Assert(t.sorted, check.Equals, true)
-	t.add("abc", "http://tsuru.io")
+	t.add("abc", "http://tsuru.io", false)
 	c.Assert(t.sorted, check.Equals, false)
 }
 
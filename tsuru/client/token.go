@@ -115,6 +115,8 @@ func (c *TokenUpdateCmd) Flags() *pflag.FlagSet {
 }
 
 type TokenListCmd struct {
+	output formatter.Output
+	fs     *pflag.FlagSet
 }
 
 func (c *TokenListCmd) Info() *cmd.Info {
@@ -124,6 +126,14 @@ func (c *TokenListCmd) Info() *cmd.Info {
 	}
 }
 
+func (c *TokenListCmd) Flags() *pflag.FlagSet {
+	if c.fs == nil {
+		c.fs = pflag.NewFlagSet("token-list", pflag.ExitOnError)
+		c.output.AddFlags(c.fs)
+	}
+	return c.fs
+}
+
 func (c *TokenListCmd) Run(ctx *cmd.Context) error {
 	apiClient, err := tsuruHTTP.TsuruClientFromEnvironment()
 	if err != nil {
@@ -131,10 +141,39 @@ func (c *TokenListCmd) Run(ctx *cmd.Context) error {
 	}
 	tokens, rsp, err := apiClient.AuthApi.TeamTokensList(context.TODO())
 	if err != nil {
-		if rsp != nil && rsp.StatusCode == http.StatusNoContent {
+		if rsp == nil || rsp.StatusCode != http.StatusNoContent {
+			return err
+		}
+		if c.output.IsTable() {
 			return nil
 		}
-		return err
+		tokens = []tsuru.TeamToken{}
+	}
+	if !c.output.IsTable() {
+		return c.output.Print(ctx.Stdout, formatter.Printable{
+			Data: tokens,
+			Names: func() []string {
+				names := make([]string, len(tokens))
+				for i, t := range tokens {
+					names[i] = t.TokenId
+				}
+				return names
+			},
+			Rows: func() ([]string, [][]string) {
+				rows := make([][]string, len(tokens))
+				for i, t := range tokens {
+					rows[i] = []string{
+						t.TokenId,
+						t.Team,
+						formatter.FormatDate(t.CreatedAt),
+						formatter.FormatDate(t.ExpiresAt),
+						formatter.FormatDate(t.LastAccess),
+						strings.ReplaceAll(formatRoles(t.Roles), "\n", ";"),
+					}
+				}
+				return []string{"tokenId", "team", "createdAt", "expiresAt", "lastAccess", "roles"}, rows
+			},
+		})
 	}
 	table := tablecli.Table{
 		Headers:       tablecli.Row{"Token ID", "Team", "Created At", "Expires At", "Last Access", "Roles"},
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/cezarsa/form"
@@ -180,7 +181,7 @@ type VolumeList struct {
 	fs         *pflag.FlagSet
 	filter     volumeFilter
 	simplified bool
-	json       bool
+	output     formatter.Output
 }
 
 func (c *VolumeList) Info() *cmd.Info {
@@ -195,13 +196,11 @@ func (c *VolumeList) Flags() *pflag.FlagSet {
 		c.fs = pflag.NewFlagSet("volume-list", pflag.ExitOnError)
 
 		c.fs.StringVarP(&c.filter.name, standards.FlagName, standards.ShortFlagName, "", "Filter volumes by name")
-		c.fs.StringVarP(&c.filter.pool, standards.FlagPool, standards.ShortFlagPool, "", "Filter volumes by pool")
+		c.fs.StringVar(&c.filter.pool, standards.FlagPool, "", "Filter volumes by pool"+formatter.MovedShorthandNote)
 		c.fs.StringVarP(&c.filter.plan, standards.FlagPlan, standards.ShortFlagPlan, "", "Filter volumes by plan")
 		c.fs.StringVarP(&c.filter.teamOwner, standards.FlagTeam, standards.ShortFlagTeam, "", "Filter volumes by team owner")
 		c.fs.BoolVarP(&c.simplified, standards.FlagOnlyName, standards.ShortFlagOnlyName, false, "Display only volumes name")
-
-		c.fs.BoolVar(&c.json, standards.FlagJSON, false, "Display in JSON format")
-
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -279,20 +278,44 @@ func (c *VolumeList) render(ctx *cmd.Context, volumes []volumeTypes.Volume) erro
 		return nil
 	}
 
-	if c.json {
-		return formatter.JSON(ctx.Stdout, volumes)
+	if !c.output.IsTable() {
+		return c.output.Print(ctx.Stdout, formatter.Printable{
+			Data: volumes,
+			Names: func() []string {
+				names := make([]string, len(volumes))
+				for i, v := range volumes {
+					names[i] = v.Name
+				}
+				return names
+			},
+			Rows: func() ([]string, [][]string) {
+				rows := make([][]string, len(volumes))
+				for i, v := range volumes {
+					rows[i] = []string{v.Name, v.Plan.Name, v.Pool, v.TeamOwner, v.Status}
+				}
+				return []string{"name", "plan", "pool", "team", "status"}, rows
+			},
+		})
 	}
 
+	wide := c.output.Wide()
 	tbl := tablecli.NewTable()
 	tbl.Headers = tablecli.Row{"Name", "Plan", "Pool", "Team"}
+	if wide {
+		tbl.Headers = append(tbl.Headers, "Status", "Binds")
+	}
 	tbl.LineSeparator = true
 	for _, v := range volumes {
-		tbl.AddRow(tablecli.Row{
+		row := tablecli.Row{
 			v.Name,
 			v.Plan.Name,
 			v.Pool,
 			v.TeamOwner,
-		})
+		}
+		if wide {
+			row = append(row, v.Status, strconv.Itoa(len(v.Binds)))
+		}
+		tbl.AddRow(row)
 	}
 	tbl.Sort()
 	fmt.Fprint(ctx.Stdout, tbl.String())
@@ -300,14 +323,14 @@ func (c *VolumeList) render(ctx *cmd.Context, volumes []volumeTypes.Volume) erro
 }
 
 type VolumeInfo struct {
-	fs   *pflag.FlagSet
-	json bool
+	fs     *pflag.FlagSet
+	output formatter.Output
 }
 
 func (c *VolumeInfo) Flags() *pflag.FlagSet {
 	if c.fs == nil {
 		c.fs = pflag.NewFlagSet("volume-info", pflag.ContinueOnError)
-		c.fs.BoolVar(&c.json, standards.FlagJSON, false, "Show JSON")
+		c.output.AddFlags(c.fs)
 	}
 	return c.fs
 }
@@ -351,8 +374,11 @@ func (c *VolumeInfo) Run(ctx *cmd.Context) error {
 		return err
 	}
 
-	if c.json {
-		return formatter.JSON(ctx.Stdout, volume)
+	if !c.output.IsTable() {
+		return c.output.Print(ctx.Stdout, formatter.Printable{
+			Data:  volume,
+			Names: func() []string { return []string{volume.Name} },
+		})
 	}
 
 	return c.render(ctx, volume)
@@ -0,0 +1,150 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-shellwords"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/tsuru/tsuru-client/tsuru/cmd/standards"
+)
+
+const aliasGroupID = "aliases"
+
+// reservedNames are the commands added by cobra itself.
+var reservedNames = []string{"help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd}
+
+// RegisterAlias registers name as a command running expansion, followed by
+// the arguments given to the alias. Aliases must be registered after every
+// other command, as they are refused when their names are taken by commands,
+// topics, shorthands or plugins.
+func (m *ManagerV2) RegisterAlias(name, expansion string) error {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, " \t\n") {
+		return fmt.Errorf("invalid alias name %q", name)
+	}
+	if m.nameTaken(name) {
+		return fmt.Errorf("alias %q shadows an existing command", name)
+	}
+	args, err := shellwords.Parse(expansion)
+	if err != nil {
+		return fmt.Errorf("invalid alias %q: %w", name, err)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("alias %q is empty", name)
+	}
+	target, _, err := m.rootCmd.Find(args)
+	if err != nil || target == m.rootCmd {
+		return fmt.Errorf("alias %q refers to unknown command %q", name, args[0])
+	}
+	if target.GroupID == aliasGroupID {
+		return fmt.Errorf("alias %q refers to another alias", name)
+	}
+
+	aliasCmd := &cobra.Command{
+		Use:                name,
+		Short:              fmt.Sprintf("Alias for %q", expansion),
+		Long:               fmt.Sprintf("Alias for %q.\n\n%s", expansion, target.Long),
+		GroupID:            aliasGroupID,
+		DisableFlagParsing: true,
+		SilenceUsage:       true,
+		RunE: func(cobraCommand *cobra.Command, extraArgs []string) error {
+			return m.runAlias(cobraCommand, append(append([]string{}, args...), extraArgs...))
+		},
+		ValidArgsFunction: func(_ *cobra.Command, extraArgs []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return m.completeAlias(append(append([]string{}, args...), extraArgs...), toComplete)
+		},
+	}
+	m.tree.AddChild(aliasCmd)
+	m.registeredCommands[name] = struct{}{}
+	return nil
+}
+
+func (m *ManagerV2) nameTaken(name string) bool {
+	if _, exists := m.registeredCommands[name]; exists {
+		return true
+	}
+	for _, reserved := range reservedNames {
+		if name == reserved {
+			return true
+		}
+	}
+	for _, c := range m.rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	for _, aliases := range standards.CommonAliases {
+		for _, alias := range aliases {
+			if alias == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// runAlias executes the root command again with the expanded arguments, so
+// global flags, hooks and the flags of the aliased command are handled as if
+// the expansion was typed. The nested execution doesn't print errors, they
+// are printed once by the outer one.
+func (m *ManagerV2) runAlias(aliasCmd *cobra.Command, args []string) error {
+	silenceErrors := m.rootCmd.SilenceErrors
+	m.rootCmd.SilenceErrors = true
+	defer func() { m.rootCmd.SilenceErrors = silenceErrors }()
+	m.rootCmd.SetArgs(args)
+	return m.rootCmd.ExecuteContext(aliasCmd.Context())
+}
+
+// completeAlias completes the arguments of an alias as the aliased command
+// would: flag names, flag values and positional arguments.
+func (m *ManagerV2) completeAlias(args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	target, targetArgs, err := m.rootCmd.Find(args)
+	if err != nil || target == m.rootCmd {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	if strings.HasPrefix(toComplete, "-") {
+		var names []string
+		target.Flags().VisitAll(func(f *pflag.Flag) {
+			if !f.Hidden {
+				names = append(names, "--"+f.Name+"\t"+f.Usage)
+			}
+		})
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+	if len(targetArgs) > 0 {
+		last := targetArgs[len(targetArgs)-1]
+		if flag := lookupFlagArg(target.Flags(), last); flag != nil && flag.Value.Type() != "bool" {
+			if complete, ok := target.GetFlagCompletionFunc(flag.Name); ok {
+				return complete(target, targetArgs, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+	}
+	if target.ValidArgsFunction == nil {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	if !target.DisableFlagParsing && target.ParseFlags(targetArgs) == nil {
+		targetArgs = target.Flags().Args()
+	}
+	return target.ValidArgsFunction(target, targetArgs, toComplete)
+}
+
+// lookupFlagArg returns the flag named in arg when arg is a flag waiting for
+// its value, as in "--app" or "-a".
+func lookupFlagArg(fs *pflag.FlagSet, arg string) *pflag.Flag {
+	if strings.Contains(arg, "=") {
+		return nil
+	}
+	if name, ok := strings.CutPrefix(arg, "--"); ok {
+		return fs.Lookup(name)
+	}
+	if name, ok := strings.CutPrefix(arg, "-"); ok && len(name) == 1 {
+		return fs.ShorthandLookup(name)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAliasTestManager(run func(ctx *Context, app string) error) *ManagerV2 {
+	manager := NewManagerV2()
+	manager.SetFlagCompletions(map[string]CompletionFunc{
+		"app": func(toComplete string) ([]string, error) { return []string{"completed-app"}, nil },
+	})
+	var app string
+	flags := pflag.NewFlagSet("app-deploy", pflag.ContinueOnError)
+	flags.StringVarP(&app, "app", "a", "", "The name of the app.")
+	flags.Bool("new-version", false, "Creates a new version.")
+	manager.Register(&mockFlaggedCommand{
+		info:  &Info{Name: "app-deploy", Desc: "Deploys an app."},
+		flags: flags,
+		runFn: func(ctx *Context) error { return run(ctx, app) },
+	})
+	manager.Register(&mockAutoCompleteCommand{
+		info: &Info{Name: "app-info", Desc: "Shows an app."},
+		completeFn: func(args []string, toComplete string) ([]string, error) {
+			return []string{"myapp", "otherapp"}, nil
+		},
+	})
+	manager.RegisterShorthand(&mockCommand{info: &Info{Name: "app-log", Desc: "Shows logs."}}, "log")
+	return manager
+}
+
+func TestManagerV2_RegisterAlias(t *testing.T) {
+	var gotArgs []string
+	var gotApp string
+	manager := newAliasTestManager(func(ctx *Context, app string) error {
+		gotArgs, gotApp = ctx.Args, app
+		return nil
+	})
+	err := manager.RegisterAlias("dp", "app deploy -a myapp 'my dir'")
+	require.NoError(t, err)
+
+	aliasNode := manager.tree.Children["dp"]
+	require.NotNil(t, aliasNode)
+	assert.Equal(t, `Alias for "app deploy -a myapp 'my dir'"`, aliasNode.Command.Short)
+	assert.Equal(t, "aliases", aliasNode.Command.GroupID)
+
+	manager.rootCmd.SetArgs([]string{"dp", "--new-version", "other"})
+	err = manager.rootCmd.Execute()
+	require.NoError(t, err)
+	assert.Equal(t, "myapp", gotApp)
+	assert.Equal(t, []string{"my dir", "other"}, gotArgs)
+}
+
+func TestManagerV2_RegisterAliasReturnsCommandError(t *testing.T) {
+	manager := newAliasTestManager(func(ctx *Context, app string) error {
+		return errors.New("deploy failed")
+	})
+	require.NoError(t, manager.RegisterAlias("dp", "app-deploy -a myapp ."))
+	var stderr bytes.Buffer
+	manager.rootCmd.SetErr(&stderr)
+	manager.rootCmd.SetArgs([]string{"dp"})
+	err := manager.rootCmd.Execute()
+	assert.EqualError(t, err, "deploy failed")
+	assert.Equal(t, "Error: deploy failed\n", stderr.String())
+}
+
+func TestManagerV2_RegisterAliasRefused(t *testing.T) {
+	manager := newAliasTestManager(func(ctx *Context, app string) error { return nil })
+	manager.RegisterTopic("pool", "Pools.")
+	require.NoError(t, manager.RegisterAlias("dp", "app deploy"))
+
+	tests := []struct {
+		name, expansion, err string
+	}{
+		{name: "app-deploy", expansion: "app info", err: `alias "app-deploy" shadows an existing command`},
+		{name: "app", expansion: "app info", err: `alias "app" shadows an existing command`},
+		{name: "pool", expansion: "app info", err: `alias "pool" shadows an existing command`},
+		{name: "log", expansion: "app info", err: `alias "log" shadows an existing command`},
+		{name: "help", expansion: "app info", err: `alias "help" shadows an existing command`},
+		{name: "dp", expansion: "app info", err: `alias "dp" shadows an existing command`},
+		{name: "-x", expansion: "app info", err: `invalid alias name "-x"`},
+		{name: "my alias", expansion: "app info", err: `invalid alias name "my alias"`},
+		{name: "empty", expansion: " ", err: `alias "empty" is empty`},
+		{name: "quotes", expansion: "app info 'myapp", err: `invalid alias "quotes": .*`},
+		{name: "unknown", expansion: "app-unknown", err: `alias "unknown" refers to unknown command "app-unknown"`},
+		{name: "nested", expansion: "dp -a myapp", err: `alias "nested" refers to another alias`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := manager.RegisterAlias(tt.name, tt.expansion)
+			require.Error(t, err)
+			assert.Regexp(t, "^"+tt.err+"$", err.Error())
+		})
+	}
+}
+
+func TestManagerV2_RegisterAliasCompletion(t *testing.T) {
+	manager := newAliasTestManager(func(ctx *Context, app string) error { return nil })
+	require.NoError(t, manager.RegisterAlias("dp", "app deploy"))
+	require.NoError(t, manager.RegisterAlias("ai", "app info"))
+	complete := func(name string, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		aliasCmd := manager.tree.Children[name].Command
+		return aliasCmd.ValidArgsFunction(aliasCmd, args, toComplete)
+	}
+
+	result, _ := complete("dp", nil, "--")
+	assert.Equal(t, []string{"--app\tThe name of the app.", "--new-version\tCreates a new version."}, result)
+
+	result, directive := complete("dp", []string{"-a"}, "")
+	assert.Equal(t, []string{"completed-app"}, result)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	result, _ = complete("ai", nil, "")
+	assert.Equal(t, []string{"myapp", "otherapp"}, result)
+}
@@ -0,0 +1,230 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmdtest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"sync"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// RedactedValue replaces the value of sensitive headers in recorded
+// cassettes.
+const RedactedValue = "REDACTED"
+
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// Cassette is a list of HTTP interactions captured by a RecordingTransport
+// and played back by a ReplayTransport.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request and the response received for it.
+type Interaction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+type CassetteRequest struct {
+	Method  string      `json:"method"`
+	Path    string      `json:"path"`
+	Query   string      `json:"query,omitempty"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+type CassetteResponse struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// LoadCassette reads a cassette file saved by Cassette.Save.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cassette Cassette
+	if err = yaml.Unmarshal(data, &cassette); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse cassette %q", path)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette to path as YAML.
+func (c *Cassette) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// RecordingTransport sends requests using Transport and records every
+// interaction, with sensitive headers redacted. Call Save to write the
+// recorded cassette to Path.
+type RecordingTransport struct {
+	Transport http.RoundTripper
+	Path      string
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cassette.Interactions = append(t.cassette.Interactions, Interaction{
+		Request: CassetteRequest{
+			Method:  req.Method,
+			Path:    req.URL.Path,
+			Query:   req.URL.RawQuery,
+			Headers: redactHeaders(req.Header),
+			Body:    reqBody,
+		},
+		Response: CassetteResponse{
+			Status:  resp.StatusCode,
+			Headers: redactHeaders(resp.Header),
+			Body:    respBody,
+		},
+	})
+	return resp, nil
+}
+
+// Cassette returns a copy of the interactions recorded so far.
+func (t *RecordingTransport) Cassette() *Cassette {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return &Cassette{Interactions: append([]Interaction(nil), t.cassette.Interactions...)}
+}
+
+// Save writes the recorded interactions to Path.
+func (t *RecordingTransport) Save() error {
+	return t.Cassette().Save(t.Path)
+}
+
+// ReplayTransport answers requests with the responses of a cassette. A
+// request matches an interaction with the same method, path and query, and
+// each interaction is used only once, in the order they were recorded.
+type ReplayTransport struct {
+	Cassette *Cassette
+
+	mu   sync.Mutex
+	used map[int]bool
+}
+
+// NewReplayTransport loads the cassette at path and returns a transport
+// replaying it.
+func NewReplayTransport(path string) (*ReplayTransport, error) {
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplayTransport{Cassette: cassette}, nil
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.used == nil {
+		t.used = make(map[int]bool)
+	}
+	for i, interaction := range t.Cassette.Interactions {
+		if t.used[i] || !interaction.Request.matches(req) {
+			continue
+		}
+		t.used[i] = true
+		bt := BodyTransport{
+			Body:    io.NopCloser(bytes.NewBufferString(interaction.Response.Body)),
+			Status:  interaction.Response.Status,
+			Headers: interaction.Response.Headers.Clone(),
+		}
+		resp, err := bt.RoundTrip(req)
+		if resp != nil {
+			resp.Request = req
+		}
+		return resp, err
+	}
+	return &http.Response{Body: nil, StatusCode: 500}, errors.Errorf("no recorded interaction for %s %s", req.Method, req.URL.RequestURI())
+}
+
+// Pending returns the interactions that were not replayed yet.
+func (t *ReplayTransport) Pending() []Interaction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var pending []Interaction
+	for i, interaction := range t.Cassette.Interactions {
+		if !t.used[i] {
+			pending = append(pending, interaction)
+		}
+	}
+	return pending
+}
+
+func (r CassetteRequest) matches(req *http.Request) bool {
+	if r.Method != req.Method || r.Path != req.URL.Path {
+		return false
+	}
+	recorded, err := url.ParseQuery(r.Query)
+	if err != nil {
+		return r.Query == req.URL.RawQuery
+	}
+	query := req.URL.Query()
+	if len(recorded) == 0 && len(query) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(recorded, query)
+}
+
+func readBody(body *io.ReadCloser) (string, error) {
+	if *body == nil || *body == http.NoBody {
+		return "", nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return "", err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return string(data), nil
+}
+
+func redactHeaders(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	header = header.Clone()
+	for _, name := range redactedHeaders {
+		if _, ok := header[name]; ok {
+			header[name] = []string{RedactedValue}
+		}
+	}
+	return header
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmdtest
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	check "gopkg.in/check.v1"
+)
+
+func (S) TestRecordingTransport(c *check.C) {
+	path := filepath.Join(c.MkDir(), "cassette.yaml")
+	t := &RecordingTransport{
+		Transport: &ConditionalTransport{
+			Transport: Transport{
+				Message: `{"name":"myapp"}`,
+				Status:  http.StatusCreated,
+				Headers: map[string][]string{"Content-Type": {"application/json"}, "Set-Cookie": {"session=abc"}},
+			},
+			CondFunc: func(req *http.Request) bool {
+				body, _ := io.ReadAll(req.Body)
+				return string(body) == "name=myapp"
+			},
+		},
+		Path: path,
+	}
+	req, _ := http.NewRequest("POST", "http://tsuru.io/1.0/apps?team=admin", strings.NewReader("name=myapp"))
+	req.Header.Set("Authorization", "bearer secret-token")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r, err := t.RoundTrip(req)
+	c.Assert(err, check.IsNil)
+	c.Assert(r.StatusCode, check.Equals, http.StatusCreated)
+	b, _ := io.ReadAll(r.Body)
+	c.Assert(string(b), check.Equals, `{"name":"myapp"}`)
+	c.Assert(t.Save(), check.IsNil)
+	cassette, err := LoadCassette(path)
+	c.Assert(err, check.IsNil)
+	c.Assert(cassette.Interactions, check.DeepEquals, []Interaction{{
+		Request: CassetteRequest{
+			Method: "POST",
+			Path:   "/1.0/apps",
+			Query:  "team=admin",
+			Headers: http.Header{
+				"Authorization": {RedactedValue},
+				"Content-Type":  {"application/x-www-form-urlencoded"},
+			},
+			Body: "name=myapp",
+		},
+		Response: CassetteResponse{
+			Status: http.StatusCreated,
+			Headers: http.Header{
+				"Content-Type": {"application/json"},
+				"Set-Cookie":   {RedactedValue},
+			},
+			Body: `{"name":"myapp"}`,
+		},
+	}})
+	c.Assert(req.Header.Get("Authorization"), check.Equals, "bearer secret-token")
+}
+
+func (S) TestReplayTransport(c *check.C) {
+	t := &ReplayTransport{Cassette: &Cassette{Interactions: []Interaction{
+		{
+			Request:  CassetteRequest{Method: "GET", Path: "/1.0/apps", Query: "pool=p1&name=x"},
+			Response: CassetteResponse{Status: http.StatusOK, Body: "first"},
+		},
+		{
+			Request:  CassetteRequest{Method: "GET", Path: "/1.0/apps", Query: "pool=p1&name=x"},
+			Response: CassetteResponse{Status: http.StatusOK, Body: "second"},
+		},
+		{
+			Request:  CassetteRequest{Method: "DELETE", Path: "/1.0/apps/x"},
+			Response: CassetteResponse{Status: http.StatusNotFound, Body: "not found"},
+		},
+	}}}
+	req, _ := http.NewRequest("GET", "http://other.host/1.0/apps?name=x&pool=p1", nil)
+	r, err := t.RoundTrip(req)
+	c.Assert(err, check.IsNil)
+	b, _ := io.ReadAll(r.Body)
+	c.Assert(string(b), check.Equals, "first")
+	r, err = t.RoundTrip(req)
+	c.Assert(err, check.IsNil)
+	b, _ = io.ReadAll(r.Body)
+	c.Assert(string(b), check.Equals, "second")
+	c.Assert(t.Pending(), check.HasLen, 1)
+	_, err = t.RoundTrip(req)
+	c.Assert(err, check.ErrorMatches, `no recorded interaction for GET /1.0/apps\?name=x&pool=p1`)
+	req, _ = http.NewRequest("DELETE", "http://other.host/1.0/apps/x", nil)
+	r, err = t.RoundTrip(req)
+	c.Assert(err, check.IsNil)
+	c.Assert(r.StatusCode, check.Equals, http.StatusNotFound)
+	c.Assert(r.Status, check.Equals, "404 Not Found")
+	c.Assert(t.Pending(), check.HasLen, 0)
+}
+
+func (S) TestReplayTransportQueryMismatch(c *check.C) {
+	t := &ReplayTransport{Cassette: &Cassette{Interactions: []Interaction{{
+		Request:  CassetteRequest{Method: "GET", Path: "/1.0/apps", Query: "pool=p1"},
+		Response: CassetteResponse{Status: http.StatusOK},
+	}}}}
+	req, _ := http.NewRequest("GET", "http://tsuru.io/1.0/apps?pool=p2", nil)
+	_, err := t.RoundTrip(req)
+	c.Assert(err, check.NotNil)
+	req, _ = http.NewRequest("POST", "http://tsuru.io/1.0/apps?pool=p1", nil)
+	_, err = t.RoundTrip(req)
+	c.Assert(err, check.NotNil)
+}
+
+func (S) TestNewReplayTransportFromRecording(c *check.C) {
+	path := filepath.Join(c.MkDir(), "cassette.yaml")
+	recorder := &RecordingTransport{
+		Transport: Transport{Message: "[]", Status: http.StatusOK},
+		Path:      path,
+	}
+	req, _ := http.NewRequest("GET", "http://tsuru.io/1.0/pools", nil)
+	_, err := recorder.RoundTrip(req)
+	c.Assert(err, check.IsNil)
+	c.Assert(recorder.Save(), check.IsNil)
+	t, err := NewReplayTransport(path)
+	c.Assert(err, check.IsNil)
+	r, err := t.RoundTrip(req)
+	c.Assert(err, check.IsNil)
+	b, _ := io.ReadAll(r.Body)
+	c.Assert(string(b), check.Equals, "[]")
+}
@@ -0,0 +1,613 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmdtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cezarsa/form"
+	"github.com/tsuru/go-tsuruclient/pkg/tsuru"
+	tsuruIo "github.com/tsuru/tsuru/io"
+	"github.com/tsuru/tsuru/service"
+	apiTypes "github.com/tsuru/tsuru/types/api"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	bindTypes "github.com/tsuru/tsuru/types/bind"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FakeServerUser is the email of the user owning the events created by the
+// FakeServer.
+const FakeServerUser = "fake@tsuru.io"
+
+// FakePool is a pool as returned by the pool list endpoint.
+type FakePool struct {
+	Name        string
+	Public      bool
+	Default     bool
+	Provisioner string
+	Allowed     map[string][]string
+}
+
+// FakeDeploy is a deploy received by the FakeServer.
+type FakeDeploy struct {
+	App     string
+	Origin  string
+	Image   string
+	Message string
+	Archive []byte
+}
+
+// FakeServer is an in-process tsuru API keeping apps, environment variables,
+// teams, pools, plans, service instances and events in memory. It implements
+// only the subset of the API needed to exercise the most common commands end
+// to end, with the same paths and encodings of tsuru: long running operations
+// stream JSON messages, while deploys stream their plain text log ending with
+// "OK", which the client wraps into JSON messages itself.
+//
+// A new server comes with the "admin" team, the "default" pool and the
+// "c1m1" plan, which are used when an app is created without them.
+type FakeServer struct {
+	*httptest.Server
+
+	// Token, when set, is required as bearer token in every request.
+	Token string
+
+	mu       sync.Mutex
+	apps     map[string]*appTypes.AppInfo
+	envs     map[string][]bindTypes.EnvVar
+	teams    []tsuru.Team
+	pools    []FakePool
+	plans    []appTypes.Plan
+	services []service.ServiceInstance
+	events   []eventTypes.EventData
+	deploys  []FakeDeploy
+	requests []string
+}
+
+// NewFakeServer starts a FakeServer. Callers must Close it when done.
+func NewFakeServer() *FakeServer {
+	s := &FakeServer{
+		apps:  make(map[string]*appTypes.AppInfo),
+		envs:  make(map[string][]bindTypes.EnvVar),
+		teams: []tsuru.Team{{Name: "admin"}},
+		pools: []FakePool{{Name: "default", Default: true, Provisioner: "kubernetes"}},
+		plans: []appTypes.Plan{{Name: "c1m1", CPUMilli: 1000, Memory: 1024 * 1024 * 1024, Default: true}},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /1.0/apps", s.listApps)
+	mux.HandleFunc("POST /1.0/apps", s.createApp)
+	mux.HandleFunc("GET /1.0/apps/{app}", s.withApp(s.getApp))
+	mux.HandleFunc("DELETE /1.0/apps/{app}", s.withApp(s.removeApp))
+	mux.HandleFunc("GET /1.0/apps/{app}/env", s.withApp(s.getEnvs))
+	mux.HandleFunc("POST /1.0/apps/{app}/env", s.withApp(s.setEnvs))
+	mux.HandleFunc("DELETE /1.0/apps/{app}/env", s.withApp(s.unsetEnvs))
+	mux.HandleFunc("POST /1.0/apps/{app}/deploy", s.withApp(s.deploy))
+	mux.HandleFunc("GET /1.0/teams", s.listTeams)
+	mux.HandleFunc("POST /1.0/teams", s.createTeam)
+	mux.HandleFunc("DELETE /1.0/teams/{team}", s.removeTeam)
+	mux.HandleFunc("GET /1.0/pools", s.listPools)
+	mux.HandleFunc("GET /1.0/plans", s.listPlans)
+	mux.HandleFunc("GET /1.0/services/instances", s.listServiceInstances)
+	mux.HandleFunc("GET /1.1/events", s.listEvents)
+	mux.HandleFunc("GET /1.1/events/{id}", s.getEvent)
+	s.Server = httptest.NewServer(s.authenticate(mux))
+	return s
+}
+
+// AddApp stores app, replacing any app with the same name.
+func (s *FakeServer) AddApp(app appTypes.AppInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apps[app.Name] = &app
+}
+
+// App returns the stored app with the given name.
+func (s *FakeServer) App(name string) (appTypes.AppInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	app, ok := s.apps[name]
+	if !ok {
+		return appTypes.AppInfo{}, false
+	}
+	return *app, true
+}
+
+// Envs returns the environment variables of an app, sorted by name.
+func (s *FakeServer) Envs(app string) []bindTypes.EnvVar {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]bindTypes.EnvVar(nil), s.envs[app]...)
+}
+
+func (s *FakeServer) AddTeam(team tsuru.Team) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.teams = append(s.teams, team)
+}
+
+func (s *FakeServer) AddPool(pool FakePool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pools = append(s.pools, pool)
+}
+
+func (s *FakeServer) AddPlan(plan appTypes.Plan) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.plans = append(s.plans, plan)
+}
+
+func (s *FakeServer) AddServiceInstance(instance service.ServiceInstance) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.services = append(s.services, instance)
+}
+
+func (s *FakeServer) AddEvent(evt eventTypes.EventData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, evt)
+}
+
+// Events returns the stored events, the most recent first.
+func (s *FakeServer) Events() []eventTypes.EventData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sortedEvents()
+}
+
+// Deploys returns the deploys received by the server, in order.
+func (s *FakeServer) Deploys() []FakeDeploy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]FakeDeploy(nil), s.deploys...)
+}
+
+// Requests returns the method and path of every request received, in order.
+func (s *FakeServer) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *FakeServer) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		token := s.Token
+		s.mu.Unlock()
+		if token != "" {
+			scheme, value, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+			if !strings.EqualFold(scheme, "bearer") || value != token {
+				http.Error(w, "You must provide a valid Authorization header", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *FakeServer) withApp(handler func(http.ResponseWriter, *http.Request, *appTypes.AppInfo)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		app, ok := s.apps[r.PathValue("app")]
+		s.mu.Unlock()
+		if !ok {
+			http.Error(w, "App not found", http.StatusNotFound)
+			return
+		}
+		handler(w, r, app)
+	}
+}
+
+func (s *FakeServer) listApps(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	pool := r.URL.Query().Get("pool")
+	teamOwner := r.URL.Query().Get("teamOwner")
+	s.mu.Lock()
+	var apps []appTypes.AppResume
+	for _, a := range s.apps {
+		if (name != "" && !strings.Contains(a.Name, name)) || (pool != "" && a.Pool != pool) || (teamOwner != "" && a.TeamOwner != teamOwner) {
+			continue
+		}
+		resume := appTypes.AppResume{
+			Name:        a.Name,
+			Pool:        a.Pool,
+			TeamOwner:   a.TeamOwner,
+			Units:       a.Units,
+			CName:       a.CName,
+			IP:          a.IP,
+			Routers:     a.Routers,
+			Tags:        a.Tags,
+			Platform:    a.Platform,
+			Description: a.Description,
+			Metadata:    a.Metadata,
+		}
+		if a.Plan != nil {
+			resume.Plan = *a.Plan
+		}
+		apps = append(apps, resume)
+	}
+	s.mu.Unlock()
+	sort.Slice(apps, func(i, j int) bool { return apps[i].Name < apps[j].Name })
+	writeJSONList(w, apps, len(apps))
+}
+
+func (s *FakeServer) createApp(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	name := r.Form.Get("name")
+	if name == "" {
+		http.Error(w, "App name is required.", http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.apps[name]; ok {
+		http.Error(w, "there is already an app with this name", http.StatusConflict)
+		return
+	}
+	app := &appTypes.AppInfo{
+		Name:        name,
+		Platform:    r.Form.Get("platform"),
+		Pool:        r.Form.Get("pool"),
+		TeamOwner:   r.Form.Get("teamOwner"),
+		Description: r.Form.Get("description"),
+		Tags:        r.Form["tag"],
+		IP:          name + ".fake.tsuru.io",
+		Owner:       FakeServerUser,
+	}
+	if app.Pool == "" {
+		for _, p := range s.pools {
+			if p.Default {
+				app.Pool = p.Name
+			}
+		}
+	}
+	if app.TeamOwner == "" && len(s.teams) > 0 {
+		app.TeamOwner = s.teams[0].Name
+	}
+	app.Teams = []string{app.TeamOwner}
+	planName := r.Form.Get("plan")
+	for i := range s.plans {
+		if s.plans[i].Name == planName || (planName == "" && s.plans[i].Default) {
+			plan := s.plans[i]
+			app.Plan = &plan
+		}
+	}
+	if app.Plan == nil {
+		http.Error(w, "plan not found", http.StatusBadRequest)
+		return
+	}
+	s.apps[name] = app
+	s.addEvent(eventTypes.TargetTypeApp, name, "app.create", "")
+	writeJSON(w, map[string]string{"status": "success", "ip": app.IP})
+}
+
+func (s *FakeServer) getApp(w http.ResponseWriter, r *http.Request, app *appTypes.AppInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, app)
+}
+
+func (s *FakeServer) removeApp(w http.ResponseWriter, r *http.Request, app *appTypes.AppInfo) {
+	s.mu.Lock()
+	delete(s.apps, app.Name)
+	delete(s.envs, app.Name)
+	s.addEvent(eventTypes.TargetTypeApp, app.Name, "app.delete", "")
+	s.mu.Unlock()
+	writeMessages(w, fmt.Sprintf("-- removing app %q --\n", app.Name))
+}
+
+func (s *FakeServer) getEnvs(w http.ResponseWriter, r *http.Request, app *appTypes.AppInfo) {
+	names := r.URL.Query()["env"]
+	s.mu.Lock()
+	envs := []bindTypes.EnvVar{}
+	for _, env := range s.envs[app.Name] {
+		if len(names) == 0 || containsString(names, env.Name) {
+			if !env.Public {
+				env.Value = "*** (private variable)"
+			}
+			envs = append(envs, env)
+		}
+	}
+	s.mu.Unlock()
+	writeJSON(w, envs)
+}
+
+func (s *FakeServer) setEnvs(w http.ResponseWriter, r *http.Request, app *appTypes.AppInfo) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var e apiTypes.Envs
+	dec := form.NewDecoder(nil)
+	dec.IgnoreUnknownKeys(true)
+	dec.IgnoreCase(true)
+	if err := dec.UseJSONTags(false).DecodeValues(&e, r.Form); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(e.Envs) == 0 {
+		http.Error(w, "You must provide the list of environment variables", http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	for _, env := range e.Envs {
+		s.setEnv(app.Name, bindTypes.EnvVar{Name: env.Name, Value: env.Value, Public: !e.Private})
+	}
+	s.addEvent(eventTypes.TargetTypeApp, app.Name, "app.update.env.set", "")
+	s.mu.Unlock()
+	writeMessages(w, fmt.Sprintf("---- Setting %d new environment variables ----\n", len(e.Envs)))
+}
+
+func (s *FakeServer) unsetEnvs(w http.ResponseWriter, r *http.Request, app *appTypes.AppInfo) {
+	names := r.URL.Query()["env"]
+	if len(names) == 0 {
+		http.Error(w, "You must provide the list of environment variables.", http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	var envs []bindTypes.EnvVar
+	for _, env := range s.envs[app.Name] {
+		if !containsString(names, env.Name) {
+			envs = append(envs, env)
+		}
+	}
+	s.envs[app.Name] = envs
+	s.addEvent(eventTypes.TargetTypeApp, app.Name, "app.update.env.unset", "")
+	s.mu.Unlock()
+	writeMessages(w, fmt.Sprintf("---- Unsetting %d environment variables ----\n", len(names)))
+}
+
+func (s *FakeServer) deploy(w http.ResponseWriter, r *http.Request, app *appTypes.AppInfo) {
+	d := FakeDeploy{App: app.Name}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if file, _, err := r.FormFile("file"); err == nil {
+			d.Archive, _ = io.ReadAll(file)
+			file.Close()
+		}
+	} else if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	d.Origin = r.FormValue("origin")
+	d.Image = r.FormValue("image")
+	d.Message = r.FormValue("message")
+	if d.Archive == nil && d.Image == "" {
+		http.Error(w, "you must specify either the archive-url, a image url or upload a file.", http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	s.deploys = append(s.deploys, d)
+	app.Deploys++
+	evt := s.addEvent(eventTypes.TargetTypeApp, app.Name, "app.deploy", "")
+	s.mu.Unlock()
+	w.Header().Set("X-Tsuru-Eventid", evt.UniqueID.Hex())
+	w.Header().Set("Content-Type", "text")
+	w.WriteHeader(http.StatusOK)
+	for _, line := range []string{
+		fmt.Sprintf("---- Deploying app %q ----\n", app.Name),
+		" ---> Building image\n",
+		" ---> Starting units\n",
+		"\nOK\n",
+	} {
+		io.WriteString(w, line)
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *FakeServer) listTeams(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	teams := append([]tsuru.Team(nil), s.teams...)
+	s.mu.Unlock()
+	writeJSONList(w, teams, len(teams))
+}
+
+func (s *FakeServer) createTeam(w http.ResponseWriter, r *http.Request) {
+	var args tsuru.TeamCreateArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if args.Name == "" {
+		http.Error(w, "Team name is required", http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range s.teams {
+		if t.Name == args.Name {
+			http.Error(w, "team already exists", http.StatusConflict)
+			return
+		}
+	}
+	s.teams = append(s.teams, tsuru.Team{Name: args.Name, Tags: args.Tags})
+	s.addEvent(eventTypes.TargetType("team"), args.Name, "team.create", "")
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *FakeServer) removeTeam(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("team")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, t := range s.teams {
+		if t.Name == name {
+			s.teams = append(s.teams[:i], s.teams[i+1:]...)
+			s.addEvent(eventTypes.TargetType("team"), name, "team.delete", "")
+			return
+		}
+	}
+	http.Error(w, fmt.Sprintf("team %q not found", name), http.StatusNotFound)
+}
+
+func (s *FakeServer) listPools(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	pools := append([]FakePool(nil), s.pools...)
+	s.mu.Unlock()
+	writeJSONList(w, pools, len(pools))
+}
+
+func (s *FakeServer) listPlans(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	plans := append([]appTypes.Plan(nil), s.plans...)
+	s.mu.Unlock()
+	writeJSONList(w, plans, len(plans))
+}
+
+func (s *FakeServer) listServiceInstances(w http.ResponseWriter, r *http.Request) {
+	appName := r.URL.Query().Get("app")
+	s.mu.Lock()
+	models := map[string]*service.ServiceModel{}
+	var names []string
+	for _, si := range s.services {
+		if appName != "" && !containsString(si.Apps, appName) {
+			continue
+		}
+		model, ok := models[si.ServiceName]
+		if !ok {
+			model = &service.ServiceModel{Service: si.ServiceName}
+			models[si.ServiceName] = model
+			names = append(names, si.ServiceName)
+		}
+		model.Instances = append(model.Instances, si.Name)
+		model.Plans = append(model.Plans, si.PlanName)
+		model.ServiceInstances = append(model.ServiceInstances, si)
+	}
+	s.mu.Unlock()
+	sort.Strings(names)
+	result := make([]service.ServiceModel, len(names))
+	for i, name := range names {
+		result[i] = *models[name]
+	}
+	writeJSONList(w, result, len(result))
+}
+
+func (s *FakeServer) listEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	kindNames := query["kindname"]
+	s.mu.Lock()
+	var events []eventTypes.EventData
+	for _, evt := range s.sortedEvents() {
+		if t := query.Get("target.type"); t != "" && string(evt.Target.Type) != t {
+			continue
+		}
+		if v := query.Get("target.value"); v != "" && evt.Target.Value != v {
+			continue
+		}
+		if len(kindNames) > 0 && !containsString(kindNames, evt.Kind.Name) {
+			continue
+		}
+		events = append(events, evt)
+	}
+	s.mu.Unlock()
+	writeJSONList(w, events, len(events))
+}
+
+func (s *FakeServer) getEvent(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, evt := range s.events {
+		if evt.UniqueID.Hex() == id {
+			writeJSON(w, eventTypes.EventInfo{EventData: evt})
+			return
+		}
+	}
+	http.Error(w, "event not found", http.StatusNotFound)
+}
+
+// setEnv must be called with s.mu held.
+func (s *FakeServer) setEnv(app string, env bindTypes.EnvVar) {
+	envs := s.envs[app]
+	for i := range envs {
+		if envs[i].Name == env.Name {
+			envs[i] = env
+			return
+		}
+	}
+	envs = append(envs, env)
+	sort.Slice(envs, func(i, j int) bool { return envs[i].Name < envs[j].Name })
+	s.envs[app] = envs
+}
+
+// addEvent must be called with s.mu held.
+func (s *FakeServer) addEvent(targetType eventTypes.TargetType, targetValue, kind, errMsg string) eventTypes.EventData {
+	now := time.Now().UTC()
+	evt := eventTypes.EventData{
+		UniqueID:  primitive.NewObjectID(),
+		StartTime: now,
+		EndTime:   now,
+		Target:    eventTypes.Target{Type: targetType, Value: targetValue},
+		Kind:      eventTypes.Kind{Type: eventTypes.KindTypePermission, Name: kind},
+		Owner:     eventTypes.Owner{Type: eventTypes.OwnerTypeUser, Name: FakeServerUser},
+		Error:     errMsg,
+	}
+	s.events = append(s.events, evt)
+	return evt
+}
+
+// sortedEvents must be called with s.mu held.
+func (s *FakeServer) sortedEvents() []eventTypes.EventData {
+	events := make([]eventTypes.EventData, len(s.events))
+	for i, evt := range s.events {
+		events[len(events)-1-i] = evt
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].StartTime.After(events[j].StartTime) })
+	return events
+}
+
+func writeJSON(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
+
+func writeJSONList(w http.ResponseWriter, data interface{}, size int) {
+	if size == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, data)
+}
+
+// writeMessages streams messages in the JSON message format used by tsuru
+// for long running operations, flushing each one to the client.
+func writeMessages(w http.ResponseWriter, messages ...string) {
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+	for _, msg := range messages {
+		encoder.Encode(tsuruIo.SimpleJsonMessage{Message: msg, Timestamp: time.Now().UTC()})
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
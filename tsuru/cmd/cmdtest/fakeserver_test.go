@@ -0,0 +1,116 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmdtest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/tsuru/go-tsuruclient/pkg/tsuru"
+	"github.com/tsuru/tsuru/service"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	bindTypes "github.com/tsuru/tsuru/types/bind"
+	check "gopkg.in/check.v1"
+)
+
+func (S) TestFakeServerApps(c *check.C) {
+	srv := NewFakeServer()
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/1.0/apps")
+	c.Assert(err, check.IsNil)
+	c.Assert(resp.StatusCode, check.Equals, http.StatusNoContent)
+	resp, err = http.PostForm(srv.URL+"/1.0/apps", url.Values{"name": {"myapp"}, "platform": {"go"}})
+	c.Assert(err, check.IsNil)
+	c.Assert(resp.StatusCode, check.Equals, http.StatusOK)
+	resp, err = http.PostForm(srv.URL+"/1.0/apps", url.Values{"name": {"myapp"}})
+	c.Assert(err, check.IsNil)
+	c.Assert(resp.StatusCode, check.Equals, http.StatusConflict)
+	resp, err = http.Get(srv.URL + "/1.0/apps/myapp")
+	c.Assert(err, check.IsNil)
+	var app appTypes.AppInfo
+	c.Assert(json.NewDecoder(resp.Body).Decode(&app), check.IsNil)
+	c.Assert(app.Name, check.Equals, "myapp")
+	c.Assert(app.Plan.Name, check.Equals, "c1m1")
+	c.Assert(app.Pool, check.Equals, "default")
+	resp, err = http.Get(srv.URL + "/1.0/apps/other")
+	c.Assert(err, check.IsNil)
+	c.Assert(resp.StatusCode, check.Equals, http.StatusNotFound)
+	events := srv.Events()
+	c.Assert(events, check.HasLen, 1)
+	c.Assert(events[0].Kind.Name, check.Equals, "app.create")
+	c.Assert(events[0].Target.Value, check.Equals, "myapp")
+}
+
+func (S) TestFakeServerEnvs(c *check.C) {
+	srv := NewFakeServer()
+	defer srv.Close()
+	srv.AddApp(appTypes.AppInfo{Name: "myapp"})
+	body := url.Values{
+		"Envs.0.Name":  {"A"},
+		"Envs.0.Value": {"1"},
+		"Envs.1.Name":  {"B"},
+		"Envs.1.Value": {"2"},
+		"Private":      {"true"},
+	}
+	resp, err := http.PostForm(srv.URL+"/1.0/apps/myapp/env", body)
+	c.Assert(err, check.IsNil)
+	data, _ := io.ReadAll(resp.Body)
+	c.Assert(string(data), check.Matches, `{"Message":"---- Setting 2 new environment variables ----\\n","Timestamp":".*"}\n`)
+	c.Assert(srv.Envs("myapp"), check.DeepEquals, []bindTypes.EnvVar{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}})
+	resp, err = http.Get(srv.URL + "/1.0/apps/myapp/env?env=B")
+	c.Assert(err, check.IsNil)
+	var envs []bindTypes.EnvVar
+	c.Assert(json.NewDecoder(resp.Body).Decode(&envs), check.IsNil)
+	c.Assert(envs, check.DeepEquals, []bindTypes.EnvVar{{Name: "B", Value: "*** (private variable)"}})
+	req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/1.0/apps/myapp/env?env=A", nil)
+	_, err = http.DefaultClient.Do(req)
+	c.Assert(err, check.IsNil)
+	c.Assert(srv.Envs("myapp"), check.DeepEquals, []bindTypes.EnvVar{{Name: "B", Value: "2"}})
+}
+
+func (S) TestFakeServerTeams(c *check.C) {
+	srv := NewFakeServer()
+	defer srv.Close()
+	resp, err := http.Post(srv.URL+"/1.0/teams", "application/json", strings.NewReader(`{"name":"devs"}`))
+	c.Assert(err, check.IsNil)
+	c.Assert(resp.StatusCode, check.Equals, http.StatusCreated)
+	resp, err = http.Get(srv.URL + "/1.0/teams")
+	c.Assert(err, check.IsNil)
+	var teams []tsuru.Team
+	c.Assert(json.NewDecoder(resp.Body).Decode(&teams), check.IsNil)
+	c.Assert(teams, check.DeepEquals, []tsuru.Team{{Name: "admin"}, {Name: "devs"}})
+}
+
+func (S) TestFakeServerServiceInstances(c *check.C) {
+	srv := NewFakeServer()
+	defer srv.Close()
+	srv.AddServiceInstance(service.ServiceInstance{Name: "db1", ServiceName: "mysql", Apps: []string{"myapp"}})
+	srv.AddServiceInstance(service.ServiceInstance{Name: "cache", ServiceName: "redis"})
+	resp, err := http.Get(srv.URL + "/1.0/services/instances?app=myapp")
+	c.Assert(err, check.IsNil)
+	var models []service.ServiceModel
+	c.Assert(json.NewDecoder(resp.Body).Decode(&models), check.IsNil)
+	c.Assert(models, check.HasLen, 1)
+	c.Assert(models[0].Service, check.Equals, "mysql")
+	c.Assert(models[0].Instances, check.DeepEquals, []string{"db1"})
+}
+
+func (S) TestFakeServerToken(c *check.C) {
+	srv := NewFakeServer()
+	defer srv.Close()
+	srv.Token = "abc"
+	resp, err := http.Get(srv.URL + "/1.0/pools")
+	c.Assert(err, check.IsNil)
+	c.Assert(resp.StatusCode, check.Equals, http.StatusUnauthorized)
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/1.0/pools", nil)
+	req.Header.Set("Authorization", "bearer abc")
+	resp, err = http.DefaultClient.Do(req)
+	c.Assert(err, check.IsNil)
+	c.Assert(resp.StatusCode, check.Equals, http.StatusOK)
+	c.Assert(srv.Requests(), check.DeepEquals, []string{"GET /1.0/pools", "GET /1.0/pools"})
+}
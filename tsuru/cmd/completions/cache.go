@@ -0,0 +1,199 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package completions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tsuru/go-tsuruclient/pkg/config"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
+)
+
+const (
+	// cacheTTLEnv overrides how long cached names are considered fresh. A
+	// value of 0 disables the cache.
+	cacheTTLEnv = "TSURU_COMPLETION_CACHE_TTL"
+	// cacheRefreshEnv is set on the background process refreshing the cache,
+	// forcing it to fetch names from the API.
+	cacheRefreshEnv = "TSURU_COMPLETION_CACHE_REFRESH"
+
+	defaultCacheTTL = 5 * time.Minute
+	// cacheMaxAge is how long stale names may still be served while they are
+	// refreshed in background. Older entries are fetched synchronously.
+	cacheMaxAge = 24 * time.Hour
+	// cacheRefreshTimeout avoids starting a new background refresh while
+	// another one is probably still running.
+	cacheRefreshTimeout = 30 * time.Second
+)
+
+type cacheEntry struct {
+	Credential       string    `json:"credential"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+	RefreshStartedAt time.Time `json:"refreshStartedAt,omitempty"`
+	Names            []string  `json:"names"`
+}
+
+// startBackgroundRefresh runs the current completion again in a detached
+// process with the cache refresh forced, so the shell gets the cached names
+// without waiting for the API.
+var startBackgroundRefresh = func() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	c := exec.Command(executable, os.Args[1:]...)
+	c.Env = append(os.Environ(), cacheRefreshEnv+"=1")
+	if err = c.Start(); err != nil {
+		return err
+	}
+	return c.Process.Release()
+}
+
+func cacheTTL() time.Duration {
+	value := os.Getenv(cacheTTLEnv)
+	if value == "" {
+		return defaultCacheTTL
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil {
+		return defaultCacheTTL
+	}
+	return ttl
+}
+
+// cachedNames returns the names stored under key in the cache of the current
+// target, calling fetch when they are missing, expired or were stored with
+// other credentials. Stale names are returned right away and refreshed in
+// background. Results of fetch are only stored when they are not nil.
+func cachedNames(key string, fetch func() ([]string, error)) ([]string, error) {
+	ttl := cacheTTL()
+	if ttl <= 0 {
+		return fetch()
+	}
+	path, credential, err := cachePath(key)
+	if err != nil {
+		return fetch()
+	}
+	if os.Getenv(cacheRefreshEnv) == "" {
+		if entry, err := readCacheEntry(path); err == nil && entry.Credential == credential {
+			age := time.Since(entry.UpdatedAt)
+			if age < ttl {
+				return entry.Names, nil
+			}
+			if age < cacheMaxAge {
+				if time.Since(entry.RefreshStartedAt) < cacheRefreshTimeout {
+					return entry.Names, nil
+				}
+				entry.RefreshStartedAt = time.Now()
+				if writeCacheEntry(path, entry) == nil && startBackgroundRefresh() == nil {
+					return entry.Names, nil
+				}
+			}
+		}
+	}
+	names, err := fetch()
+	if err != nil || names == nil {
+		return names, err
+	}
+	writeCacheEntry(path, &cacheEntry{
+		Credential: credential,
+		UpdatedAt:  time.Now(),
+		Names:      names,
+	})
+	return names, nil
+}
+
+// cachePath returns the file holding key for the current target, along with
+// a digest of the credentials in use. Entries are kept per target under
+// ~/.tsuru/cache and are discarded when the credentials change.
+func cachePath(key string) (string, string, error) {
+	target, err := config.GetTarget()
+	if err != nil {
+		return "", "", err
+	}
+	credential, err := currentCredential()
+	if err != nil {
+		return "", "", err
+	}
+	dir := config.JoinWithUserDir(".tsuru", "cache", digest(strings.TrimRight(target, "/"))[:16])
+	return filepath.Join(dir, url.PathEscape(key)+".json"), digest(credential), nil
+}
+
+// currentCredential returns the value identifying the logged in user. For
+// OIDC logins the refresh token is used, as access tokens change often.
+func currentCredential() (string, error) {
+	if token := config.ReadTeamToken(); token != "" {
+		return token, nil
+	}
+	tokenV2, err := credentials.CurrentTokenV2()
+	if err == nil && tokenV2 != nil && tokenV2.OAuth2Token != nil {
+		return tokenV2.Scheme + ":" + tokenV2.OAuth2Token.RefreshToken, nil
+	}
+	return credentials.Token()
+}
+
+func digest(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func readCacheEntry(path string) (*cacheEntry, error) {
+	f, err := config.Filesystem().Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entry cacheEntry
+	if err = json.NewDecoder(f).Decode(&entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// writeCacheEntry replaces the entry atomically, as completions may run
+// concurrently with a background refresh.
+func writeCacheEntry(path string, entry *cacheEntry) error {
+	fsystem := config.Filesystem()
+	if err := fsystem.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	tmpPath := path + "." + strconv.Itoa(os.Getpid()) + ".tmp"
+	f, err := fsystem.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fsystem.Remove(tmpPath)
+		return err
+	}
+	return fsystem.Rename(tmpPath, path)
+}
+
+func filterPrefix(names []string, prefix string) []string {
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package completions
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsuru/tsuru-client/tsuru/cmd/cmdtest"
+)
+
+func stubBackgroundRefresh(t *testing.T) *int {
+	t.Helper()
+	var calls int
+	original := startBackgroundRefresh
+	startBackgroundRefresh = func() error {
+		calls++
+		return nil
+	}
+	t.Cleanup(func() { startBackgroundRefresh = original })
+	return &calls
+}
+
+func expireCacheEntry(t *testing.T, key string, age time.Duration) {
+	t.Helper()
+	path, _, err := cachePath(key)
+	require.NoError(t, err)
+	entry, err := readCacheEntry(path)
+	require.NoError(t, err)
+	entry.UpdatedAt = time.Now().Add(-age)
+	require.NoError(t, writeCacheEntry(path, entry))
+}
+
+func TestCompletionCacheServesFreshEntries(t *testing.T) {
+	setupTest(t)
+	stubBackgroundRefresh(t)
+	trans := &cmdtest.Transport{Message: `[{"name":"team1"},{"name":"team2"}]`, Status: http.StatusOK}
+	setupFakeTransport(trans)
+
+	completions, err := TeamNameCompletionFunc("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team1", "team2"}, completions)
+
+	trans.Message = `[{"name":"team3"}]`
+	completions, err = TeamNameCompletionFunc("team")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team1", "team2"}, completions)
+
+	path, _, err := cachePath("teams")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(os.Getenv("HOME"), ".tsuru", "cache"), filepath.Dir(filepath.Dir(path)))
+}
+
+func TestCompletionCacheRefreshesStaleEntriesInBackground(t *testing.T) {
+	setupTest(t)
+	refreshes := stubBackgroundRefresh(t)
+	trans := &cmdtest.Transport{Message: `[{"name":"pool1"}]`, Status: http.StatusOK}
+	setupFakeTransport(trans)
+
+	_, err := PoolNameCompletionFunc("")
+	require.NoError(t, err)
+	expireCacheEntry(t, "pools", time.Hour)
+	trans.Message = `[{"name":"pool1"},{"name":"pool2"}]`
+
+	completions, err := PoolNameCompletionFunc("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pool1"}, completions)
+	assert.Equal(t, 1, *refreshes)
+
+	completions, err = PoolNameCompletionFunc("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pool1"}, completions)
+	assert.Equal(t, 1, *refreshes)
+
+	t.Setenv(cacheRefreshEnv, "1")
+	completions, err = PoolNameCompletionFunc("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pool1", "pool2"}, completions)
+}
+
+func TestCompletionCacheFetchesVeryOldEntries(t *testing.T) {
+	setupTest(t)
+	refreshes := stubBackgroundRefresh(t)
+	trans := &cmdtest.Transport{Message: `[{"name":"app1"}]`, Status: http.StatusOK}
+	setupFakeTransport(trans)
+
+	_, err := AppNameCompletionFunc("")
+	require.NoError(t, err)
+	expireCacheEntry(t, "apps", 2*cacheMaxAge)
+	trans.Message = `[{"name":"app2"}]`
+
+	completions, err := AppNameCompletionFunc("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app2"}, completions)
+	assert.Equal(t, 0, *refreshes)
+}
+
+func TestCompletionCacheInvalidatedOnTokenChange(t *testing.T) {
+	setupTest(t)
+	stubBackgroundRefresh(t)
+	trans := &cmdtest.Transport{Message: `[{"name":"app1"}]`, Status: http.StatusOK}
+	setupFakeTransport(trans)
+
+	_, err := AppNameCompletionFunc("")
+	require.NoError(t, err)
+	trans.Message = `[{"name":"app2"}]`
+	t.Setenv("TSURU_TOKEN", "othertoken")
+
+	completions, err := AppNameCompletionFunc("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app2"}, completions)
+}
+
+func TestCompletionCachePerTarget(t *testing.T) {
+	setupTest(t)
+	stubBackgroundRefresh(t)
+	trans := &cmdtest.Transport{Message: `[{"name":"app1"}]`, Status: http.StatusOK}
+	setupFakeTransport(trans)
+
+	_, err := AppNameCompletionFunc("")
+	require.NoError(t, err)
+	trans.Message = `[{"name":"app2"}]`
+	t.Setenv("TSURU_TARGET", "http://otherhost:8080")
+
+	completions, err := AppNameCompletionFunc("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app2"}, completions)
+
+	t.Setenv("TSURU_TARGET", "http://localhost:8080")
+	completions, err = AppNameCompletionFunc("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app1"}, completions)
+}
+
+func TestCompletionCacheDisabled(t *testing.T) {
+	setupTest(t)
+	stubBackgroundRefresh(t)
+	t.Setenv(cacheTTLEnv, "0")
+	trans := &cmdtest.Transport{Message: `[{"name":"app1"}]`, Status: http.StatusOK}
+	setupFakeTransport(trans)
+
+	_, err := AppNameCompletionFunc("")
+	require.NoError(t, err)
+	trans.Message = `[{"name":"app2"}]`
+
+	completions, err := AppNameCompletionFunc("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app2"}, completions)
+	_, err = os.Stat(filepath.Join(os.Getenv("HOME"), ".tsuru", "cache"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCompletionCacheSkipsEmptyServiceInstanceLookups(t *testing.T) {
+	setupTest(t)
+	stubBackgroundRefresh(t)
+	trans := &cmdtest.Transport{Status: http.StatusNoContent}
+	setupFakeTransport(trans)
+
+	completions, err := ServiceInstanceCompletionFunc("mysql", "")
+	require.NoError(t, err)
+	assert.Nil(t, completions)
+
+	trans.Message = `[{"service":"mysql","service_instances":[{"name":"db1"}]}]`
+	trans.Status = http.StatusOK
+	completions, err = ServiceInstanceCompletionFunc("mysql", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db1"}, completions)
+}
@@ -21,8 +21,15 @@ import (
 )
 
 func AppNameCompletionFunc(toComplete string) ([]string, error) {
+	names, err := cachedNames("apps", fetchAppNames)
+	if err != nil {
+		return nil, err
+	}
+	return filterPrefix(names, toComplete), nil
+}
+
+func fetchAppNames() ([]string, error) {
 	query := make(url.Values)
-	query.Set("name", toComplete)
 	query.Set("simplified", "true")
 
 	u, err := config.GetURL(fmt.Sprintf("/apps?%s", query.Encode()))
@@ -50,9 +57,6 @@ func AppNameCompletionFunc(toComplete string) ([]string, error) {
 
 	result := make([]string, 0, len(apps))
 	for _, app := range apps {
-		if !strings.HasPrefix(app.Name, toComplete) {
-			continue
-		}
 		result = append(result, app.Name)
 	}
 
@@ -60,6 +64,14 @@ func AppNameCompletionFunc(toComplete string) ([]string, error) {
 }
 
 func TeamNameCompletionFunc(toComplete string) ([]string, error) {
+	names, err := cachedNames("teams", fetchTeamNames)
+	if err != nil {
+		return nil, err
+	}
+	return filterPrefix(names, toComplete), nil
+}
+
+func fetchTeamNames() ([]string, error) {
 	apiClient, err := tsuruHTTP.TsuruClientFromEnvironment()
 	if err != nil {
 		return nil, err
@@ -79,9 +91,6 @@ func TeamNameCompletionFunc(toComplete string) ([]string, error) {
 	result := make([]string, 0, len(teams))
 
 	for _, team := range teams {
-		if !strings.HasPrefix(team.Name, toComplete) {
-			continue
-		}
 		result = append(result, team.Name)
 	}
 
@@ -116,6 +125,14 @@ func JobNameCompletionFunc(toComplete string) ([]string, error) {
 }
 
 func PoolNameCompletionFunc(toComplete string) ([]string, error) {
+	names, err := cachedNames("pools", fetchPoolNames)
+	if err != nil {
+		return nil, err
+	}
+	return filterPrefix(names, toComplete), nil
+}
+
+func fetchPoolNames() ([]string, error) {
 	url, err := config.GetURL("/pools")
 	if err != nil {
 		return nil, err
@@ -141,9 +158,6 @@ func PoolNameCompletionFunc(toComplete string) ([]string, error) {
 	result := make([]string, 0, len(pools))
 
 	for _, pool := range pools {
-		if !strings.HasPrefix(pool.Name, toComplete) {
-			continue
-		}
 		result = append(result, pool.Name)
 	}
 
@@ -279,6 +293,16 @@ func ServiceNameCompletionFunc(toComplete string) ([]string, error) {
 }
 
 func ServiceInstanceCompletionFunc(serviceName string, toComplete string) ([]string, error) {
+	names, err := cachedNames("service-instances-"+serviceName, func() ([]string, error) {
+		return fetchServiceInstanceNames(serviceName)
+	})
+	if err != nil || names == nil {
+		return nil, err
+	}
+	return filterPrefix(names, toComplete), nil
+}
+
+func fetchServiceInstanceNames(serviceName string) ([]string, error) {
 	qs := make(url.Values)
 	qs.Set("service", serviceName)
 
@@ -305,7 +329,7 @@ func ServiceInstanceCompletionFunc(serviceName string, toComplete string) ([]str
 		return nil, err
 	}
 
-	result := make([]string, 0, len(services))
+	result := []string{}
 
 	for _, svc := range services {
 		if svc.Service != serviceName {
@@ -313,10 +337,6 @@ func ServiceInstanceCompletionFunc(serviceName string, toComplete string) ([]str
 		}
 
 		for _, instance := range svc.ServiceInstances {
-			if !strings.HasPrefix(instance.Name, toComplete) {
-				continue
-			}
-
 			result = append(result, instance.Name)
 		}
 	}
@@ -18,6 +18,7 @@ func setupTest(t *testing.T) {
 	t.Helper()
 	os.Setenv("TSURU_TARGET", "http://localhost:8080")
 	os.Setenv("TSURU_TOKEN", "sometoken")
+	t.Setenv("HOME", t.TempDir())
 	config.ResetFileSystem()
 
 	t.Cleanup(func() {
@@ -20,6 +20,7 @@ type ManagerV2 struct {
 	tree        *v2.CmdNode
 	contexts    []*Context
 	retryHook   func(err error) bool
+	errorHook   func(ctx *Context, err error) error
 	completions map[string]CompletionFunc
 
 	registeredCommands map[string]struct{}
@@ -30,6 +31,9 @@ type CompletionFunc func(toComplete string) ([]string, error)
 type ManagerV2Opts struct {
 	AfterFlagParseHook func()
 	RetryHook          func(err error) bool
+	// ErrorHook is called with the error returned by a command, the returned
+	// error replaces the original one.
+	ErrorHook func(ctx *Context, err error) error
 }
 
 func NewManagerV2(opts ...*ManagerV2Opts) *ManagerV2 {
@@ -55,6 +59,9 @@ func NewManagerV2(opts ...*ManagerV2Opts) *ManagerV2 {
 	if len(opts) == 1 && opts[0].RetryHook != nil {
 		m.retryHook = opts[0].RetryHook
 	}
+	if len(opts) == 1 && opts[0].ErrorHook != nil {
+		m.errorHook = opts[0].ErrorHook
+	}
 	return m
 }
 
@@ -271,7 +278,11 @@ func (m *ManagerV2) runCommand(command Command, cobraCommand *cobra.Command, arg
 		}(*context)
 	}
 
-	return command.Run(context)
+	err := command.Run(context)
+	if err != nil && m.errorHook != nil {
+		err = m.errorHook(context, err)
+	}
+	return err
 }
 
 func (m *ManagerV2) newContext(c Context) *Context {
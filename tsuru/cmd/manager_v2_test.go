@@ -1782,3 +1782,31 @@ func TestManagerV2_registerCompletionsOnCommand(t *testing.T) {
 		assert.NotNil(t, simpleNode.Command)
 	})
 }
+
+func TestManagerV2_Run_ErrorHook(t *testing.T) {
+	var receivedErr error
+	opts := &ManagerV2Opts{
+		ErrorHook: func(ctx *Context, err error) error {
+			receivedErr = err
+			return nil
+		},
+	}
+
+	manager := NewManagerV2(opts)
+	cmd := &mockCommand{
+		info: &Info{
+			Name: "test-cmd",
+			Desc: "Test command",
+		},
+		runFn: func(ctx *Context) error {
+			return assert.AnError
+		},
+	}
+
+	manager.Register(cmd)
+	manager.rootCmd.SetArgs([]string{"test-cmd"})
+
+	err := manager.rootCmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, assert.AnError, receivedErr)
+}
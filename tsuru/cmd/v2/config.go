@@ -92,6 +92,12 @@ func ColorStream() bool {
 	return def
 }
 
+// CredentialHelper returns the credential helper set in the configuration
+// file or in the TSURU_CREDENTIAL_HELPER environment variable.
+func CredentialHelper() string {
+	return defaultViper.GetString("credential-helper")
+}
+
 var TsuruConfigDir = config.JoinWithUserDir(".tsuru")
 
 // preSetupViper prepares viper for being used by NewProductionTsuruContext()
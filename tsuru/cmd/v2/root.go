@@ -37,8 +37,14 @@ func setupPFlagsAndCommands(rootCmd *cobra.Command) {
 	rootCmd.PersistentFlags().String("target", "", "Tsuru server endpoint")
 	defaultViper.BindPFlag("target", rootCmd.PersistentFlags().Lookup("target"))
 
-	rootCmd.PersistentFlags().Int("verbosity", 0, "Verbosity level: 1 => print HTTP requests; 2 => print HTTP requests/responses")
+	rootCmd.PersistentFlags().Int("verbosity", 0, "Verbosity level: 1 => print HTTP requests; 2 => print HTTP requests/responses; 3 => also print HTTP timings and request IDs")
 	defaultViper.BindPFlag("verbosity", rootCmd.PersistentFlags().Lookup("verbosity"))
+
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Print mutating HTTP requests instead of sending them")
+	defaultViper.BindPFlag("dry-run", rootCmd.PersistentFlags().Lookup("dry-run"))
+
+	rootCmd.PersistentFlags().Bool("confirm-protected", false, "Confirm the changes sent to a protected target without asking")
+	defaultViper.BindPFlag("confirm-protected", rootCmd.PersistentFlags().Lookup("confirm-protected"))
 }
 
 func rootPersistentPreRun(cmd *cobra.Command, args []string) {
@@ -50,6 +56,14 @@ func rootPersistentPreRun(cmd *cobra.Command, args []string) {
 	if v, err := cmd.Flags().GetInt("verbosity"); v > 0 && err == nil {
 		os.Setenv("TSURU_VERBOSITY", strconv.Itoa(v))
 	}
+
+	if v, err := cmd.Flags().GetBool("dry-run"); v && err == nil {
+		os.Setenv("TSURU_DRY_RUN", strconv.FormatBool(v))
+	}
+
+	if v, err := cmd.Flags().GetBool("confirm-protected"); v && err == nil {
+		os.Setenv("TSURU_CONFIRM_PROTECTED", strconv.FormatBool(v))
+	}
 }
 
 // parseFirstFlagsOnly handles only the first flags with cmd.ParseFlags()
@@ -652,3 +652,49 @@ func TestPreSetupViperTableColor(t *testing.T) {
 		assert.Nil(t, tablecli.TableConfig.BorderColorFunc)
 	})
 }
+
+func TestDryRunFlagSetsEnv(t *testing.T) {
+	if oldEnv, ok := os.LookupEnv("TSURU_DRY_RUN"); ok {
+		defer os.Setenv("TSURU_DRY_RUN", oldEnv)
+	} else {
+		defer os.Unsetenv("TSURU_DRY_RUN")
+	}
+	os.Unsetenv("TSURU_DRY_RUN")
+
+	rootCmd := NewRootCmd()
+	called := false
+	rootCmd.AddCommand(&cobra.Command{
+		Use: "newtestcommand",
+		Run: func(cmd *cobra.Command, args []string) {
+			called = true
+		},
+	})
+	rootCmd.SetArgs([]string{"--dry-run", "newtestcommand"})
+	rootCmd.Execute()
+	assert.True(t, called)
+	assert.Equal(t, "true", os.Getenv("TSURU_DRY_RUN"))
+}
+
+func TestConfirmProtectedFlagSetsEnv(t *testing.T) {
+	if oldEnv, ok := os.LookupEnv("TSURU_CONFIRM_PROTECTED"); ok {
+		defer os.Setenv("TSURU_CONFIRM_PROTECTED", oldEnv)
+	} else {
+		defer os.Unsetenv("TSURU_CONFIRM_PROTECTED")
+	}
+	os.Unsetenv("TSURU_CONFIRM_PROTECTED")
+
+	rootCmd := NewRootCmd()
+	newCmd := &cobra.Command{
+		Use: "newtestcommand",
+		Run: func(cmd *cobra.Command, args []string) {},
+	}
+	newCmd.Flags().BoolP("assume-yes", "y", false, "")
+	rootCmd.AddCommand(newCmd)
+	rootCmd.SetArgs([]string{"newtestcommand", "-y"})
+	rootCmd.Execute()
+	assert.Equal(t, "", os.Getenv("TSURU_CONFIRM_PROTECTED"))
+
+	rootCmd.SetArgs([]string{"--confirm-protected", "newtestcommand"})
+	rootCmd.Execute()
+	assert.Equal(t, "true", os.Getenv("TSURU_CONFIRM_PROTECTED"))
+}
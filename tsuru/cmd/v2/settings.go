@@ -0,0 +1,344 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v2
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+// Types of the client settings.
+const (
+	SettingTypeBool   = "bool"
+	SettingTypeInt    = "int"
+	SettingTypeString = "string"
+	// SettingTypePager accepts a boolean or the command used as pager.
+	SettingTypePager = "bool|command"
+)
+
+// Sources of the effective value of a setting.
+const (
+	SettingSourceEnv     = "env"
+	SettingSourceFile    = "file"
+	SettingSourceDefault = "default"
+)
+
+// Setting describes a key of the client configuration file.
+type Setting struct {
+	Key         string
+	Type        string
+	Description string
+	// Default returns the value used when the key is neither set in the
+	// environment nor in the file. Some defaults depend on the terminal.
+	Default func() string
+}
+
+func constDefault(value string) func() string {
+	return func() string { return value }
+}
+
+// Settings are the keys supported in the client configuration file.
+var Settings = []Setting{
+	{
+		Key:         "pager",
+		Type:        SettingTypePager,
+		Description: `Pager for long outputs: true uses "less -RFX", false disables paging, any other value is the pager command`,
+		Default:     constDefault("true"),
+	},
+	{
+		Key:         "disable-colors",
+		Type:        SettingTypeBool,
+		Description: "Disables colors in every output, also disabled by the NO_COLOR environment variable",
+		Default: func() string {
+			_, noColor := os.LookupEnv("NO_COLOR")
+			return strconv.FormatBool(noColor || !IsModernTerminal)
+		},
+	},
+	{
+		Key:         "color-stream",
+		Type:        SettingTypeBool,
+		Description: "Colors the streamed outputs of deploys and builds",
+		Default:     func() string { return strconv.FormatBool(IsModernTerminal) },
+	},
+	{
+		Key:         "table-color",
+		Type:        SettingTypeString,
+		Description: `Color of table borders, either a name like "hi-black" or a "#rrggbb" value`,
+		Default: func() string {
+			if isDarkBackground() && isModernTerminal() {
+				return "hi-black"
+			}
+			return ""
+		},
+	},
+	{
+		Key:         "table-utf8",
+		Type:        SettingTypeBool,
+		Description: "Draws table borders with UTF-8 characters",
+		Default:     func() string { return strconv.FormatBool(isModernTerminal()) },
+	},
+	{
+		Key:         "tab-writer",
+		Type:        SettingTypeBool,
+		Description: "Renders tables as tab separated columns instead of bordered tables",
+		Default:     constDefault("false"),
+	},
+	{
+		Key:         "tab-writer-truncate",
+		Type:        SettingTypeBool,
+		Description: "Truncates the columns of tab separated tables to the terminal width",
+		Default:     constDefault("false"),
+	},
+	{
+		Key:         "tab-writer-padding",
+		Type:        SettingTypeInt,
+		Description: "Padding between the columns of tab separated tables",
+		Default:     constDefault("2"),
+	},
+	{
+		Key:         "break-any",
+		Type:        SettingTypeBool,
+		Description: "Breaks long table cells at any character instead of at spaces",
+		Default:     constDefault("false"),
+	},
+	{
+		Key:         "force-wrap",
+		Type:        SettingTypeBool,
+		Description: "Wraps table cells even when the table fits the terminal",
+		Default:     constDefault("false"),
+	},
+	{
+		Key:         "credential-helper",
+		Type:        SettingTypeString,
+		Description: `Credential helper keeping the tokens instead of the files in ~/.tsuru, either a name, run as "tsuru-credential-<name>", or a path`,
+		Default:     constDefault(""),
+	},
+}
+
+// LookupSetting returns the setting named key, failing for unknown keys.
+func LookupSetting(key string) (Setting, error) {
+	for _, s := range Settings {
+		if s.Key == key {
+			return s, nil
+		}
+	}
+	keys := make([]string, len(Settings))
+	for i, s := range Settings {
+		keys[i] = s.Key
+	}
+	return Setting{}, fmt.Errorf("unknown setting %q, supported settings are: %s", key, strings.Join(keys, ", "))
+}
+
+// EnvName returns the environment variable overriding the setting.
+func (s Setting) EnvName() string {
+	return "TSURU_" + strings.ToUpper(strings.ReplaceAll(s.Key, "-", "_"))
+}
+
+// Parse converts value to the type stored in the configuration file.
+func (s Setting) Parse(value string) (interface{}, error) {
+	switch s.Type {
+	case SettingTypeBool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for %s: must be true or false", value, s.Key)
+		}
+		return v, nil
+	case SettingTypeInt:
+		v, err := strconv.Atoi(value)
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("invalid value %q for %s: must be a non-negative integer", value, s.Key)
+		}
+		return v, nil
+	case SettingTypePager:
+		if v, err := strconv.ParseBool(value); err == nil {
+			return v, nil
+		}
+		if strings.TrimSpace(value) == "" {
+			return nil, fmt.Errorf("invalid value %q for %s: must be true, false or a command", value, s.Key)
+		}
+		return value, nil
+	}
+	return value, nil
+}
+
+// Value returns the effective value of the setting and where it comes from,
+// reading the configuration file again so changes made by the running
+// command are seen.
+func (s Setting) Value() (value, source string) {
+	vip := viper.New()
+	vip.SetConfigFile(ConfigFile())
+	vip.ReadInConfig()
+	return s.value(vip)
+}
+
+func (s Setting) value(vip *viper.Viper) (string, string) {
+	if v := os.Getenv(s.EnvName()); v != "" {
+		return v, SettingSourceEnv
+	}
+	if vip.InConfig(s.Key) {
+		return fmt.Sprint(vip.Get(s.Key)), SettingSourceFile
+	}
+	return s.Default(), SettingSourceDefault
+}
+
+// aliasesKey holds the user-defined command aliases, see Aliases.
+const aliasesKey = "aliases"
+
+// Aliases returns the command aliases defined in the configuration file, as
+// in:
+//
+//	aliases:
+//	  dp: app deploy -a myapp .
+//	  prodlog: app log -a api --target prod -f
+func Aliases() (map[string]string, error) {
+	data, err := os.ReadFile(ConfigFile())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var content map[string]interface{}
+	if err = yaml.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %w", ConfigFile(), err)
+	}
+	return parseAliases(content[aliasesKey])
+}
+
+func parseAliases(value interface{}) (map[string]string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	items, ok := value.(map[interface{}]interface{})
+	if slice, isSlice := value.(yaml.MapSlice); isSlice {
+		items, ok = make(map[interface{}]interface{}, len(slice)), true
+		for _, item := range slice {
+			items[item.Key] = item.Value
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("invalid %s: must be a mapping of names to commands", aliasesKey)
+	}
+	aliases := make(map[string]string, len(items))
+	for name, expansion := range items {
+		str, ok := expansion.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid alias %q: must be a command line", fmt.Sprint(name))
+		}
+		aliases[fmt.Sprint(name)] = str
+	}
+	return aliases, nil
+}
+
+// ConfigFile returns the path of the client configuration file.
+func ConfigFile() string {
+	return filepath.Join(TsuruConfigDir, "client.yaml")
+}
+
+// SetSetting validates value and stores it in the configuration file.
+func SetSetting(key, value string) error {
+	s, err := LookupSetting(key)
+	if err != nil {
+		return err
+	}
+	v, err := s.Parse(value)
+	if err != nil {
+		return err
+	}
+	return updateConfigFile(func(content yaml.MapSlice) yaml.MapSlice {
+		for i := range content {
+			if content[i].Key == key {
+				content[i].Value = v
+				return content
+			}
+		}
+		return append(content, yaml.MapItem{Key: key, Value: v})
+	})
+}
+
+// UnsetSetting removes key from the configuration file, restoring its
+// default value.
+func UnsetSetting(key string) error {
+	if _, err := LookupSetting(key); err != nil {
+		return err
+	}
+	return updateConfigFile(func(content yaml.MapSlice) yaml.MapSlice {
+		result := content[:0]
+		for _, item := range content {
+			if item.Key != key {
+				result = append(result, item)
+			}
+		}
+		return result
+	})
+}
+
+// ValidateConfig checks that data is a valid configuration file: a YAML
+// mapping of known settings with values of the expected types.
+func ValidateConfig(data []byte) error {
+	var content yaml.MapSlice
+	if err := yaml.Unmarshal(data, &content); err != nil {
+		return fmt.Errorf("invalid YAML: %w", err)
+	}
+	for _, item := range content {
+		key := fmt.Sprint(item.Key)
+		if key == aliasesKey {
+			if _, err := parseAliases(item.Value); err != nil {
+				return err
+			}
+			continue
+		}
+		s, err := LookupSetting(key)
+		if err != nil {
+			return err
+		}
+		if _, err := s.Parse(fmt.Sprint(item.Value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func updateConfigFile(update func(yaml.MapSlice) yaml.MapSlice) error {
+	path := ConfigFile()
+	var content yaml.MapSlice
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err = yaml.Unmarshal(data, &content); err != nil {
+		return fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+	content = update(content)
+	if len(content) == 0 {
+		data = nil
+	} else if data, err = yaml.Marshal(content); err != nil {
+		return err
+	}
+	return WriteConfig(data)
+}
+
+// WriteConfig replaces the content of the configuration file.
+func WriteConfig(data []byte) error {
+	path := ConfigFile()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmpPath := path + "." + strconv.Itoa(os.Getpid()) + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v2
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupConfigDir(t *testing.T) {
+	originalDir := TsuruConfigDir
+	TsuruConfigDir = t.TempDir()
+	t.Cleanup(func() { TsuruConfigDir = originalDir })
+}
+
+func TestLookupSetting(t *testing.T) {
+	s, err := LookupSetting("tab-writer-padding")
+	require.NoError(t, err)
+	assert.Equal(t, SettingTypeInt, s.Type)
+	assert.Equal(t, "TSURU_TAB_WRITER_PADDING", s.EnvName())
+
+	_, err = LookupSetting("unknown")
+	assert.ErrorContains(t, err, `unknown setting "unknown", supported settings are: pager, disable-colors`)
+}
+
+func TestSettingParse(t *testing.T) {
+	tests := []struct {
+		key      string
+		value    string
+		expected interface{}
+		err      string
+	}{
+		{key: "tab-writer", value: "true", expected: true},
+		{key: "tab-writer", value: "yes", err: `invalid value "yes" for tab-writer: must be true or false`},
+		{key: "tab-writer-padding", value: "4", expected: 4},
+		{key: "tab-writer-padding", value: "-1", err: `invalid value "-1" for tab-writer-padding: must be a non-negative integer`},
+		{key: "pager", value: "false", expected: false},
+		{key: "pager", value: "more -d", expected: "more -d"},
+		{key: "pager", value: " ", err: `invalid value " " for pager: must be true, false or a command`},
+		{key: "table-color", value: "#ff0000", expected: "#ff0000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			s, err := LookupSetting(tt.key)
+			require.NoError(t, err)
+			v, err := s.Parse(tt.value)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestSetAndUnsetSetting(t *testing.T) {
+	setupConfigDir(t)
+	require.NoError(t, os.WriteFile(ConfigFile(), []byte("break-any: true\npager: less\n"), 0600))
+
+	require.NoError(t, SetSetting("pager", "false"))
+	require.NoError(t, SetSetting("tab-writer-padding", "4"))
+	data, err := os.ReadFile(ConfigFile())
+	require.NoError(t, err)
+	assert.Equal(t, "break-any: true\npager: false\ntab-writer-padding: 4\n", string(data))
+
+	assert.EqualError(t, SetSetting("tab-writer", "maybe"), `invalid value "maybe" for tab-writer: must be true or false`)
+
+	require.NoError(t, UnsetSetting("pager"))
+	data, err = os.ReadFile(ConfigFile())
+	require.NoError(t, err)
+	assert.Equal(t, "break-any: true\ntab-writer-padding: 4\n", string(data))
+}
+
+func TestSettingValueSources(t *testing.T) {
+	setupConfigDir(t)
+	t.Setenv("TSURU_TAB_WRITER", "")
+	s, err := LookupSetting("tab-writer")
+	require.NoError(t, err)
+
+	value, source := s.Value()
+	assert.Equal(t, "false", value)
+	assert.Equal(t, SettingSourceDefault, source)
+
+	require.NoError(t, SetSetting("tab-writer", "true"))
+	value, source = s.Value()
+	assert.Equal(t, "true", value)
+	assert.Equal(t, SettingSourceFile, source)
+
+	t.Setenv("TSURU_TAB_WRITER", "false")
+	value, source = s.Value()
+	assert.Equal(t, "false", value)
+	assert.Equal(t, SettingSourceEnv, source)
+}
+
+func TestValidateConfig(t *testing.T) {
+	assert.NoError(t, ValidateConfig([]byte("# comments only\n")))
+	assert.NoError(t, ValidateConfig([]byte("pager: false\ntab-writer-padding: 3\ntable-color: red\n")))
+	assert.EqualError(t, ValidateConfig([]byte("tab-writer-padding: wide\n")), `invalid value "wide" for tab-writer-padding: must be a non-negative integer`)
+	assert.ErrorContains(t, ValidateConfig([]byte("tab-writter: true\n")), `unknown setting "tab-writter"`)
+	assert.ErrorContains(t, ValidateConfig([]byte("pager: [less\n")), "invalid YAML")
+}
+
+func TestAliases(t *testing.T) {
+	setupConfigDir(t)
+	aliases, err := Aliases()
+	require.NoError(t, err)
+	assert.Nil(t, aliases)
+
+	require.NoError(t, os.WriteFile(ConfigFile(), []byte("tab-writer: true\naliases:\n  dp: app deploy -a myapp .\n  prodlog: app log -a api --target prod -f\n"), 0600))
+	aliases, err = Aliases()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"dp":      "app deploy -a myapp .",
+		"prodlog": "app log -a api --target prod -f",
+	}, aliases)
+
+	require.NoError(t, os.WriteFile(ConfigFile(), []byte("aliases:\n  - dp\n"), 0600))
+	_, err = Aliases()
+	assert.EqualError(t, err, "invalid aliases: must be a mapping of names to commands")
+}
+
+func TestValidateConfigAliases(t *testing.T) {
+	assert.NoError(t, ValidateConfig([]byte("aliases:\n  dp: app deploy .\n")))
+	assert.EqualError(t, ValidateConfig([]byte("aliases:\n  dp: [app, deploy]\n")), `invalid alias "dp": must be a command line`)
+}
@@ -12,6 +12,7 @@ var groupLabels = map[string]string{
 	"sub-resource": "Manage sub-resources:",
 	"plugin":       "Plugins:",
 	"shorthands":   "Shorthand commands:",
+	"aliases":      "Aliases:",
 }
 
 type CmdNode struct {
@@ -0,0 +1,300 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package credentials stores the tokens used to authenticate on tsuru
+// targets. Each target has its own tokens, kept in ~/.tsuru/token.d/<key>
+// and ~/.tsuru/token-v2.d/<key>.json, so switching targets never sends the
+// token of one target to another. The tokens may be kept by a credential
+// helper instead, see SetHelper.
+package credentials
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/tsuru/go-tsuruclient/pkg/config"
+	"github.com/tsuru/tsuru-client/tsuru/targets"
+)
+
+const (
+	tokenV1Directory = "token.d"
+	tokenV2Directory = "token-v2.d"
+
+	legacyTokenV1File = "token"
+	legacyTokenV2File = "token-v2.json"
+)
+
+var unsafeKeyChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Key returns the name under which the credentials of target are stored:
+// its label in the target list or, for targets that are not in the list, a
+// name derived from the URL.
+func Key(target string) (string, error) {
+	target = targets.NormalizeURL(target)
+	if target == "" {
+		return "", errors.New("empty target")
+	}
+	t, err := targets.FindByURL(target)
+	if err != nil {
+		return "", err
+	}
+	if t != nil {
+		return t.Label, nil
+	}
+	name := target[strings.Index(target, "://")+3:]
+	return "@" + strings.Trim(unsafeKeyChars.ReplaceAllString(name, "_"), "_"), nil
+}
+
+// CurrentKey returns the key of the current target, as selected by the
+// --target flag, the TSURU_TARGET environment variable or target-set.
+func CurrentKey() (string, error) {
+	target, err := config.GetTarget()
+	if err != nil {
+		return "", err
+	}
+	migrateLegacyTokens()
+	return Key(target)
+}
+
+// Token returns the token sent to the current target: the team token set in
+// TSURU_TOKEN or the token stored by the last login on that target. It
+// returns an empty string when there's no session.
+func Token() (string, error) {
+	if token := config.ReadTeamToken(); token != "" {
+		return token, nil
+	}
+	key, err := CurrentKey()
+	if err != nil {
+		return "", nil
+	}
+	return ReadTokenV1(key)
+}
+
+// CurrentTokenV2 returns the V2 token of the current target, or nil when
+// there's none.
+func CurrentTokenV2() (*config.TokenV2, error) {
+	key, err := CurrentKey()
+	if err != nil {
+		return nil, nil
+	}
+	return ReadTokenV2(key)
+}
+
+// HasSession reports whether there are usable credentials stored for key.
+// OIDC sessions are usable while the access token is valid or can be
+// refreshed.
+func HasSession(key string) bool {
+	if tokenV2, err := ReadTokenV2(key); err == nil && tokenV2 != nil && tokenV2.Scheme == "oidc" {
+		return tokenV2.OAuth2Token != nil && (tokenV2.OAuth2Token.Valid() || tokenV2.OAuth2Token.RefreshToken != "")
+	}
+	token, err := ReadTokenV1(key)
+	return err == nil && token != ""
+}
+
+func tokenV1Path(key string) string {
+	return config.JoinWithUserDir(".tsuru", tokenV1Directory, key)
+}
+
+func tokenV2Path(key string) string {
+	return config.JoinWithUserDir(".tsuru", tokenV2Directory, key+".json")
+}
+
+// ReadTokenV1 returns the token stored for key, or an empty string when
+// there's none.
+func ReadTokenV1(key string) (string, error) {
+	data, err := readSecret(key, secretTokenV1, tokenV1Path(key))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// ReadTokenV2 returns the V2 token stored for key, or nil when there's none.
+func ReadTokenV2(key string) (*config.TokenV2, error) {
+	data, err := readSecret(key, secretTokenV2, tokenV2Path(key))
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+	var token config.TokenV2
+	if err = json.Unmarshal(data, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// WriteTokenV1 stores the token for key.
+func WriteTokenV1(key, token string) error {
+	return writeSecret(key, secretTokenV1, tokenV1Path(key), []byte(token))
+}
+
+// WriteTokenV2 stores the V2 token for key.
+func WriteTokenV2(key string, token config.TokenV2) error {
+	data, err := json.MarshalIndent(&token, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeSecret(key, secretTokenV2, tokenV2Path(key), data)
+}
+
+// RemoveTokenV2 removes the V2 token stored for key, if any.
+func RemoveTokenV2(key string) error {
+	return removeSecret(key, secretTokenV2, tokenV2Path(key))
+}
+
+// Remove removes all the credentials stored for key.
+func Remove(key string) error {
+	if err := removeSecret(key, secretTokenV1, tokenV1Path(key)); err != nil {
+		return err
+	}
+	return RemoveTokenV2(key)
+}
+
+// RemoveLegacy removes the tokens written by older versions that could not
+// be moved to a target.
+func RemoveLegacy() error {
+	if err := removeFile(config.JoinWithUserDir(".tsuru", legacyTokenV1File)); err != nil {
+		return err
+	}
+	return removeFile(config.JoinWithUserDir(".tsuru", legacyTokenV2File))
+}
+
+// migrateLegacyTokens moves the tokens written by older versions in
+// ~/.tsuru/token and ~/.tsuru/token-v2.json to the target they were created
+// for, which is the one saved by target-set.
+func migrateLegacyTokens() {
+	legacyTokens := []struct {
+		path, name string
+		keyPath    func(string) string
+	}{
+		{config.JoinWithUserDir(".tsuru", legacyTokenV1File), secretTokenV1, tokenV1Path},
+		{config.JoinWithUserDir(".tsuru", legacyTokenV2File), secretTokenV2, tokenV2Path},
+	}
+	var key string
+	for _, legacy := range legacyTokens {
+		data, err := readFile(legacy.path)
+		if err != nil {
+			continue
+		}
+		if key == "" {
+			target, err := readFile(config.JoinWithUserDir(".tsuru", "target"))
+			if err != nil {
+				return
+			}
+			if key, err = Key(string(target)); err != nil {
+				return
+			}
+		}
+		existing, err := readSecret(key, legacy.name, legacy.keyPath(key))
+		if err != nil {
+			continue
+		}
+		if len(existing) == 0 {
+			if err = writeSecret(key, legacy.name, legacy.keyPath(key), data); err != nil {
+				continue
+			}
+		}
+		config.Filesystem().Remove(legacy.path)
+	}
+}
+
+// readSecret returns the secret name of key, stored in path or kept by the
+// credential helper. Secrets still stored in files are moved to the helper.
+// It returns no data when there's no secret.
+func readSecret(key, name, path string) ([]byte, error) {
+	if helper == nil {
+		data, err := readFile(path)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return data, err
+	}
+	secret, err := helper.get(key, name)
+	if err != nil || secret != "" {
+		return []byte(secret), err
+	}
+	data, err := readFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = helper.store(key, name, string(data)); err != nil {
+		return nil, err
+	}
+	return data, removeFile(path)
+}
+
+func writeSecret(key, name, path string, data []byte) error {
+	if helper == nil {
+		return writeFile(path, data)
+	}
+	if err := helper.store(key, name, string(data)); err != nil {
+		return err
+	}
+	return removeFile(path)
+}
+
+func removeSecret(key, name, path string) error {
+	if helper != nil {
+		if err := helper.erase(key, name); err != nil {
+			return err
+		}
+	}
+	return removeFile(path)
+}
+
+func readFile(path string) ([]byte, error) {
+	f, err := config.Filesystem().Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+func writeFile(path string, data []byte) error {
+	fsystem := config.Filesystem()
+	if err := fsystem.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := fsystem.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	n, err := f.Write(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return errors.New("Failed to write token file.")
+	}
+	return nil
+}
+
+func removeFile(path string) error {
+	err := config.Filesystem().Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+type tokenProvider struct{}
+
+func (tokenProvider) Token() (string, error) {
+	return Token()
+}
+
+// TokenProvider returns a provider of the token of the current target, used
+// by plugins and websockets.
+func TokenProvider() config.TokenProvider {
+	return tokenProvider{}
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package credentials
+
+import (
+	"os"
+	"time"
+
+	"github.com/tsuru/go-tsuruclient/pkg/config"
+	"golang.org/x/oauth2"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestKey(c *check.C) {
+	key, err := Key("http://staging.tsuru.io/")
+	c.Assert(err, check.IsNil)
+	c.Assert(key, check.Equals, "staging")
+	key, err = Key("prod.tsuru.io")
+	c.Assert(err, check.IsNil)
+	c.Assert(key, check.Equals, "@prod.tsuru.io")
+	key, err = Key("https://prod.tsuru.io")
+	c.Assert(err, check.IsNil)
+	c.Assert(key, check.Equals, "prod")
+	key, err = Key("http://localhost:8080/api")
+	c.Assert(err, check.IsNil)
+	c.Assert(key, check.Equals, "@localhost_8080_api")
+}
+
+func (s *S) TestCurrentKey(c *check.C) {
+	key, err := CurrentKey()
+	c.Assert(err, check.IsNil)
+	c.Assert(key, check.Equals, "staging")
+	os.Setenv("TSURU_TARGET", "prod")
+	key, err = CurrentKey()
+	c.Assert(err, check.IsNil)
+	c.Assert(key, check.Equals, "prod")
+	os.Setenv("TSURU_TARGET", "https://prod.tsuru.io")
+	key, err = CurrentKey()
+	c.Assert(err, check.IsNil)
+	c.Assert(key, check.Equals, "prod")
+}
+
+func (s *S) TestTokenPerTarget(c *check.C) {
+	c.Assert(WriteTokenV1("staging", "staging-token"), check.IsNil)
+	token, err := Token()
+	c.Assert(err, check.IsNil)
+	c.Assert(token, check.Equals, "staging-token")
+	os.Setenv("TSURU_TARGET", "prod")
+	token, err = Token()
+	c.Assert(err, check.IsNil)
+	c.Assert(token, check.Equals, "")
+	c.Assert(WriteTokenV1("prod", "prod-token"), check.IsNil)
+	token, err = Token()
+	c.Assert(err, check.IsNil)
+	c.Assert(token, check.Equals, "prod-token")
+}
+
+func (s *S) TestTokenTeamToken(c *check.C) {
+	c.Assert(WriteTokenV1("staging", "staging-token"), check.IsNil)
+	os.Setenv("TSURU_TOKEN", "team-token")
+	token, err := Token()
+	c.Assert(err, check.IsNil)
+	c.Assert(token, check.Equals, "team-token")
+}
+
+func (s *S) TestTokenNoTarget(c *check.C) {
+	c.Assert(s.fs.Remove(config.JoinWithUserDir(".tsuru", "target")), check.IsNil)
+	token, err := Token()
+	c.Assert(err, check.IsNil)
+	c.Assert(token, check.Equals, "")
+}
+
+func (s *S) TestTokenV2(c *check.C) {
+	token := config.TokenV2{
+		Scheme:      "oidc",
+		OAuth2Token: &oauth2.Token{AccessToken: "access", RefreshToken: "refresh"},
+	}
+	c.Assert(WriteTokenV2("prod", token), check.IsNil)
+	got, err := ReadTokenV2("prod")
+	c.Assert(err, check.IsNil)
+	c.Assert(got, check.DeepEquals, &token)
+	got, err = CurrentTokenV2()
+	c.Assert(err, check.IsNil)
+	c.Assert(got, check.IsNil)
+	c.Assert(RemoveTokenV2("prod"), check.IsNil)
+	got, err = ReadTokenV2("prod")
+	c.Assert(err, check.IsNil)
+	c.Assert(got, check.IsNil)
+}
+
+func (s *S) TestHasSession(c *check.C) {
+	c.Assert(HasSession("staging"), check.Equals, false)
+	c.Assert(WriteTokenV1("staging", "staging-token"), check.IsNil)
+	c.Assert(HasSession("staging"), check.Equals, true)
+	c.Assert(WriteTokenV2("prod", config.TokenV2{
+		Scheme:      "oidc",
+		OAuth2Token: &oauth2.Token{AccessToken: "access", Expiry: time.Now().Add(-time.Hour)},
+	}), check.IsNil)
+	c.Assert(HasSession("prod"), check.Equals, false)
+	c.Assert(WriteTokenV2("prod", config.TokenV2{
+		Scheme:      "oidc",
+		OAuth2Token: &oauth2.Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Hour)},
+	}), check.IsNil)
+	c.Assert(HasSession("prod"), check.Equals, true)
+}
+
+func (s *S) TestRemove(c *check.C) {
+	c.Assert(WriteTokenV1("staging", "staging-token"), check.IsNil)
+	c.Assert(WriteTokenV2("staging", config.TokenV2{Scheme: "oidc"}), check.IsNil)
+	c.Assert(WriteTokenV1("prod", "prod-token"), check.IsNil)
+	c.Assert(Remove("staging"), check.IsNil)
+	c.Assert(Remove("unknown"), check.IsNil)
+	c.Assert(HasSession("staging"), check.Equals, false)
+	token, err := ReadTokenV2("staging")
+	c.Assert(err, check.IsNil)
+	c.Assert(token, check.IsNil)
+	c.Assert(HasSession("prod"), check.Equals, true)
+}
+
+func (s *S) TestMigrateLegacyTokens(c *check.C) {
+	s.writeFile(c, config.JoinWithUserDir(".tsuru", "token"), "legacy-token")
+	os.Setenv("TSURU_TARGET", "prod")
+	token, err := Token()
+	c.Assert(err, check.IsNil)
+	c.Assert(token, check.Equals, "")
+	token, err = ReadTokenV1("staging")
+	c.Assert(err, check.IsNil)
+	c.Assert(token, check.Equals, "legacy-token")
+	c.Assert(s.fs.HasAction("remove "+config.JoinWithUserDir(".tsuru", "token")), check.Equals, true)
+}
+
+func (s *S) TestMigrateLegacyTokensKeepsTargetTokens(c *check.C) {
+	s.writeFile(c, config.JoinWithUserDir(".tsuru", "token"), "legacy-token")
+	c.Assert(WriteTokenV1("staging", "staging-token"), check.IsNil)
+	token, err := Token()
+	c.Assert(err, check.IsNil)
+	c.Assert(token, check.Equals, "staging-token")
+	c.Assert(s.fs.HasAction("remove "+config.JoinWithUserDir(".tsuru", "token")), check.Equals, true)
+}
+
+func (s *S) TestRemoveLegacy(c *check.C) {
+	c.Assert(s.fs.Remove(config.JoinWithUserDir(".tsuru", "target")), check.IsNil)
+	s.writeFile(c, config.JoinWithUserDir(".tsuru", "token"), "legacy-token")
+	c.Assert(RemoveLegacy(), check.IsNil)
+	c.Assert(s.fs.HasAction("remove "+config.JoinWithUserDir(".tsuru", "token")), check.Equals, true)
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package credentials
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/tsuru/tsuru/exec"
+)
+
+// Names of the secrets kept by credential helpers for each target.
+const (
+	secretTokenV1 = "token"
+	secretTokenV2 = "token-v2"
+)
+
+// helperPrefix is prepended to the names of credential helpers that are not
+// paths, so "keychain" runs the tsuru-credential-keychain executable.
+const helperPrefix = "tsuru-credential-"
+
+var (
+	helper     *credentialHelper
+	helperExec exec.Executor = exec.OsExecutor{}
+)
+
+// SetHelper makes the tokens be kept by the credential helper name instead
+// of the files in ~/.tsuru. An empty name restores the files.
+//
+// A credential helper is an executable run with one of the actions get,
+// store or erase as argument. It reads a JSON request from the standard
+// input, with the target and the name of the secret, either "token" or
+// "token-v2", and for store the secret itself:
+//
+//	{"target": "prod", "name": "token", "secret": "..."}
+//
+// The get action writes the secret to the standard output, as in
+// {"secret": "..."}, with an empty secret when there's none. Failures are
+// reported with a non-zero exit status and a message in the standard error.
+func SetHelper(name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		helper = nil
+		return
+	}
+	if !strings.ContainsAny(name, `/\`) {
+		name = helperPrefix + name
+	}
+	helper = &credentialHelper{command: name, cache: map[string]string{}}
+}
+
+type helperRequest struct {
+	Target string `json:"target"`
+	Name   string `json:"name"`
+	Secret string `json:"secret,omitempty"`
+}
+
+type helperResponse struct {
+	Secret string `json:"secret"`
+}
+
+// credentialHelper runs the credential helper command, caching the secrets
+// it returns so each one is read at most once per run.
+type credentialHelper struct {
+	command string
+
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+func (h *credentialHelper) get(key, name string) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if secret, ok := h.cache[key+"/"+name]; ok {
+		return secret, nil
+	}
+	var resp helperResponse
+	if err := h.run("get", helperRequest{Target: key, Name: name}, &resp); err != nil {
+		return "", err
+	}
+	h.cache[key+"/"+name] = resp.Secret
+	return resp.Secret, nil
+}
+
+func (h *credentialHelper) store(key, name, secret string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := h.run("store", helperRequest{Target: key, Name: name, Secret: secret}, nil); err != nil {
+		return err
+	}
+	h.cache[key+"/"+name] = secret
+	return nil
+}
+
+func (h *credentialHelper) erase(key, name string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := h.run("erase", helperRequest{Target: key, Name: name}, nil); err != nil {
+		return err
+	}
+	h.cache[key+"/"+name] = ""
+	return nil
+}
+
+func (h *credentialHelper) run(action string, req helperRequest, result interface{}) error {
+	input, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	var stdout, stderr bytes.Buffer
+	err = helperExec.Execute(exec.ExecuteOptions{
+		Cmd:    h.command,
+		Args:   []string{action},
+		Stdin:  bytes.NewReader(input),
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("credential helper %s %s failed: %w: %s", h.command, action, err, msg)
+		}
+		return fmt.Errorf("credential helper %s %s failed: %w", h.command, action, err)
+	}
+	if result == nil || len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return nil
+	}
+	if err = json.Unmarshal(stdout.Bytes(), result); err != nil {
+		return fmt.Errorf("invalid response from credential helper %s: %w", h.command, err)
+	}
+	return nil
+}
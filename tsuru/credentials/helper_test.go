@@ -0,0 +1,117 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package credentials
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/tsuru/go-tsuruclient/pkg/config"
+	"github.com/tsuru/tsuru/exec"
+	"golang.org/x/oauth2"
+	check "gopkg.in/check.v1"
+)
+
+// fakeHelper is a credential helper keeping the secrets in memory.
+type fakeHelper struct {
+	secrets map[string]string
+	calls   []string
+	err     error
+}
+
+func (f *fakeHelper) Execute(opts exec.ExecuteOptions) error {
+	var req helperRequest
+	if err := json.NewDecoder(opts.Stdin).Decode(&req); err != nil {
+		return err
+	}
+	action := opts.Args[0]
+	f.calls = append(f.calls, fmt.Sprintf("%s %s %s/%s", opts.Cmd, action, req.Target, req.Name))
+	if f.err != nil {
+		io.WriteString(opts.Stderr, "keychain locked\n")
+		return f.err
+	}
+	switch action {
+	case "get":
+		return json.NewEncoder(opts.Stdout).Encode(helperResponse{Secret: f.secrets[req.Target+"/"+req.Name]})
+	case "store":
+		f.secrets[req.Target+"/"+req.Name] = req.Secret
+	case "erase":
+		delete(f.secrets, req.Target+"/"+req.Name)
+	}
+	return nil
+}
+
+func (s *S) setHelper(name string) *fakeHelper {
+	fake := &fakeHelper{secrets: map[string]string{}}
+	helperExec = fake
+	SetHelper(name)
+	s.restoreHelper = func() {
+		SetHelper("")
+		helperExec = exec.OsExecutor{}
+	}
+	return fake
+}
+
+func (s *S) TestSetHelper(c *check.C) {
+	SetHelper("keychain")
+	c.Assert(helper.command, check.Equals, "tsuru-credential-keychain")
+	SetHelper("/usr/local/bin/vault-helper")
+	c.Assert(helper.command, check.Equals, "/usr/local/bin/vault-helper")
+	SetHelper(" ")
+	c.Assert(helper, check.IsNil)
+}
+
+func (s *S) TestHelperStoresTokens(c *check.C) {
+	fake := s.setHelper("keychain")
+	c.Assert(WriteTokenV1("staging", "staging-token"), check.IsNil)
+	token := config.TokenV2{Scheme: "oidc", OAuth2Token: &oauth2.Token{AccessToken: "access"}}
+	c.Assert(WriteTokenV2("staging", token), check.IsNil)
+	c.Assert(fake.secrets["staging/token"], check.Equals, "staging-token")
+	c.Assert(fake.secrets["staging/token-v2"], check.Not(check.Equals), "")
+	_, err := s.fs.Open(tokenV1Path("staging"))
+	c.Assert(err, check.NotNil)
+
+	SetHelper("keychain")
+	got, err := Token()
+	c.Assert(err, check.IsNil)
+	c.Assert(got, check.Equals, "staging-token")
+	gotV2, err := ReadTokenV2("staging")
+	c.Assert(err, check.IsNil)
+	c.Assert(gotV2, check.DeepEquals, &token)
+	got, err = Token()
+	c.Assert(err, check.IsNil)
+	c.Assert(got, check.Equals, "staging-token")
+	c.Assert(fake.calls, check.DeepEquals, []string{
+		"tsuru-credential-keychain store staging/token",
+		"tsuru-credential-keychain store staging/token-v2",
+		"tsuru-credential-keychain get staging/token",
+		"tsuru-credential-keychain get staging/token-v2",
+	})
+
+	c.Assert(Remove("staging"), check.IsNil)
+	c.Assert(fake.secrets, check.HasLen, 0)
+	c.Assert(HasSession("staging"), check.Equals, false)
+}
+
+func (s *S) TestHelperMovesTokenFiles(c *check.C) {
+	c.Assert(WriteTokenV1("staging", "staging-token"), check.IsNil)
+	fake := s.setHelper("keychain")
+	token, err := ReadTokenV1("staging")
+	c.Assert(err, check.IsNil)
+	c.Assert(token, check.Equals, "staging-token")
+	c.Assert(fake.secrets["staging/token"], check.Equals, "staging-token")
+	c.Assert(s.fs.HasAction("remove "+tokenV1Path("staging")), check.Equals, true)
+}
+
+func (s *S) TestHelperFailure(c *check.C) {
+	fake := s.setHelper("keychain")
+	fake.err = errors.New("exit status 1")
+	_, err := ReadTokenV1("staging")
+	c.Assert(err, check.ErrorMatches, "credential helper tsuru-credential-keychain get failed: exit status 1: keychain locked")
+	err = WriteTokenV1("staging", "staging-token")
+	c.Assert(err, check.ErrorMatches, "credential helper tsuru-credential-keychain store failed: .*")
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package credentials
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tsuru/go-tsuruclient/pkg/config"
+	"golang.org/x/oauth2"
+)
+
+// refreshAhead is how long before expiring OIDC access tokens are refreshed,
+// so the requests of long-running commands, like deploys and followed logs,
+// are not sent with tokens about to expire.
+const refreshAhead = 2 * time.Minute
+
+// refreshTokenWarning is how long before the refresh token expires users are
+// warned to login again.
+const refreshTokenWarning = 24 * time.Hour
+
+// NewOIDCTokenSource returns a token source refreshing the OIDC token of
+// the target identified by key ahead of its expiry, storing refreshed tokens
+// back for that target only.
+func NewOIDCTokenSource(key string, token *config.TokenV2) oauth2.TokenSource {
+	refresher := token.OAuth2Config.TokenSource(context.Background(), token.OAuth2Token)
+	return &oidcTokenSource{
+		base:   oauth2.ReuseTokenSourceWithExpiry(token.OAuth2Token, refresher, refreshAhead),
+		key:    key,
+		last:   token,
+		stderr: os.Stderr,
+	}
+}
+
+type oidcTokenSource struct {
+	base   oauth2.TokenSource
+	key    string
+	last   *config.TokenV2
+	stderr io.Writer
+
+	mu     sync.Mutex
+	warned bool
+}
+
+func (s *oidcTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	newToken, err := s.base.Token()
+	if err != nil {
+		return nil, err
+	}
+	s.warnRefreshTokenExpiry(newToken)
+	if reflect.DeepEqual(s.last.OAuth2Token, newToken) {
+		return newToken, nil
+	}
+	fmt.Fprintf(s.stderr, "The OIDC token was refreshed and expiry in %s\n", time.Until(newToken.Expiry).Round(time.Second))
+	s.last.OAuth2Token = newToken
+	if err = WriteTokenV2(s.key, *s.last); err != nil {
+		fmt.Fprintf(s.stderr, "Could not write refreshed token: %s\n", err.Error())
+		return nil, err
+	}
+	if err = WriteTokenV1(s.key, newToken.AccessToken); err != nil {
+		fmt.Fprintf(s.stderr, "Could not write legacy refreshed token: %s\n", err.Error())
+		return nil, err
+	}
+	return newToken, nil
+}
+
+// warnRefreshTokenExpiry warns, once, that the session can't be refreshed
+// for long, as the refresh token itself is about to expire.
+func (s *oidcTokenSource) warnRefreshTokenExpiry(t *oauth2.Token) {
+	if s.warned {
+		return
+	}
+	expiry := RefreshTokenExpiry(t)
+	if expiry.IsZero() || time.Until(expiry) > refreshTokenWarning {
+		return
+	}
+	s.warned = true
+	if until := time.Until(expiry); until > 0 {
+		fmt.Fprintf(s.stderr, "Warning: the OIDC session expires in %s, run \"tsuru login\" to renew it.\n", until.Round(time.Second))
+	}
+}
+
+// RefreshTokenExpiry returns when the refresh token of t expires, as told by
+// the token response that issued it or by the claims of JWT refresh tokens.
+// It returns the zero time when the expiry is unknown.
+func RefreshTokenExpiry(t *oauth2.Token) time.Time {
+	if t == nil || t.RefreshToken == "" {
+		return time.Time{}
+	}
+	for _, name := range []string{"refresh_expires_in", "refresh_token_expires_in"} {
+		if seconds := extraSeconds(t.Extra(name)); seconds > 0 {
+			return time.Now().Add(time.Duration(seconds) * time.Second)
+		}
+	}
+	if exp, ok := JWTClaims(t.RefreshToken)["exp"].(float64); ok && exp > 0 {
+		return time.Unix(int64(exp), 0)
+	}
+	return time.Time{}
+}
+
+func extraSeconds(value interface{}) int64 {
+	switch v := value.(type) {
+	case float64:
+		return int64(v)
+	case string:
+		seconds, _ := strconv.ParseInt(v, 10, 64)
+		return seconds
+	}
+	return 0
+}
+
+// JWTClaims returns the claims of token when it's a JWT. The signature is not
+// verified, the claims are only used to inform users.
+func JWTClaims(token string) map[string]interface{} {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil
+	}
+	var claims map[string]interface{}
+	if json.Unmarshal(payload, &claims) != nil {
+		return nil
+	}
+	return claims
+}
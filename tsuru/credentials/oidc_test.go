@@ -0,0 +1,129 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package credentials
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/tsuru/go-tsuruclient/pkg/config"
+	"golang.org/x/oauth2"
+	check "gopkg.in/check.v1"
+)
+
+func newRefreshingIDP(c *check.C, response string) (*httptest.Server, *int32) {
+	var calls int32
+	idp := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		c.Check(req.FormValue("grant_type"), check.Equals, "refresh_token")
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Write([]byte(response))
+	}))
+	return idp, &calls
+}
+
+func newTestTokenSource(tokenURL string, token *oauth2.Token) (*oidcTokenSource, *bytes.Buffer) {
+	source := NewOIDCTokenSource("staging", &config.TokenV2{
+		Scheme:       "oidc",
+		OAuth2Token:  token,
+		OAuth2Config: &oauth2.Config{ClientID: "tsuru", Endpoint: oauth2.Endpoint{TokenURL: tokenURL}},
+	}).(*oidcTokenSource)
+	stderr := &bytes.Buffer{}
+	source.stderr = stderr
+	return source, stderr
+}
+
+func jwt(claims string) string {
+	return "header." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".signature"
+}
+
+func (s *S) TestOIDCTokenSourceRefreshesAhead(c *check.C) {
+	idp, calls := newRefreshingIDP(c, `{"access_token":"new","refresh_token":"refresh","token_type":"bearer","expires_in":3600}`)
+	defer idp.Close()
+	source, stderr := newTestTokenSource(idp.URL, &oauth2.Token{
+		AccessToken:  "old",
+		RefreshToken: "refresh",
+		Expiry:       time.Now().Add(time.Minute),
+	})
+	token, err := source.Token()
+	c.Assert(err, check.IsNil)
+	c.Assert(token.AccessToken, check.Equals, "new")
+	c.Assert(atomic.LoadInt32(calls), check.Equals, int32(1))
+	c.Assert(strings.Contains(stderr.String(), "The OIDC token was refreshed"), check.Equals, true)
+	stored, err := ReadTokenV2("staging")
+	c.Assert(err, check.IsNil)
+	c.Assert(stored.OAuth2Token.AccessToken, check.Equals, "new")
+	legacy, err := ReadTokenV1("staging")
+	c.Assert(err, check.IsNil)
+	c.Assert(legacy, check.Equals, "new")
+
+	token, err = source.Token()
+	c.Assert(err, check.IsNil)
+	c.Assert(token.AccessToken, check.Equals, "new")
+	c.Assert(atomic.LoadInt32(calls), check.Equals, int32(1))
+}
+
+func (s *S) TestOIDCTokenSourceKeepsValidToken(c *check.C) {
+	idp, calls := newRefreshingIDP(c, `{}`)
+	defer idp.Close()
+	source, stderr := newTestTokenSource(idp.URL, &oauth2.Token{
+		AccessToken:  "current",
+		RefreshToken: "refresh",
+		Expiry:       time.Now().Add(time.Hour),
+	})
+	token, err := source.Token()
+	c.Assert(err, check.IsNil)
+	c.Assert(token.AccessToken, check.Equals, "current")
+	c.Assert(atomic.LoadInt32(calls), check.Equals, int32(0))
+	c.Assert(stderr.String(), check.Equals, "")
+}
+
+func (s *S) TestOIDCTokenSourceWarnsRefreshTokenExpiry(c *check.C) {
+	source, stderr := newTestTokenSource("http://idp.invalid", &oauth2.Token{
+		AccessToken:  "current",
+		RefreshToken: jwt(fmt.Sprintf(`{"exp":%d}`, time.Now().Add(2*time.Hour).Unix())),
+		Expiry:       time.Now().Add(time.Hour),
+	})
+	_, err := source.Token()
+	c.Assert(err, check.IsNil)
+	_, err = source.Token()
+	c.Assert(err, check.IsNil)
+	c.Assert(stderr.String(), check.Matches, `Warning: the OIDC session expires in (1h59m59s|2h0m0s), run "tsuru login" to renew it.\n`)
+}
+
+func (s *S) TestOIDCTokenSourceWarnsRefreshExpiresIn(c *check.C) {
+	idp, _ := newRefreshingIDP(c, `{"access_token":"new","refresh_token":"refresh","token_type":"bearer","expires_in":3600,"refresh_expires_in":1800}`)
+	defer idp.Close()
+	source, stderr := newTestTokenSource(idp.URL, &oauth2.Token{
+		AccessToken:  "old",
+		RefreshToken: "refresh",
+		Expiry:       time.Now().Add(-time.Minute),
+	})
+	_, err := source.Token()
+	c.Assert(err, check.IsNil)
+	c.Assert(strings.Contains(stderr.String(), "Warning: the OIDC session expires in 30m0s"), check.Equals, true)
+}
+
+func (s *S) TestRefreshTokenExpiry(c *check.C) {
+	c.Assert(RefreshTokenExpiry(nil).IsZero(), check.Equals, true)
+	c.Assert(RefreshTokenExpiry(&oauth2.Token{RefreshToken: "opaque"}).IsZero(), check.Equals, true)
+	exp := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	expiry := RefreshTokenExpiry(&oauth2.Token{RefreshToken: jwt(fmt.Sprintf(`{"exp":%d}`, exp.Unix()))})
+	c.Assert(expiry.Equal(exp), check.Equals, true)
+}
+
+func (s *S) TestJWTClaims(c *check.C) {
+	claims := JWTClaims(jwt(`{"iss":"https://idp.example.com","exp":10}`))
+	c.Assert(claims["iss"], check.Equals, "https://idp.example.com")
+	c.Assert(claims["exp"], check.Equals, float64(10))
+	c.Assert(JWTClaims("opaque"), check.IsNil)
+	c.Assert(JWTClaims("a.!!!.c"), check.IsNil)
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package credentials
+
+import (
+	"os"
+	"testing"
+
+	"github.com/tsuru/go-tsuruclient/pkg/config"
+	"github.com/tsuru/tsuru/fs/fstest"
+	check "gopkg.in/check.v1"
+)
+
+type S struct {
+	fs            *fstest.RecordingFs
+	restoreHelper func()
+}
+
+var _ = check.Suite(&S{})
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+func (s *S) SetUpTest(c *check.C) {
+	os.Unsetenv("TSURU_TARGET")
+	os.Unsetenv("TSURU_TOKEN")
+	s.fs = &fstest.RecordingFs{}
+	config.SetFileSystem(s.fs)
+	s.writeFile(c, config.JoinWithUserDir(".tsuru", "targets"), "staging\thttp://staging.tsuru.io\nprod\thttps://prod.tsuru.io/\n")
+	s.writeFile(c, config.JoinWithUserDir(".tsuru", "target"), "http://staging.tsuru.io")
+}
+
+func (s *S) TearDownTest(c *check.C) {
+	if s.restoreHelper != nil {
+		s.restoreHelper()
+		s.restoreHelper = nil
+	}
+	os.Unsetenv("TSURU_TARGET")
+	os.Unsetenv("TSURU_TOKEN")
+	config.ResetFileSystem()
+}
+
+func (s *S) writeFile(c *check.C, path, content string) {
+	f, err := s.fs.Create(path)
+	c.Assert(err, check.IsNil)
+	defer f.Close()
+	_, err = f.WriteString(content)
+	c.Assert(err, check.IsNil)
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package credentials
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/tsuru/go-tsuruclient/pkg/config"
+)
+
+const tlsDirectory = "tls.d"
+
+// TLS holds the TLS settings used to connect to a target.
+type TLS struct {
+	CAFile             string `json:"caFile,omitempty"`
+	ClientCert         string `json:"clientCert,omitempty"`
+	ClientKey          string `json:"clientKey,omitempty"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty"`
+}
+
+// IsZero reports whether no TLS setting is defined, in which case the
+// system defaults are used.
+func (t TLS) IsZero() bool {
+	return t == TLS{}
+}
+
+// Validate checks that the CA bundle and the client key pair can be loaded.
+func (t TLS) Validate() error {
+	if (t.ClientCert == "") != (t.ClientKey == "") {
+		return errors.New("client certificate and key must be provided together")
+	}
+	_, err := t.Config()
+	return err
+}
+
+// Config builds the tls.Config for the settings, or nil when none is
+// defined.
+func (t TLS) Config() (*tls.Config, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: t.InsecureSkipVerify}
+	if t.CAFile != "" {
+		data, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no PEM certificates found in CA file %q", t.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if t.ClientCert != "" || t.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(t.ClientCert, t.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// Env returns the settings as environment variables, the way they are passed
+// to plugins.
+func (t TLS) Env() []string {
+	var envs []string
+	if t.CAFile != "" {
+		envs = append(envs, "TSURU_CA_FILE="+t.CAFile)
+	}
+	if t.ClientCert != "" {
+		envs = append(envs, "TSURU_CLIENT_CERT="+t.ClientCert)
+	}
+	if t.ClientKey != "" {
+		envs = append(envs, "TSURU_CLIENT_KEY="+t.ClientKey)
+	}
+	if t.InsecureSkipVerify {
+		envs = append(envs, "TSURU_INSECURE_SKIP_VERIFY=true")
+	}
+	return envs
+}
+
+func tlsPath(key string) string {
+	return config.JoinWithUserDir(".tsuru", tlsDirectory, key+".json")
+}
+
+// ReadTLS returns the TLS settings stored for key.
+func ReadTLS(key string) (TLS, error) {
+	var t TLS
+	data, err := readFile(tlsPath(key))
+	if os.IsNotExist(err) {
+		return t, nil
+	}
+	if err != nil {
+		return t, err
+	}
+	err = json.Unmarshal(data, &t)
+	return t, err
+}
+
+// WriteTLS stores the TLS settings for key, removing them when empty.
+func WriteTLS(key string, t TLS) error {
+	if t.IsZero() {
+		return RemoveTLS(key)
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(tlsPath(key), data)
+}
+
+// RemoveTLS removes the TLS settings stored for key, if any.
+func RemoveTLS(key string) error {
+	return removeFile(tlsPath(key))
+}
+
+// CurrentTLS returns the TLS settings of the current target. The
+// TSURU_CA_FILE, TSURU_CLIENT_CERT, TSURU_CLIENT_KEY and
+// TSURU_INSECURE_SKIP_VERIFY environment variables override the stored
+// ones.
+func CurrentTLS() (TLS, error) {
+	var t TLS
+	if key, err := CurrentKey(); err == nil {
+		if t, err = ReadTLS(key); err != nil {
+			return t, err
+		}
+	}
+	if v := os.Getenv("TSURU_CA_FILE"); v != "" {
+		t.CAFile = v
+	}
+	if v := os.Getenv("TSURU_CLIENT_CERT"); v != "" {
+		t.ClientCert = v
+	}
+	if v := os.Getenv("TSURU_CLIENT_KEY"); v != "" {
+		t.ClientKey = v
+	}
+	if v, err := strconv.ParseBool(os.Getenv("TSURU_INSECURE_SKIP_VERIFY")); err == nil {
+		t.InsecureSkipVerify = v
+	}
+	return t, nil
+}
+
+// CurrentTLSConfig returns the tls.Config for the current target, or nil
+// when it uses the system defaults.
+func CurrentTLSConfig() (*tls.Config, error) {
+	t, err := CurrentTLS()
+	if err != nil {
+		return nil, err
+	}
+	return t.Config()
+}
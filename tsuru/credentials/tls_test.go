@@ -0,0 +1,113 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package credentials
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	check "gopkg.in/check.v1"
+)
+
+// writeKeyPair writes a self-signed certificate and its key to dir.
+func writeKeyPair(c *check.C, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, check.IsNil)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "tsuru"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	c.Assert(err, check.IsNil)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	c.Assert(err, check.IsNil)
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	c.Assert(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600), check.IsNil)
+	c.Assert(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600), check.IsNil)
+	return certFile, keyFile
+}
+
+func (s *S) TestTLSConfig(c *check.C) {
+	certFile, keyFile := writeKeyPair(c, c.MkDir())
+	tlsConfig, err := TLS{}.Config()
+	c.Assert(err, check.IsNil)
+	c.Assert(tlsConfig, check.IsNil)
+	tlsConfig, err = TLS{CAFile: certFile, ClientCert: certFile, ClientKey: keyFile, InsecureSkipVerify: true}.Config()
+	c.Assert(err, check.IsNil)
+	c.Assert(tlsConfig.RootCAs, check.NotNil)
+	c.Assert(tlsConfig.Certificates, check.HasLen, 1)
+	c.Assert(tlsConfig.InsecureSkipVerify, check.Equals, true)
+}
+
+func (s *S) TestTLSValidate(c *check.C) {
+	dir := c.MkDir()
+	certFile, keyFile := writeKeyPair(c, dir)
+	c.Assert(TLS{CAFile: certFile, ClientCert: certFile, ClientKey: keyFile}.Validate(), check.IsNil)
+	c.Assert(TLS{ClientCert: certFile}.Validate(), check.ErrorMatches, "client certificate and key must be provided together")
+	c.Assert(TLS{CAFile: filepath.Join(dir, "missing.pem")}.Validate(), check.ErrorMatches, "unable to read CA file: .*")
+	c.Assert(TLS{CAFile: keyFile}.Validate(), check.ErrorMatches, `no PEM certificates found in CA file ".*key.pem"`)
+	c.Assert(TLS{ClientCert: keyFile, ClientKey: certFile}.Validate(), check.ErrorMatches, "unable to load client certificate: .*")
+}
+
+func (s *S) TestTLSEnv(c *check.C) {
+	c.Assert(TLS{}.Env(), check.IsNil)
+	c.Assert(TLS{CAFile: "/ca.pem", ClientCert: "/cert.pem", ClientKey: "/key.pem", InsecureSkipVerify: true}.Env(), check.DeepEquals, []string{
+		"TSURU_CA_FILE=/ca.pem",
+		"TSURU_CLIENT_CERT=/cert.pem",
+		"TSURU_CLIENT_KEY=/key.pem",
+		"TSURU_INSECURE_SKIP_VERIFY=true",
+	})
+}
+
+func (s *S) TestWriteReadTLS(c *check.C) {
+	settings := TLS{CAFile: "/ca.pem", InsecureSkipVerify: true}
+	c.Assert(WriteTLS("prod", settings), check.IsNil)
+	got, err := ReadTLS("prod")
+	c.Assert(err, check.IsNil)
+	c.Assert(got, check.Equals, settings)
+	got, err = ReadTLS("staging")
+	c.Assert(err, check.IsNil)
+	c.Assert(got.IsZero(), check.Equals, true)
+	c.Assert(WriteTLS("prod", TLS{}), check.IsNil)
+	got, err = ReadTLS("prod")
+	c.Assert(err, check.IsNil)
+	c.Assert(got.IsZero(), check.Equals, true)
+}
+
+func (s *S) TestCurrentTLS(c *check.C) {
+	c.Assert(WriteTLS("staging", TLS{CAFile: "/staging-ca.pem"}), check.IsNil)
+	c.Assert(WriteTLS("prod", TLS{CAFile: "/prod-ca.pem"}), check.IsNil)
+	got, err := CurrentTLS()
+	c.Assert(err, check.IsNil)
+	c.Assert(got, check.Equals, TLS{CAFile: "/staging-ca.pem"})
+	os.Setenv("TSURU_TARGET", "prod")
+	got, err = CurrentTLS()
+	c.Assert(err, check.IsNil)
+	c.Assert(got, check.Equals, TLS{CAFile: "/prod-ca.pem"})
+}
+
+func (s *S) TestCurrentTLSEnvOverrides(c *check.C) {
+	c.Assert(WriteTLS("staging", TLS{CAFile: "/staging-ca.pem", InsecureSkipVerify: true}), check.IsNil)
+	os.Setenv("TSURU_CA_FILE", "/env-ca.pem")
+	os.Setenv("TSURU_INSECURE_SKIP_VERIFY", "false")
+	defer os.Unsetenv("TSURU_CA_FILE")
+	defer os.Unsetenv("TSURU_INSECURE_SKIP_VERIFY")
+	got, err := CurrentTLS()
+	c.Assert(err, check.IsNil)
+	c.Assert(got, check.Equals, TLS{CAFile: "/env-ca.pem"})
+}
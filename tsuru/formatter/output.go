@@ -0,0 +1,197 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package formatter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Output formats accepted by the -o/--output flag.
+const (
+	OutputTable      = ""
+	OutputWide       = "wide"
+	OutputJSON       = "json"
+	OutputYAML       = "yaml"
+	OutputName       = "name"
+	OutputCSV        = "csv"
+	OutputJSONPath   = "jsonpath"
+	OutputGoTemplate = "go-template"
+)
+
+const outputUsage = "Output format: json, yaml, wide, name, csv, jsonpath=<template> or go-template=<template>"
+
+// MovedShorthandNote is appended to the usage of the flags that used to have
+// the -o shorthand, like --pool and --owner in list commands, which now
+// belongs to --output.
+const MovedShorthandNote = " (the -o shorthand now selects the output format)"
+
+// Output handles the -o/--output flag shared by list and info commands.
+type Output struct {
+	value string
+	json  bool
+}
+
+// Printable holds what a command is able to render in each output format.
+// Data is used by the json, yaml, jsonpath and go-template formats, Names by
+// the name format and Rows by the csv format.
+type Printable struct {
+	Data  interface{}
+	Names func() []string
+	Rows  func() (header []string, rows [][]string)
+}
+
+// AddFlags registers the -o/--output flag and the legacy --json flag. The -o
+// shorthand means --output in every command using it, so it must not be taken
+// by other flags of fs.
+func (o *Output) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&o.value, "output", "o", "", outputUsage)
+	fs.BoolVar(&o.json, "json", false, "Display in JSON format (same as --output json)")
+}
+
+// Format returns the name of the selected format, without its argument.
+func (o *Output) Format() string {
+	if o.json && o.value == "" {
+		return OutputJSON
+	}
+	format, _, _ := strings.Cut(o.value, "=")
+	return format
+}
+
+// IsTable reports whether the selected format is rendered by the command
+// itself as a table.
+func (o *Output) IsTable() bool {
+	format := o.Format()
+	return format == OutputTable || format == OutputWide
+}
+
+// Wide reports whether the table should include additional columns.
+func (o *Output) Wide() bool {
+	return o.Format() == OutputWide
+}
+
+// Print renders p using the selected format. Table formats are rendered by
+// the commands, so Print must only be called when IsTable returns false.
+func (o *Output) Print(w io.Writer, p Printable) error {
+	format, arg, _ := strings.Cut(o.value, "=")
+	if o.json && o.value == "" {
+		format = OutputJSON
+	}
+	switch format {
+	case OutputJSON:
+		if p.Data == nil {
+			break
+		}
+		return JSON(w, p.Data)
+	case OutputYAML:
+		if p.Data == nil {
+			break
+		}
+		data, err := yaml.Marshal(p.Data)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	case OutputName:
+		if p.Names == nil {
+			break
+		}
+		for _, name := range p.Names() {
+			fmt.Fprintln(w, name)
+		}
+		return nil
+	case OutputCSV:
+		if p.Rows == nil {
+			break
+		}
+		header, rows := p.Rows()
+		csvWriter := csv.NewWriter(w)
+		if err := csvWriter.Write(header); err != nil {
+			return err
+		}
+		if err := csvWriter.WriteAll(rows); err != nil {
+			return err
+		}
+		return nil
+	case OutputJSONPath:
+		if p.Data == nil {
+			break
+		}
+		return printJSONPath(w, arg, p.Data)
+	case OutputGoTemplate:
+		if p.Data == nil {
+			break
+		}
+		return printGoTemplate(w, arg, p.Data)
+	case OutputTable, OutputWide:
+		return errors.New("table output must be rendered by the command")
+	default:
+		return errors.Errorf("unknown output format %q, valid formats are: json, yaml, wide, name, csv, jsonpath=<template>, go-template=<template> (-o no longer filters by pool or owner, use --pool or --owner)", o.value)
+	}
+	return errors.Errorf("output format %q is not supported by this command", format)
+}
+
+// genericData converts data to the generic representation produced by
+// encoding/json, so templates see the same field names of the JSON output.
+func genericData(data interface{}) (interface{}, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	err = decoder.Decode(&result)
+	return result, err
+}
+
+func printJSONPath(w io.Writer, tmpl string, data interface{}) error {
+	if tmpl == "" {
+		return errors.New("jsonpath output requires a template, e.g. -o jsonpath='{.name}'")
+	}
+	jp := jsonpath.New("output").AllowMissingKeys(true)
+	if err := jp.Parse(tmpl); err != nil {
+		return errors.Wrap(err, "invalid jsonpath template")
+	}
+	generic, err := genericData(data)
+	if err != nil {
+		return err
+	}
+	if err = jp.Execute(w, generic); err != nil {
+		return err
+	}
+	fmt.Fprintln(w)
+	return nil
+}
+
+func printGoTemplate(w io.Writer, tmpl string, data interface{}) error {
+	if tmpl == "" {
+		return errors.New("go-template output requires a template, e.g. -o go-template='{{.name}}'")
+	}
+	t, err := template.New("output").Parse(tmpl)
+	if err != nil {
+		return errors.Wrap(err, "invalid go-template")
+	}
+	generic, err := genericData(data)
+	if err != nil {
+		return err
+	}
+	if err = t.Execute(w, generic); err != nil {
+		return err
+	}
+	fmt.Fprintln(w)
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package formatter
+
+import (
+	"bytes"
+
+	"github.com/spf13/pflag"
+	check "gopkg.in/check.v1"
+)
+
+type outputItem struct {
+	Name  string `json:"name"`
+	Units int    `json:"units"`
+}
+
+var outputItems = []outputItem{{Name: "app1", Units: 2}, {Name: "app2", Units: 1}}
+
+func outputPrintable() Printable {
+	return Printable{
+		Data: outputItems,
+		Names: func() []string {
+			return []string{"app1", "app2"}
+		},
+		Rows: func() ([]string, [][]string) {
+			return []string{"Name", "Units"}, [][]string{{"app1", "2"}, {"app2", "1"}}
+		},
+	}
+}
+
+func parseOutput(c *check.C, args ...string) *Output {
+	var o Output
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	o.AddFlags(fs)
+	c.Assert(fs.Parse(args), check.IsNil)
+	return &o
+}
+
+func (s *S) TestOutputDefaultIsTable(c *check.C) {
+	o := parseOutput(c)
+	c.Assert(o.IsTable(), check.Equals, true)
+	c.Assert(o.Wide(), check.Equals, false)
+	o = parseOutput(c, "-o", "wide")
+	c.Assert(o.IsTable(), check.Equals, true)
+	c.Assert(o.Wide(), check.Equals, true)
+}
+
+func (s *S) TestOutputLegacyJSONFlag(c *check.C) {
+	o := parseOutput(c, "--json")
+	c.Assert(o.Format(), check.Equals, OutputJSON)
+	var buf bytes.Buffer
+	c.Assert(o.Print(&buf, outputPrintable()), check.IsNil)
+	c.Assert(buf.String(), check.Equals, `[
+  {
+    "name": "app1",
+    "units": 2
+  },
+  {
+    "name": "app2",
+    "units": 1
+  }
+]
+`)
+}
+
+func (s *S) TestOutputShorthandAlreadyTaken(c *check.C) {
+	var o Output
+	var pool string
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.StringVarP(&pool, "pool", "o", "", "pool")
+	c.Assert(func() { o.AddFlags(fs) }, check.PanicMatches, `.*unable to redefine 'o' shorthand.*`)
+}
+
+func (s *S) TestOutputYAML(c *check.C) {
+	var buf bytes.Buffer
+	err := parseOutput(c, "-o", "yaml").Print(&buf, outputPrintable())
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, "- name: app1\n  units: 2\n- name: app2\n  units: 1\n")
+}
+
+func (s *S) TestOutputName(c *check.C) {
+	var buf bytes.Buffer
+	err := parseOutput(c, "-o", "name").Print(&buf, outputPrintable())
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, "app1\napp2\n")
+}
+
+func (s *S) TestOutputCSV(c *check.C) {
+	var buf bytes.Buffer
+	err := parseOutput(c, "-o", "csv").Print(&buf, outputPrintable())
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, "Name,Units\napp1,2\napp2,1\n")
+}
+
+func (s *S) TestOutputJSONPath(c *check.C) {
+	var buf bytes.Buffer
+	err := parseOutput(c, "-o", "jsonpath={[*].name}").Print(&buf, outputPrintable())
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, "app1 app2\n")
+}
+
+func (s *S) TestOutputJSONPathWithoutTemplate(c *check.C) {
+	var buf bytes.Buffer
+	err := parseOutput(c, "-o", "jsonpath").Print(&buf, outputPrintable())
+	c.Assert(err, check.ErrorMatches, "jsonpath output requires a template.*")
+}
+
+func (s *S) TestOutputGoTemplate(c *check.C) {
+	var buf bytes.Buffer
+	err := parseOutput(c, "-o", "go-template={{range .}}{{.name}}={{.units}};{{end}}").Print(&buf, outputPrintable())
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, "app1=2;app2=1;\n")
+}
+
+func (s *S) TestOutputNotSupportedByCommand(c *check.C) {
+	var buf bytes.Buffer
+	err := parseOutput(c, "-o", "csv").Print(&buf, Printable{Data: outputItems})
+	c.Assert(err, check.ErrorMatches, `output format "csv" is not supported by this command`)
+}
+
+func (s *S) TestOutputUnknownFormat(c *check.C) {
+	var buf bytes.Buffer
+	err := parseOutput(c, "-o", "xml").Print(&buf, outputPrintable())
+	c.Assert(err, check.ErrorMatches, `unknown output format "xml".*`)
+}
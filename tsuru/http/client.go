@@ -9,8 +9,12 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/pkg/errors"
+	goTsuruClient "github.com/tsuru/go-tsuruclient/pkg/client"
 	"github.com/tsuru/go-tsuruclient/pkg/config"
 	"github.com/tsuru/go-tsuruclient/pkg/tsuru"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
+	"golang.org/x/oauth2"
 )
 
 var (
@@ -21,14 +25,19 @@ var (
 var (
 	TerminalClientOnlyRequest = 1
 	TerminalClientVerbose     = 2
+	TerminalClientTimings     = 3
 )
 
 type TerminalClientOptions struct {
 	RoundTripper  http.RoundTripper
 	ClientName    string
 	ClientVersion string
+	Stdin         io.Reader
 	Stdout        io.Writer
 	Stderr        io.Writer
+	// ProtectedTarget is the label of the current target when it's
+	// protected, see TerminalRoundTripper.
+	ProtectedTarget string
 }
 
 func NewTerminalClient(opts TerminalClientOptions) *http.Client {
@@ -44,11 +53,13 @@ func NewTerminalClient(opts TerminalClientOptions) *http.Client {
 	}
 
 	transport := &TerminalRoundTripper{
-		RoundTripper:   opts.RoundTripper,
-		Stdout:         stdout,
-		Stderr:         stderr,
-		Progname:       opts.ClientName,
-		CurrentVersion: opts.ClientVersion,
+		RoundTripper:    opts.RoundTripper,
+		Stdin:           opts.Stdin,
+		Stdout:          stdout,
+		Stderr:          stderr,
+		Progname:        opts.ClientName,
+		CurrentVersion:  opts.ClientVersion,
+		ProtectedTarget: opts.ProtectedTarget,
 	}
 	return &http.Client{Transport: transport}
 }
@@ -69,6 +80,63 @@ func TsuruClientFromEnvironment() (*tsuru.APIClient, error) {
 	return cli, nil
 }
 
+// RoundTripperAndTokenProvider returns the round tripper authenticating
+// requests sent through base with the credentials of the current target, and
+// the provider of its token. The team token in TSURU_TOKEN takes precedence
+// over OIDC sessions.
+func RoundTripperAndTokenProvider(base http.RoundTripper) (http.RoundTripper, config.TokenProvider, error) {
+	tokenV2, err := credentials.CurrentTokenV2()
+	if err != nil {
+		return nil, nil, err
+	}
+	if tokenV2 != nil && tokenV2.Scheme == "oidc" && config.ReadTeamToken() == "" {
+		key, err := credentials.CurrentKey()
+		if err != nil {
+			return nil, nil, err
+		}
+		tokenSource := credentials.NewOIDCTokenSource(key, tokenV2)
+		roundTripper := &oauth2.Transport{
+			Base:   base,
+			Source: tokenSource,
+		}
+		return roundTripper, &goTsuruClient.OIDCTokenProvider{OAuthTokenSource: tokenSource}, nil
+	}
+	return &TokenV1RoundTripper{RoundTripper: base}, credentials.TokenProvider(), nil
+}
+
+// TargetTransport returns the transport used to reach the current target,
+// configured with the CA bundle, client certificate and verification mode
+// set by target-add or target-update.
+func TargetTransport() (http.RoundTripper, error) {
+	settings, err := credentials.CurrentTLS()
+	if err == nil {
+		var transport http.RoundTripper
+		if transport, err = TLSTransport(settings); err == nil {
+			return transport, nil
+		}
+	}
+	return nil, errors.Wrap(err, "invalid TLS settings for the current target")
+}
+
+// TLSTransport returns a transport using the given TLS settings, or the
+// default transport when none is set.
+func TLSTransport(settings credentials.TLS) (http.RoundTripper, error) {
+	tlsConfig, err := settings.Config()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		return defaultRoundTripper, nil
+	}
+	base, ok := defaultRoundTripper.(*http.Transport)
+	if !ok {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+	transport := base.Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
 type errWrapped interface {
 	Unwrap() error
 }
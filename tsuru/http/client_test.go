@@ -6,15 +6,20 @@ package http
 
 import (
 	"bytes"
+	"encoding/pem"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/tsuru/go-tsuruclient/pkg/config"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
 	"github.com/tsuru/tsuru-client/tsuru/cmd/cmdtest"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
 	tsuruerr "github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/fs/fstest"
 	check "gopkg.in/check.v1"
@@ -30,11 +35,13 @@ func (s *S) SetUpTest(c *check.C) {
 	if env := os.Getenv("TERM"); env == "" {
 		os.Setenv("TERM", "tsuruterm")
 	}
+	retryBaseDelay = time.Millisecond
 }
 
 func (s *S) TearDownTest(c *check.C) {
 	os.Unsetenv("TSURU_TARGET")
 	os.Unsetenv("TSURU_TOKEN")
+	retryBaseDelay = 500 * time.Millisecond
 }
 
 var _ = check.Suite(&S{})
@@ -390,3 +397,74 @@ func (s *S) TestUnwrapErrWithChainedErrors(c *check.C) {
 	// Should unwrap all the way to the base error
 	c.Assert(result, check.Equals, baseErr)
 }
+
+func (s *S) TestRoundTripperAndTokenProviderUsesCurrentTargetToken(c *check.C) {
+	os.Unsetenv("TSURU_TOKEN")
+	rfs := &fstest.RecordingFs{}
+	config.SetFileSystem(rfs)
+	defer config.ResetFileSystem()
+	f, _ := rfs.Create(config.JoinWithUserDir(".tsuru", "targets"))
+	f.Write([]byte("staging\thttp://staging.tsuru.io\nprod\thttp://prod.tsuru.io\n"))
+	f.Close()
+	c.Assert(credentials.WriteTokenV1("staging", "staging-token"), check.IsNil)
+	c.Assert(credentials.WriteTokenV1("prod", "prod-token"), check.IsNil)
+	var authorization string
+	defaultRoundTripper = &cmdtest.ConditionalTransport{
+		Transport: cmdtest.Transport{Status: http.StatusOK},
+		CondFunc: func(req *http.Request) bool {
+			authorization = req.Header.Get("Authorization")
+			return true
+		},
+	}
+	defer func() { defaultRoundTripper = http.DefaultTransport }()
+	for target, expected := range map[string]string{"staging": "bearer staging-token", "prod": "bearer prod-token"} {
+		os.Setenv("TSURU_TARGET", target)
+		roundTripper, provider, err := RoundTripperAndTokenProvider(defaultRoundTripper)
+		c.Assert(err, check.IsNil)
+		request, _ := http.NewRequest("GET", "http://"+target+".tsuru.io/1.0/apps", nil)
+		_, err = roundTripper.RoundTrip(request)
+		c.Assert(err, check.IsNil)
+		c.Assert(authorization, check.Equals, expected)
+		token, err := provider.Token()
+		c.Assert(err, check.IsNil)
+		c.Assert("bearer "+token, check.Equals, expected)
+	}
+}
+
+func (s *S) TestTargetTransportWithCAFile(c *check.C) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	caFile := filepath.Join(c.MkDir(), "ca.pem")
+	err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600)
+	c.Assert(err, check.IsNil)
+	rfs := &fstest.RecordingFs{}
+	config.SetFileSystem(rfs)
+	defer config.ResetFileSystem()
+	f, _ := rfs.Create(config.JoinWithUserDir(".tsuru", "targets"))
+	f.Write([]byte("private\t" + server.URL + "\n"))
+	f.Close()
+	os.Setenv("TSURU_TARGET", "private")
+
+	transport, err := TargetTransport()
+	c.Assert(err, check.IsNil)
+	request, _ := http.NewRequest("GET", server.URL, nil)
+	_, err = transport.RoundTrip(request)
+	c.Assert(err, check.NotNil)
+
+	c.Assert(credentials.WriteTLS("private", credentials.TLS{CAFile: caFile}), check.IsNil)
+	transport, err = TargetTransport()
+	c.Assert(err, check.IsNil)
+	request, _ = http.NewRequest("GET", server.URL, nil)
+	response, err := transport.RoundTrip(request)
+	c.Assert(err, check.IsNil)
+	c.Assert(response.StatusCode, check.Equals, http.StatusOK)
+}
+
+func (s *S) TestTargetTransportInvalidSettings(c *check.C) {
+	os.Setenv("TSURU_CA_FILE", filepath.Join(c.MkDir(), "missing.pem"))
+	defer os.Unsetenv("TSURU_CA_FILE")
+	_, err := TargetTransport()
+	c.Assert(err, check.ErrorMatches, "invalid TLS settings for the current target: unable to read CA file: .*")
+}
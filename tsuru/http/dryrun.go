@@ -0,0 +1,137 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrDryRun is returned by the TerminalRoundTripper instead of sending
+// mutating requests when the dry-run mode is enabled.
+var ErrDryRun = errors.New("dry-run mode: request not sent")
+
+// IsDryRun reports whether the dry-run mode is enabled, either by the global
+// --dry-run flag or by the TSURU_DRY_RUN environment variable.
+func IsDryRun() bool {
+	v, _ := strconv.ParseBool(os.Getenv("TSURU_DRY_RUN"))
+	return v
+}
+
+// DryRunRoundTripper prints mutating requests instead of sending them when
+// the dry-run mode is enabled, as the TerminalRoundTripper does. It's used by
+// the UnauthenticatedClient, which sends the requests of login flows.
+type DryRunRoundTripper struct {
+	http.RoundTripper
+	Stdout io.Writer
+}
+
+func (t *DryRunRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := checkDryRun(t.Stdout, req); err != nil {
+		return nil, err
+	}
+	roundTripper := t.RoundTripper
+	if roundTripper == nil {
+		roundTripper = defaultRoundTripper
+	}
+	return roundTripper.RoundTrip(req)
+}
+
+// checkDryRun prints req and returns ErrDryRun when it's a mutating request
+// and the dry-run mode is enabled.
+func checkDryRun(w io.Writer, req *http.Request) error {
+	if !IsDryRun() || isSafeMethod(req.Method) {
+		return nil
+	}
+	if err := printDryRunRequest(w, req); err != nil {
+		return err
+	}
+	return ErrDryRun
+}
+
+func isSafeMethod(method string) bool {
+	return method == "" || method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// printDryRunRequest prints req with its body, masking secrets. Bodies which
+// are neither a form nor JSON are never printed, as secrets in them can't be
+// told apart, only their size is.
+func printDryRunRequest(w io.Writer, req *http.Request) error {
+	if w == nil {
+		w = io.Discard
+	}
+	fmt.Fprintf(w, "[dry-run] %s %s\n", req.Method, req.URL.String())
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	isText := mediaType == "" || strings.HasPrefix(mediaType, "text/")
+	if mediaType == "application/x-www-form-urlencoded" || (isText && isFormBody(body)) {
+		values, err := url.ParseQuery(string(body))
+		if err == nil {
+			printFormValues(w, redactFormValues(values))
+			return nil
+		}
+	}
+	if strings.Contains(mediaType, "json") || (isText && json.Valid(body)) {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, redactJSON(body), "  ", "  "); err == nil {
+			fmt.Fprintf(w, "  %s\n", buf.String())
+			return nil
+		}
+	}
+	if mediaType == "" {
+		fmt.Fprintf(w, "  <%d bytes>\n", len(body))
+		return nil
+	}
+	fmt.Fprintf(w, "  <%d bytes of %s>\n", len(body), mediaType)
+	return nil
+}
+
+func printFormValues(w io.Writer, values url.Values) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range values[k] {
+			fmt.Fprintf(w, "  %s=%s\n", k, v)
+		}
+	}
+}
+
+// isFormBody tells whether a body sent without a form content type is
+// still an encoded form, as in "name=value&other=value".
+func isFormBody(body []byte) bool {
+	if bytes.ContainsAny(body, " \t\r\n") {
+		return false
+	}
+	for _, pair := range bytes.Split(body, []byte("&")) {
+		if !bytes.Contains(pair, []byte("=")) {
+			return false
+		}
+	}
+	return true
+}
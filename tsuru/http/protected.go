@@ -0,0 +1,64 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+)
+
+// ErrProtectedTarget is returned by the TerminalRoundTripper instead of
+// sending mutating requests to a protected target when the change is not
+// confirmed.
+var ErrProtectedTarget = errors.New("change to a protected target not confirmed")
+
+// IsProtectedConfirmed reports whether changes to a protected target are
+// confirmed beforehand, either by the global --confirm-protected flag or by
+// the TSURU_CONFIRM_PROTECTED environment variable. The -y/--assume-yes flag
+// of commands doesn't confirm them, so a command sent to the wrong target is
+// still stopped.
+func IsProtectedConfirmed() bool {
+	v, _ := strconv.ParseBool(os.Getenv("TSURU_CONFIRM_PROTECTED"))
+	return v
+}
+
+// confirmProtected asks for confirmation before the first mutating request is
+// sent to the protected target. The answer holds for the following requests,
+// as a single command may send many of them.
+//
+// Stdin is never read when it's not a terminal, as it may hold the input of
+// the command itself, the change is refused with an error telling how to
+// confirm it instead.
+func (v *TerminalRoundTripper) confirmProtected(req *http.Request) error {
+	if IsProtectedConfirmed() {
+		return nil
+	}
+	v.confirmOnce.Do(func() {
+		if f, ok := v.Stdin.(*os.File); ok && !term.IsTerminal(int(f.Fd())) {
+			v.confirmErr = errors.Wrapf(ErrProtectedTarget, "target %q is protected and stdin is not a terminal, use --confirm-protected or set TSURU_CONFIRM_PROTECTED=true to confirm the change", v.ProtectedTarget)
+			return
+		}
+		stderr := v.Stderr
+		if stderr == nil {
+			stderr = io.Discard
+		}
+		fmt.Fprintf(stderr, "Target %q is protected, you're about to send %s %s.\nContinue? (y/n) ", v.ProtectedTarget, req.Method, req.URL.Path)
+		var answer string
+		if v.Stdin != nil {
+			fmt.Fscanln(v.Stdin, &answer)
+		}
+		if answer != "y" {
+			fmt.Fprintln(stderr, "Abort.")
+			v.confirmErr = ErrProtectedTarget
+		}
+	})
+	return v.confirmErr
+}
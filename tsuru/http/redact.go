@@ -0,0 +1,143 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// redactedValue replaces secrets in verbose and dry-run output.
+const redactedValue = "***"
+
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// secretFields are the normalized names of form and JSON fields holding
+// secrets, see isSecretField.
+var secretFields = map[string]bool{
+	"token":        true,
+	"accesstoken":  true,
+	"refreshtoken": true,
+	"idtoken":      true,
+	"password":     true,
+	"newpassword":  true,
+	"oldpassword":  true,
+	"secret":       true,
+	"clientsecret": true,
+	"apikey":       true,
+	"privatekey":   true,
+}
+
+var (
+	jsonStringFieldRegexp = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"(\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	envFormFieldRegexp    = regexp.MustCompile(`(?i)^envs\.(\d+)\.(name|value|private)$`)
+)
+
+func isSecretField(name string) bool {
+	name = strings.ToLower(name)
+	name = strings.NewReplacer("_", "", "-", "").Replace(name)
+	return secretFields[name]
+}
+
+// redactDump masks secrets in a request or response dump produced by
+// httputil: authentication headers and cookies, secret fields of form and
+// JSON bodies and the values of private environment variables.
+func redactDump(dump []byte, header http.Header) []byte {
+	head, body, hasBody := bytes.Cut(dump, []byte("\r\n\r\n"))
+	lines := bytes.Split(head, []byte("\r\n"))
+	for i, line := range lines {
+		name, value, ok := bytes.Cut(line, []byte(":"))
+		if !ok || !redactedHeaders[http.CanonicalHeaderKey(string(name))] {
+			continue
+		}
+		lines[i] = []byte(string(name) + ": " + redactHeaderValue(strings.TrimSpace(string(value))))
+	}
+	result := bytes.Join(lines, []byte("\r\n"))
+	if !hasBody {
+		return result
+	}
+	result = append(result, "\r\n\r\n"...)
+	return append(result, redactBody(body, header.Get("Content-Type"))...)
+}
+
+// redactHeaderValue keeps the authentication scheme, if any, so the output
+// still shows which kind of credential was sent.
+func redactHeaderValue(value string) string {
+	if scheme, _, ok := strings.Cut(value, " "); ok && !strings.ContainsAny(scheme, "=;") {
+		return scheme + " " + redactedValue
+	}
+	return redactedValue
+}
+
+func redactBody(body []byte, contentType string) []byte {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(bytes.TrimSpace(body)))
+		if err != nil {
+			return body
+		}
+		redacted := redactFormValues(values)
+		if reflect.DeepEqual(values, redacted) {
+			return body
+		}
+		return []byte(redacted.Encode())
+	case strings.Contains(mediaType, "json"):
+		return redactJSON(body)
+	}
+	return body
+}
+
+// redactFormValues returns a copy of values with secret fields and the values
+// of private environment variables masked.
+func redactFormValues(values url.Values) url.Values {
+	result := url.Values{}
+	privateEnvs, _ := strconv.ParseBool(values.Get("Private"))
+	privateIndexes := map[string]bool{}
+	for k, v := range values {
+		if m := envFormFieldRegexp.FindStringSubmatch(k); m != nil && strings.EqualFold(m[2], "private") {
+			privateIndexes[m[1]], _ = strconv.ParseBool(v[0])
+		}
+	}
+	for k, v := range values {
+		redact := isSecretField(k)
+		if m := envFormFieldRegexp.FindStringSubmatch(k); m != nil && strings.EqualFold(m[2], "value") {
+			redact = privateEnvs || privateIndexes[m[1]]
+		}
+		if !redact {
+			result[k] = append([]string(nil), v...)
+			continue
+		}
+		masked := make([]string, len(v))
+		for i := range masked {
+			masked[i] = redactedValue
+		}
+		result[k] = masked
+	}
+	return result
+}
+
+// redactJSON masks string values of secret fields. It works on the raw text,
+// so it also handles streams of JSON messages and chunked bodies.
+func redactJSON(body []byte) []byte {
+	return jsonStringFieldRegexp.ReplaceAllFunc(body, func(match []byte) []byte {
+		m := jsonStringFieldRegexp.FindSubmatch(match)
+		if !isSecretField(string(m[1])) {
+			return match
+		}
+		return []byte(`"` + string(m[1]) + `"` + string(m[2]) + `"` + redactedValue + `"`)
+	})
+}
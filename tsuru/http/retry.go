@@ -0,0 +1,166 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	retryableHeader  = "X-Tsuru-Retryable"
+	retryAfterHeader = "Retry-After"
+
+	defaultMaxRetries = 2
+)
+
+var (
+	// retryBaseDelay is the delay before the first retry, doubled on each
+	// subsequent attempt up to retryMaxDelay.
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 10 * time.Second
+	// retryAfterMaxDelay caps the delay requested by the server through the
+	// Retry-After header.
+	retryAfterMaxDelay = time.Minute
+)
+
+// getMaxRetries returns how many times a failed request may be retried, read
+// from the TSURU_HTTP_RETRIES environment variable.
+func getMaxRetries() int {
+	v, err := strconv.Atoi(os.Getenv("TSURU_HTTP_RETRIES"))
+	if err != nil || v < 0 {
+		return defaultMaxRetries
+	}
+	return v
+}
+
+func isIdempotentMethod(method string) bool {
+	return method == "" || method == http.MethodGet || method == http.MethodHead
+}
+
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isNotProcessedStatus tells whether a response with a Retry-After header
+// means the server refused the request without running it.
+func isNotProcessedStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable
+}
+
+// retryDelay reports whether the request should be sent again after
+// receiving response or err, and how long to wait before that. GET and HEAD
+// requests are retried on connection errors and on 429, 502, 503 and 504
+// responses. Other methods may have been run already, so they are only
+// retried when the server marks the response as retryable, either with the
+// X-Tsuru-Retryable header or with a Retry-After header on a 429 or 503
+// response. A Retry-After added by a proxy to a 502 or 504 response doesn't
+// tell whether the request reached the server.
+func retryDelay(req *http.Request, response *http.Response, err error, attempt int) (time.Duration, bool) {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return 0, false
+	}
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrDryRun) {
+			return 0, false
+		}
+		return backoff(attempt), isIdempotentMethod(req.Method)
+	}
+	if response == nil || !isRetryableStatus(response.StatusCode) {
+		return 0, false
+	}
+	retryAfter, hasRetryAfter := parseRetryAfter(response.Header.Get(retryAfterHeader))
+	markedRetryable, _ := strconv.ParseBool(response.Header.Get(retryableHeader))
+	if !isIdempotentMethod(req.Method) && !markedRetryable && !(hasRetryAfter && isNotProcessedStatus(response.StatusCode)) {
+		return 0, false
+	}
+	if hasRetryAfter {
+		return retryAfter, true
+	}
+	return backoff(attempt), true
+}
+
+// backoff returns the exponential delay for the given attempt, with jitter
+// between half and the whole delay.
+func backoff(attempt int) time.Duration {
+	delay := retryBaseDelay
+	for i := 0; i < attempt && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	half := int64(delay / 2)
+	if half <= 0 {
+		return delay
+	}
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// parseRetryAfter parses the Retry-After header, either in seconds or as an
+// HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		delay = time.Until(date)
+	} else {
+		return 0, false
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	if delay > retryAfterMaxDelay {
+		delay = retryAfterMaxDelay
+	}
+	return delay, true
+}
+
+// prepareRetry discards the previous response and rewinds the request body.
+func prepareRetry(req *http.Request, response *http.Response) error {
+	if response != nil && response.Body != nil {
+		io.Copy(io.Discard, response.Body)
+		response.Body.Close()
+	}
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
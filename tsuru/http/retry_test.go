@@ -0,0 +1,181 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tsuru/tsuru-client/tsuru/cmd/cmdtest"
+	tsuruerr "github.com/tsuru/tsuru/errors"
+	check "gopkg.in/check.v1"
+)
+
+type countingTransport struct {
+	responses []cmdtest.Transport
+	err       error
+	bodies    []string
+	calls     int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls++
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		t.bodies = append(t.bodies, string(body))
+	}
+	if t.err != nil {
+		return nil, t.err
+	}
+	next := t.responses[0]
+	if len(t.responses) > 1 {
+		t.responses = t.responses[1:]
+	}
+	return next.RoundTrip(req)
+}
+
+func (s *S) TestRoundTripRetriesIdempotentRequest(c *check.C) {
+	transport := &countingTransport{responses: []cmdtest.Transport{
+		{Status: http.StatusBadGateway},
+		{Status: http.StatusServiceUnavailable},
+		{Message: "ok", Status: http.StatusOK},
+	}}
+	r := TerminalRoundTripper{RoundTripper: transport}
+	req, err := http.NewRequest(http.MethodGet, "http://localhost/apps/myapp", nil)
+	c.Assert(err, check.IsNil)
+	resp, err := r.RoundTrip(req)
+	c.Assert(err, check.IsNil)
+	c.Assert(resp.StatusCode, check.Equals, http.StatusOK)
+	c.Assert(transport.calls, check.Equals, 3)
+}
+
+func (s *S) TestRoundTripRetriesConnectionErrors(c *check.C) {
+	transport := &countingTransport{err: errors.New("connection reset by peer")}
+	r := TerminalRoundTripper{RoundTripper: transport}
+	req, err := http.NewRequest(http.MethodGet, "http://localhost/apps/myapp", nil)
+	c.Assert(err, check.IsNil)
+	_, err = r.RoundTrip(req)
+	c.Assert(err, check.ErrorMatches, ".*connection reset by peer")
+	c.Assert(transport.calls, check.Equals, 3)
+}
+
+func (s *S) TestRoundTripRetriesLimitFromEnvironment(c *check.C) {
+	os.Setenv("TSURU_HTTP_RETRIES", "0")
+	defer os.Unsetenv("TSURU_HTTP_RETRIES")
+	transport := &countingTransport{responses: []cmdtest.Transport{{Status: http.StatusBadGateway}}}
+	r := TerminalRoundTripper{RoundTripper: transport}
+	req, err := http.NewRequest(http.MethodGet, "http://localhost/apps", nil)
+	c.Assert(err, check.IsNil)
+	_, err = r.RoundTrip(req)
+	c.Assert(err, check.FitsTypeOf, &tsuruerr.HTTP{})
+	c.Assert(transport.calls, check.Equals, 1)
+}
+
+func (s *S) TestRoundTripDoesNotRetryMutatingRequest(c *check.C) {
+	transport := &countingTransport{responses: []cmdtest.Transport{{Status: http.StatusBadGateway}}}
+	r := TerminalRoundTripper{RoundTripper: transport}
+	req, err := http.NewRequest(http.MethodPost, "http://localhost/apps", strings.NewReader("name=myapp"))
+	c.Assert(err, check.IsNil)
+	_, err = r.RoundTrip(req)
+	c.Assert(err, check.NotNil)
+	c.Assert(transport.calls, check.Equals, 1)
+
+	transport = &countingTransport{err: errors.New("connection reset by peer")}
+	r = TerminalRoundTripper{RoundTripper: transport}
+	req, err = http.NewRequest(http.MethodPost, "http://localhost/apps", strings.NewReader("name=myapp"))
+	c.Assert(err, check.IsNil)
+	_, err = r.RoundTrip(req)
+	c.Assert(err, check.NotNil)
+	c.Assert(transport.calls, check.Equals, 1)
+}
+
+func (s *S) TestRoundTripRetriesMutatingRequestMarkedRetryable(c *check.C) {
+	os.Setenv("TSURU_VERBOSITY", "1")
+	defer os.Unsetenv("TSURU_VERBOSITY")
+	transport := &countingTransport{responses: []cmdtest.Transport{
+		{Status: http.StatusServiceUnavailable, Headers: map[string][]string{"X-Tsuru-Retryable": {"true"}}},
+		{Status: http.StatusServiceUnavailable, Headers: map[string][]string{"Retry-After": {"0"}}},
+		{Message: "created", Status: http.StatusCreated},
+	}}
+	var stdout, stderr bytes.Buffer
+	r := TerminalRoundTripper{RoundTripper: transport, Stdout: &stdout, Stderr: &stderr}
+	req, err := http.NewRequest(http.MethodPost, "http://localhost/apps", strings.NewReader("name=myapp"))
+	c.Assert(err, check.IsNil)
+	resp, err := r.RoundTrip(req)
+	c.Assert(err, check.IsNil)
+	c.Assert(resp.StatusCode, check.Equals, http.StatusCreated)
+	c.Assert(transport.bodies, check.DeepEquals, []string{"name=myapp", "name=myapp", "name=myapp"})
+	c.Assert(stderr.String(), check.Matches, `(?s)Request POST /apps failed \(503 Service Unavailable\), retrying in .* \(1/2\)\n.*\(2/2\)\n`)
+}
+
+func (s *S) TestRoundTripDoesNotRetryMutatingRequestOnGatewayRetryAfter(c *check.C) {
+	for _, status := range []int{http.StatusBadGateway, http.StatusGatewayTimeout} {
+		transport := &countingTransport{responses: []cmdtest.Transport{
+			{Status: status, Headers: map[string][]string{"Retry-After": {"0"}}},
+			{Message: "created", Status: http.StatusCreated},
+		}}
+		r := TerminalRoundTripper{RoundTripper: transport}
+		req, err := http.NewRequest(http.MethodPost, "http://localhost/apps", strings.NewReader("name=myapp"))
+		c.Assert(err, check.IsNil)
+		_, err = r.RoundTrip(req)
+		c.Assert(err, check.NotNil)
+		c.Assert(transport.calls, check.Equals, 1)
+	}
+}
+
+func (s *S) TestRoundTripRetriesMutatingRequestOnTooManyRequests(c *check.C) {
+	transport := &countingTransport{responses: []cmdtest.Transport{
+		{Status: http.StatusTooManyRequests, Headers: map[string][]string{"Retry-After": {"0"}}},
+		{Message: "created", Status: http.StatusCreated},
+	}}
+	r := TerminalRoundTripper{RoundTripper: transport}
+	req, err := http.NewRequest(http.MethodPost, "http://localhost/apps", strings.NewReader("name=myapp"))
+	c.Assert(err, check.IsNil)
+	resp, err := r.RoundTrip(req)
+	c.Assert(err, check.IsNil)
+	c.Assert(resp.StatusCode, check.Equals, http.StatusCreated)
+	c.Assert(transport.calls, check.Equals, 2)
+}
+
+func (s *S) TestRoundTripDoesNotRetryUnreplayableBody(c *check.C) {
+	transport := &countingTransport{responses: []cmdtest.Transport{
+		{Status: http.StatusServiceUnavailable, Headers: map[string][]string{"X-Tsuru-Retryable": {"true"}}},
+	}}
+	r := TerminalRoundTripper{RoundTripper: transport}
+	req, err := http.NewRequest(http.MethodPost, "http://localhost/apps", io.NopCloser(strings.NewReader("name=myapp")))
+	c.Assert(err, check.IsNil)
+	_, err = r.RoundTrip(req)
+	c.Assert(err, check.NotNil)
+	c.Assert(transport.calls, check.Equals, 1)
+}
+
+func (s *S) TestParseRetryAfter(c *check.C) {
+	d, ok := parseRetryAfter("3")
+	c.Assert(ok, check.Equals, true)
+	c.Assert(d, check.Equals, 3*time.Second)
+	d, ok = parseRetryAfter("3600")
+	c.Assert(ok, check.Equals, true)
+	c.Assert(d, check.Equals, retryAfterMaxDelay)
+	d, ok = parseRetryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+	c.Assert(ok, check.Equals, true)
+	c.Assert(d, check.Equals, time.Duration(0))
+	_, ok = parseRetryAfter("")
+	c.Assert(ok, check.Equals, false)
+	_, ok = parseRetryAfter("soon")
+	c.Assert(ok, check.Equals, false)
+}
+
+func (s *S) TestBackoff(c *check.C) {
+	retryBaseDelay = time.Second
+	for attempt, max := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		d := backoff(attempt)
+		c.Assert(d >= max/2 && d <= max, check.Equals, true, check.Commentf("attempt %d: %s", attempt, d))
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// requestIDHeaders are the response headers identifying a request, printed
+// along with the timings.
+var requestIDHeaders = []string{"X-Tsuru-Eventid", "X-Request-Id", "X-Amzn-Trace-Id"}
+
+// requestTimer collects the timings of a single request through httptrace.
+type requestTimer struct {
+	mu         sync.Mutex
+	start      time.Time
+	dnsStart   time.Time
+	dns        time.Duration
+	connStart  time.Time
+	connect    time.Duration
+	tlsStart   time.Time
+	tls        time.Duration
+	reused     bool
+	firstByte  time.Duration
+	wroteStart time.Time
+}
+
+func newRequestTimer() *requestTimer {
+	return &requestTimer{start: time.Now()}
+}
+
+func (t *requestTimer) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.dns = time.Since(t.dnsStart)
+		},
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.connStart = time.Now()
+		},
+		ConnectDone: func(string, string, error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.connect = time.Since(t.connStart)
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.tls = time.Since(t.tlsStart)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.reused = info.Reused
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.wroteStart = time.Now()
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if !t.wroteStart.IsZero() {
+				t.firstByte = time.Since(t.wroteStart)
+			} else {
+				t.firstByte = time.Since(t.start)
+			}
+		},
+	}
+}
+
+func (t *requestTimer) print(w io.Writer, req *http.Request, response *http.Response) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	total := time.Since(t.start)
+	uri := req.URL.RequestURI()
+	fmt.Fprintf(w, "*************************** <Timings uri=%q> **********************************\n", uri)
+	fmt.Fprintf(w, "DNS lookup:         %s\n", formatTiming(t.dns))
+	connect := formatTiming(t.connect)
+	if t.reused {
+		connect = "reused connection"
+	}
+	fmt.Fprintf(w, "TCP connect:        %s\n", connect)
+	fmt.Fprintf(w, "TLS handshake:      %s\n", formatTiming(t.tls))
+	fmt.Fprintf(w, "Time to first byte: %s\n", formatTiming(t.firstByte))
+	fmt.Fprintf(w, "Total:              %s\n", formatTiming(total))
+	if response != nil {
+		for _, h := range requestIDHeaders {
+			if value := response.Header.Get(h); value != "" {
+				fmt.Fprintf(w, "%s: %s\n", h, value)
+			}
+		}
+	}
+	fmt.Fprintf(w, "*************************** </Timings uri=%q> **********************************\n", uri)
+}
+
+func formatTiming(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return d.Round(time.Microsecond).String()
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestRoundTripPrintsTimings(c *check.C) {
+	os.Setenv("TSURU_VERBOSITY", "3")
+	defer os.Unsetenv("TSURU_VERBOSITY")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Tsuru-Eventid", "abc123")
+		w.Header().Set("X-Request-Id", "req-1")
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	var out bytes.Buffer
+	r := TerminalRoundTripper{Stdout: &out, CurrentVersion: "1.0.0"}
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/apps/myapp", nil)
+	c.Assert(err, check.IsNil)
+	resp, err := r.RoundTrip(req)
+	c.Assert(err, check.IsNil)
+	c.Assert(resp.StatusCode, check.Equals, http.StatusOK)
+	c.Assert(out.String(), check.Matches, `(?s)`+
+		`.*<Request uri="/apps/myapp">.*`+
+		`<Timings uri="/apps/myapp">[^\n]*\n`+
+		`DNS lookup: +-\n`+
+		`TCP connect: +[0-9.]+[µm]?s\n`+
+		`TLS handshake: +-\n`+
+		`Time to first byte: +[0-9.]+[µm]?s\n`+
+		`Total: +[0-9.]+[µm]?s\n`+
+		`X-Tsuru-Eventid: abc123\n`+
+		`X-Request-Id: req-1\n`+
+		`[^\n]*</Timings uri="/apps/myapp">.*`+
+		`<Response uri="/apps/myapp">.*`)
+}
+
+func (s *S) TestRoundTripDoesNotPrintTimingsOnLowerVerbosity(c *check.C) {
+	os.Setenv("TSURU_VERBOSITY", "2")
+	defer os.Unsetenv("TSURU_VERBOSITY")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	var out bytes.Buffer
+	r := TerminalRoundTripper{Stdout: &out, CurrentVersion: "1.0.0"}
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/apps/myapp", nil)
+	c.Assert(err, check.IsNil)
+	_, err = r.RoundTrip(req)
+	c.Assert(err, check.IsNil)
+	c.Assert(out.String(), check.Not(check.Matches), `(?s).*Timings.*`)
+}
@@ -9,13 +9,17 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptrace"
 	"net/http/httputil"
 	"os"
 	"strconv"
+	"sync"
+	"time"
 
 	goVersion "github.com/hashicorp/go-version"
 	"github.com/pkg/errors"
 	"github.com/tsuru/go-tsuruclient/pkg/config"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
 	tsuruerr "github.com/tsuru/tsuru/errors"
 	"golang.org/x/oauth2"
 )
@@ -50,12 +54,26 @@ var errUnauthorized = &tsuruerr.HTTP{Code: http.StatusUnauthorized, Message: "un
 // based on the Verbosity.
 // Verbosity >= 1 --> Dumps request
 // Verbosity >= 2 --> Dumps response
+// Verbosity >= 3 --> Prints timings and request IDs
+//
+// Credentials and other secrets are masked in the dumps.
+//
+// Failed requests are retried with exponential backoff, up to the number of
+// times set in the TSURU_HTTP_RETRIES environment variable (2 by default).
 type TerminalRoundTripper struct {
 	http.RoundTripper
+	Stdin          io.Reader
 	Stdout         io.Writer
 	Stderr         io.Writer
 	CurrentVersion string
 	Progname       string
+
+	// ProtectedTarget is the label of the target requests are sent to when
+	// it's protected. The first mutating request sent to it must be
+	// confirmed by answering "y" in Stdin, unless IsProtectedConfirmed.
+	ProtectedTarget string
+	confirmOnce     sync.Once
+	confirmErr      error
 }
 
 func getVerbosity() int {
@@ -73,12 +91,25 @@ func (v *TerminalRoundTripper) RoundTrip(req *http.Request) (*http.Response, err
 	req.Header.Set("User-Agent", fmt.Sprintf("tsuru-client/%s", v.CurrentVersion))
 	req.Close = true
 
+	if err := checkDryRun(v.Stdout, req); err != nil {
+		return nil, err
+	}
+
+	if v.ProtectedTarget != "" && !isSafeMethod(req.Method) {
+		if err := v.confirmProtected(req); err != nil {
+			return nil, err
+		}
+	}
+
 	if verbosity >= TerminalClientOnlyRequest {
 		fmt.Fprintf(v.Stdout, "*************************** <Request uri=%q> **********************************\n", req.URL.RequestURI())
-		requestDump, err := httputil.DumpRequest(req, true)
+		// Streamed bodies, as the archives of deploys, are not dumped since
+		// they would have to be read entirely in memory.
+		requestDump, err := httputil.DumpRequest(req, req.ContentLength >= 0)
 		if err != nil {
 			return nil, err
 		}
+		requestDump = redactDump(requestDump, req.Header)
 		fmt.Fprint(v.Stdout, string(requestDump))
 		if requestDump[len(requestDump)-1] != '\n' {
 			fmt.Fprintln(v.Stdout)
@@ -86,13 +117,47 @@ func (v *TerminalRoundTripper) RoundTrip(req *http.Request) (*http.Response, err
 		fmt.Fprintf(v.Stdout, "*************************** </Request uri=%q> **********************************\n", req.URL.RequestURI())
 	}
 
-	response, err := roundTripper.RoundTrip(req)
+	var response *http.Response
+	var err error
+	maxRetries := getMaxRetries()
+	for attempt := 0; ; attempt++ {
+		if verbosity >= TerminalClientTimings {
+			timer := newRequestTimer()
+			response, err = roundTripper.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), timer.trace())))
+			timer.print(v.Stdout, req, response)
+		} else {
+			response, err = roundTripper.RoundTrip(req)
+		}
+		if attempt >= maxRetries {
+			break
+		}
+		delay, retry := retryDelay(req, response, err, attempt)
+		if !retry {
+			break
+		}
+		if verbosity >= TerminalClientOnlyRequest && v.Stderr != nil {
+			reason := ""
+			if err != nil {
+				reason = err.Error()
+			} else {
+				reason = response.Status
+			}
+			fmt.Fprintf(v.Stderr, "Request %s %s failed (%s), retrying in %s (%d/%d)\n", req.Method, req.URL.RequestURI(), reason, delay.Round(time.Millisecond), attempt+1, maxRetries)
+		}
+		if err = prepareRetry(req, response); err != nil {
+			return nil, err
+		}
+		if err = sleepContext(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
 	if verbosity >= TerminalClientVerbose && response != nil {
 		fmt.Fprintf(v.Stdout, "*************************** <Response uri=%q> **********************************\n", req.URL.RequestURI())
 		responseDump, errDump := httputil.DumpResponse(response, true)
 		if errDump != nil {
 			return nil, errDump
 		}
+		responseDump = redactDump(responseDump, response.Header)
 		fmt.Fprint(v.Stdout, string(responseDump))
 		if responseDump[len(responseDump)-1] != '\n' {
 			fmt.Fprintln(v.Stdout)
@@ -105,7 +170,7 @@ func (v *TerminalRoundTripper) RoundTrip(req *http.Request) (*http.Response, err
 	}
 
 	supported := response.Header.Get(versionHeader)
-	if !validateVersion(supported, v.CurrentVersion) {
+	if !ValidateVersion(supported, v.CurrentVersion) {
 		fmt.Fprintf(v.Stderr, invalidVersionFormat, v.Progname, supported, v.CurrentVersion)
 	}
 
@@ -138,7 +203,7 @@ func detectClientError(err error) error {
 		case *tsuruerr.HTTP:
 			return errors.Wrapf(e, "Error received from tsuru server (%s), %d", target, e.Code)
 		case x509.UnknownAuthorityError:
-			return errors.Wrapf(e, "Failed to connect to tsuru server (%s)", target)
+			return errors.Wrapf(e, "Failed to connect to tsuru server (%s), its certificate is not trusted. Use the --ca-file flag of target-update to trust a private CA", target)
 		case *oauth2.RetrieveError:
 			return errors.Wrapf(e, "your session has expired, please run \"tsuru login\" (%s)", target)
 		}
@@ -148,9 +213,9 @@ func detectClientError(err error) error {
 	return detectErr(UnwrapErr(err))
 }
 
-// validateVersion checks whether current version is greater or equal to
+// ValidateVersion checks whether current version is greater or equal to
 // supported version.
-func validateVersion(supported, current string) bool {
+func ValidateVersion(supported, current string) bool {
 	if current == "dev" {
 		return true
 	}
@@ -178,7 +243,7 @@ func (v *TokenV1RoundTripper) RoundTrip(req *http.Request) (*http.Response, erro
 		roundTripper = defaultRoundTripper
 	}
 
-	if token, err := config.ReadTokenV1(); err == nil && token != "" {
+	if token, err := credentials.Token(); err == nil && token != "" {
 		req.Header.Set("Authorization", "bearer "+token)
 	}
 
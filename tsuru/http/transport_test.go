@@ -3,6 +3,7 @@ package http
 import (
 	"bytes"
 	"errors"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -91,3 +92,168 @@ func (s *S) TestDetectClientErrorGenericStillProbablyDown(c *check.C) {
 	c.Assert(err, check.NotNil)
 	c.Assert(strings.Contains(err.Error(), "probably down"), check.Equals, true)
 }
+
+func (s *S) TestDryRunRoundTripperRefusesMutatingRequests(c *check.C) {
+	os.Setenv("TSURU_DRY_RUN", "true")
+	defer os.Unsetenv("TSURU_DRY_RUN")
+	out := new(bytes.Buffer)
+	r := TerminalRoundTripper{
+		Stdout:         out,
+		CurrentVersion: "1.0.0",
+		RoundTripper: &cmdtest.ConditionalTransport{
+			Transport: cmdtest.Transport{Message: "Success!", Status: http.StatusOK},
+			CondFunc: func(req *http.Request) bool {
+				c.Fatalf("request should not be sent: %s %s", req.Method, req.URL)
+				return false
+			},
+		},
+	}
+	req, err := http.NewRequest(http.MethodPost, "http://localhost/apps/myapp/env", strings.NewReader("Envs.0.Name=FOO&Envs.0.Value=bar&NoRestart=false"))
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	_, err = r.RoundTrip(req)
+	c.Assert(err, check.Equals, ErrDryRun)
+	c.Assert(out.String(), check.Equals, "[dry-run] POST http://localhost/apps/myapp/env\n"+
+		"  Envs.0.Name=FOO\n"+
+		"  Envs.0.Value=bar\n"+
+		"  NoRestart=false\n")
+
+	out.Reset()
+	req, err = http.NewRequest(http.MethodPut, "http://localhost/apps/myapp", strings.NewReader(`{"plan":"c1m1"}`))
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/json")
+	_, err = r.RoundTrip(req)
+	c.Assert(err, check.Equals, ErrDryRun)
+	c.Assert(out.String(), check.Equals, "[dry-run] PUT http://localhost/apps/myapp\n"+
+		"  {\n"+
+		"    \"plan\": \"c1m1\"\n"+
+		"  }\n")
+}
+
+func (s *S) TestProtectedTargetRoundTripperConfirmsOnce(c *check.C) {
+	var sent []string
+	stderr := new(bytes.Buffer)
+	r := TerminalRoundTripper{
+		Stdin:           strings.NewReader("y\n"),
+		Stdout:          new(bytes.Buffer),
+		Stderr:          stderr,
+		CurrentVersion:  "1.0.0",
+		ProtectedTarget: "prod",
+		RoundTripper: &cmdtest.ConditionalTransport{
+			Transport: cmdtest.Transport{Message: "Success!", Status: http.StatusOK},
+			CondFunc: func(req *http.Request) bool {
+				sent = append(sent, req.Method+" "+req.URL.Path)
+				return true
+			},
+		},
+	}
+	req, err := http.NewRequest(http.MethodGet, "http://localhost/apps", nil)
+	c.Assert(err, check.IsNil)
+	_, err = r.RoundTrip(req)
+	c.Assert(err, check.IsNil)
+	c.Assert(stderr.String(), check.Equals, "")
+	for _, path := range []string{"/apps/myapp/restart", "/apps/myapp/stop"} {
+		req, err = http.NewRequest(http.MethodPost, "http://localhost"+path, nil)
+		c.Assert(err, check.IsNil)
+		_, err = r.RoundTrip(req)
+		c.Assert(err, check.IsNil)
+	}
+	c.Assert(sent, check.DeepEquals, []string{"GET /apps", "POST /apps/myapp/restart", "POST /apps/myapp/stop"})
+	c.Assert(stderr.String(), check.Equals, "Target \"prod\" is protected, you're about to send POST /apps/myapp/restart.\nContinue? (y/n) ")
+}
+
+func (s *S) TestProtectedTargetRoundTripperRefusesUnconfirmedChanges(c *check.C) {
+	stderr := new(bytes.Buffer)
+	r := TerminalRoundTripper{
+		Stdin:           strings.NewReader("n\n"),
+		Stdout:          new(bytes.Buffer),
+		Stderr:          stderr,
+		CurrentVersion:  "1.0.0",
+		ProtectedTarget: "prod",
+		RoundTripper: &cmdtest.ConditionalTransport{
+			Transport: cmdtest.Transport{Message: "Success!", Status: http.StatusOK},
+			CondFunc: func(req *http.Request) bool {
+				c.Fatalf("request should not be sent: %s %s", req.Method, req.URL)
+				return false
+			},
+		},
+	}
+	req, err := http.NewRequest(http.MethodDelete, "http://localhost/apps/myapp", nil)
+	c.Assert(err, check.IsNil)
+	_, err = r.RoundTrip(req)
+	c.Assert(err, check.Equals, ErrProtectedTarget)
+	c.Assert(stderr.String(), check.Matches, `(?s).*Continue\? \(y/n\) Abort.\n`)
+	req, err = http.NewRequest(http.MethodPut, "http://localhost/apps/myapp", nil)
+	c.Assert(err, check.IsNil)
+	_, err = r.RoundTrip(req)
+	c.Assert(err, check.Equals, ErrProtectedTarget)
+}
+
+func (s *S) TestProtectedTargetRoundTripperConfirmedByEnv(c *check.C) {
+	os.Setenv("TSURU_CONFIRM_PROTECTED", "true")
+	defer os.Unsetenv("TSURU_CONFIRM_PROTECTED")
+	stderr := new(bytes.Buffer)
+	r := TerminalRoundTripper{
+		Stdin:           strings.NewReader("n\n"),
+		Stdout:          new(bytes.Buffer),
+		Stderr:          stderr,
+		CurrentVersion:  "1.0.0",
+		ProtectedTarget: "prod",
+		RoundTripper:    &cmdtest.Transport{Message: "Success!", Status: http.StatusOK},
+	}
+	req, err := http.NewRequest(http.MethodPost, "http://localhost/apps/myapp/restart", nil)
+	c.Assert(err, check.IsNil)
+	_, err = r.RoundTrip(req)
+	c.Assert(err, check.IsNil)
+	c.Assert(stderr.String(), check.Equals, "")
+}
+
+func (s *S) TestProtectedTargetRoundTripperDoesNotReadNonTerminalStdin(c *check.C) {
+	stdin, w, err := os.Pipe()
+	c.Assert(err, check.IsNil)
+	defer stdin.Close()
+	_, err = w.WriteString("y\n")
+	c.Assert(err, check.IsNil)
+	w.Close()
+	stderr := new(bytes.Buffer)
+	r := TerminalRoundTripper{
+		Stdin:           stdin,
+		Stdout:          new(bytes.Buffer),
+		Stderr:          stderr,
+		CurrentVersion:  "1.0.0",
+		ProtectedTarget: "prod",
+		RoundTripper: &cmdtest.ConditionalTransport{
+			Transport: cmdtest.Transport{Message: "Success!", Status: http.StatusOK},
+			CondFunc: func(req *http.Request) bool {
+				c.Fatalf("request should not be sent: %s %s", req.Method, req.URL)
+				return false
+			},
+		},
+	}
+	req, err := http.NewRequest(http.MethodPost, "http://localhost/apps/myapp/restart", nil)
+	c.Assert(err, check.IsNil)
+	_, err = r.RoundTrip(req)
+	c.Assert(errors.Is(err, ErrProtectedTarget), check.Equals, true)
+	c.Assert(err, check.ErrorMatches, `target "prod" is protected and stdin is not a terminal, use --confirm-protected or set TSURU_CONFIRM_PROTECTED=true to confirm the change: .*`)
+	c.Assert(stderr.String(), check.Equals, "")
+	data, err := io.ReadAll(stdin)
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "y\n")
+}
+
+func (s *S) TestDryRunRoundTripperSendsGetRequests(c *check.C) {
+	os.Setenv("TSURU_DRY_RUN", "true")
+	defer os.Unsetenv("TSURU_DRY_RUN")
+	out := new(bytes.Buffer)
+	r := TerminalRoundTripper{
+		Stdout:         out,
+		CurrentVersion: "1.0.0",
+		RoundTripper:   &cmdtest.Transport{Message: "Success!", Status: http.StatusOK},
+	}
+	req, err := http.NewRequest(http.MethodGet, "http://localhost/apps", nil)
+	c.Assert(err, check.IsNil)
+	resp, err := r.RoundTrip(req)
+	c.Assert(err, check.IsNil)
+	c.Assert(resp.StatusCode, check.Equals, http.StatusOK)
+	c.Assert(out.String(), check.Equals, "")
+}
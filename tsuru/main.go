@@ -10,10 +10,11 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"sort"
+	"strconv"
 
 	"github.com/cezarsa/form"
 	"github.com/pkg/errors"
-	goTsuruClient "github.com/tsuru/go-tsuruclient/pkg/client"
 	"github.com/tsuru/go-tsuruclient/pkg/config"
 
 	"github.com/tsuru/tsuru-client/tsuru/admin"
@@ -22,8 +23,12 @@ import (
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
 	"github.com/tsuru/tsuru-client/tsuru/cmd/completions"
 	"github.com/tsuru/tsuru-client/tsuru/cmd/standards"
+	v2 "github.com/tsuru/tsuru-client/tsuru/cmd/v2"
 	"github.com/tsuru/tsuru-client/tsuru/config/selfupdater"
+	"github.com/tsuru/tsuru-client/tsuru/credentials"
 	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
+	"github.com/tsuru/tsuru-client/tsuru/project"
+	"github.com/tsuru/tsuru-client/tsuru/targets"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
 	"golang.org/x/oauth2"
 )
@@ -34,7 +39,14 @@ const targetTopic = `Target is used to manage the address of the remote tsuru se
 
 Each target is identified by a label and a HTTP/HTTPS address. The client
 requires at least one target to connect to, there's no default target. A user
-may have multiple targets, but only one will be used at a time.`
+may have multiple targets, but only one will be used at a time.
+
+Targets are kept in ~/.tsuru/targets.yaml, along with their description,
+default team and pool, color and whether they are protected. Any change sent
+to a protected target must be confirmed first, even when the command is run
+with -y/--assume-yes. The confirmation is asked in the terminal, or given
+beforehand with the global --confirm-protected flag or the
+TSURU_CONFIRM_PROTECTED=true environment variable.`
 
 func buildManager(stdout, stderr io.Writer) *cmd.ManagerV2 {
 	form.DefaultEncoder = form.DefaultEncoder.UseJSONTags(false)
@@ -80,9 +92,12 @@ func buildManager(stdout, stderr io.Writer) *cmd.ManagerV2 {
 
 	m := cmd.NewManagerV2(&cmd.ManagerV2Opts{
 		AfterFlagParseHook: func() {
+			applyProjectContext(stderr)
+			credentials.SetHelper(v2.CredentialHelper())
 			initAuthorization()
 		},
 		RetryHook: retryHook,
+		ErrorHook: dryRunErrorHook,
 	})
 
 	m.SetFlagCompletions(map[string]cmd.CompletionFunc{
@@ -99,10 +114,19 @@ func buildManager(stdout, stderr io.Writer) *cmd.ManagerV2 {
 
 	m.Register(&auth.Login{})
 	m.Register(&auth.Logout{})
+	m.RegisterTopic("auth", `Auth is used to inspect the session used on the current target.`)
+	m.Register(&auth.AuthStatus{})
 	m.Register(&versionCmd{})
 
+	m.RegisterTopic("config", `Config is used to inspect and change the client settings stored in ~/.tsuru/client.yaml.`)
+	m.Register(&client.ConfigList{})
+	m.Register(&client.ConfigGet{})
+	m.Register(&client.ConfigSet{})
+	m.Register(&client.ConfigUnset{})
+	m.Register(&client.ConfigEdit{})
+
 	m.RegisterTopic("target", targetTopic)
-	m.Register(&client.TargetList{})
+	m.Register(&client.TargetList{ClientVersion: version})
 	m.Register(&client.TargetAdd{})
 	m.Register(&client.TargetRemove{})
 	m.Register(&client.TargetSet{})
@@ -113,6 +137,8 @@ func buildManager(stdout, stderr io.Writer) *cmd.ManagerV2 {
 	m.Register(&client.AppCreate{})
 	m.Register(&client.AppRemove{})
 	m.Register(&client.AppUpdate{})
+	m.Register(&client.AppApply{})
+	m.Register(&client.AppExport{})
 
 	m.RegisterTopic("app-process", `An application process represents a command that runs as part of the application.`)
 	m.Register(&client.AppProcessUpdate{})
@@ -384,6 +410,9 @@ Services aren’t managed by tsuru, but by their creators.`)
 	m.RegisterHiddenShorthand(&client.AppStart{}, "start")
 	m.RegisterHiddenShorthand(&client.AppStop{}, "stop")
 
+	// aliases are registered last, so they never shadow other commands
+	registerAliases(m, stderr)
+
 	return m
 }
 
@@ -403,20 +432,97 @@ func main() {
 	err = m.Run()
 }
 
+// dryRunErrorHook turns the refusal of a mutating request in dry-run mode into
+// a successful run.
+func dryRunErrorHook(ctx *cmd.Context, err error) error {
+	if errors.Is(err, tsuruHTTP.ErrDryRun) {
+		fmt.Fprintln(ctx.Stderr, "Dry-run mode: no changes were made.")
+		return nil
+	}
+	return err
+}
+
+// registerAliases registers the aliases in ~/.tsuru/client.yaml, warning
+// about the ones that can't be used.
+func registerAliases(m *cmd.ManagerV2, stderr io.Writer) {
+	aliases, err := v2.Aliases()
+	if err != nil {
+		fmt.Fprintf(stderr, "Warning: unable to read aliases: %s\n", err.Error())
+		return
+	}
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err = m.RegisterAlias(name, aliases[name]); err != nil {
+			fmt.Fprintf(stderr, "Warning: %s, ignoring it\n", err.Error())
+		}
+	}
+}
+
+// applyProjectContext makes the target in the project context file the
+// current one, unless another target is given in the --target flag or in the
+// TSURU_TARGET environment variable. The context is printed from verbosity
+// level 1 onwards.
+func applyProjectContext(stderr io.Writer) {
+	projectCtx, err := project.Current()
+	if err != nil {
+		fmt.Fprintf(stderr, "Warning: %s\n", err.Error())
+		return
+	}
+	if projectCtx == nil {
+		return
+	}
+	targetOverridden := os.Getenv("TSURU_TARGET") != ""
+	if projectCtx.Target != "" && !targetOverridden {
+		os.Setenv("TSURU_TARGET", projectCtx.Target)
+	}
+	if verbosity, _ := strconv.Atoi(os.Getenv("TSURU_VERBOSITY")); verbosity > 0 {
+		projectCtx.Print(stderr, targetOverridden)
+	}
+}
+
+// protectedTarget returns the label of the current target when it's
+// protected, or an empty string otherwise.
+func protectedTarget() string {
+	key, err := credentials.CurrentKey()
+	if err != nil {
+		return ""
+	}
+	target, err := targets.Get(key)
+	if err != nil || target == nil || !target.Protected {
+		return ""
+	}
+	return target.Label
+}
+
 func initAuthorization() {
 	name := cmd.ExtractProgramName(os.Args[0])
-	roundTripper, tokenProvider, err := goTsuruClient.RoundTripperAndTokenProvider()
+	transport, err := tsuruHTTP.TargetTransport()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s, using the default TLS settings\n", err.Error())
+		transport = http.DefaultTransport
+	}
+	tsuruHTTP.UnauthenticatedClient = &http.Client{
+		Transport: &tsuruHTTP.DryRunRoundTripper{RoundTripper: transport, Stdout: os.Stdout},
+	}
+
+	roundTripper, tokenProvider, err := tsuruHTTP.RoundTripperAndTokenProvider(transport)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Could not read token V2: %q\n", err.Error())
 		os.Exit(1)
 	}
 
 	tsuruHTTP.AuthenticatedClient = tsuruHTTP.NewTerminalClient(tsuruHTTP.TerminalClientOptions{
-		RoundTripper:  roundTripper,
-		ClientName:    name,
-		ClientVersion: version,
-		Stdout:        os.Stdout,
-		Stderr:        os.Stderr,
+		RoundTripper:    roundTripper,
+		ClientName:      name,
+		ClientVersion:   version,
+		Stdin:           os.Stdin,
+		Stdout:          os.Stdout,
+		Stderr:          os.Stderr,
+		ProtectedTarget: protectedTarget(),
 	})
 	config.DefaultTokenProvider = tokenProvider
 }
@@ -6,28 +6,46 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"gopkg.in/check.v1"
 
+	"github.com/tsuru/go-tsuruclient/pkg/config"
 	"github.com/tsuru/tsuru-client/tsuru/cmd"
+	"github.com/tsuru/tsuru-client/tsuru/cmd/cmdtest"
+	v2 "github.com/tsuru/tsuru-client/tsuru/cmd/v2"
 	tsuruHTTP "github.com/tsuru/tsuru-client/tsuru/http"
+	"github.com/tsuru/tsuru-client/tsuru/targets"
+	"github.com/tsuru/tsuru/fs/fstest"
 )
 
-type S struct{}
+type S struct {
+	home      string
+	configDir string
+}
 
 func (s *S) SetUpSuite(c *check.C) {
 	os.Setenv("TSURU_TARGET", "http://localhost:8080")
 	os.Setenv("TSURU_TOKEN", "sometoken")
+	// buildManager reads the aliases and settings of the user, the tests
+	// use an empty home and configuration directory instead.
+	s.home = os.Getenv("HOME")
+	os.Setenv("HOME", c.MkDir())
+	s.configDir = v2.TsuruConfigDir
+	v2.TsuruConfigDir = c.MkDir()
 }
 
 func (s *S) TearDownSuite(c *check.C) {
 	os.Unsetenv("TSURU_TARGET")
 	os.Unsetenv("TSURU_TOKEN")
+	os.Setenv("HOME", s.home)
+	v2.TsuruConfigDir = s.configDir
 }
 
 var _ = check.Suite(&S{})
@@ -170,3 +188,143 @@ func (s *S) TestVersionAPIInvalidURL(c *check.C) {
 
 	c.Assert(stdout, check.Matches, "Client version: dev.\n")
 }
+
+func runWithFakeServer(c *check.C, srv *cmdtest.FakeServer, args ...string) (string, error) {
+	os.Setenv("TSURU_TARGET", srv.URL)
+	defer os.Setenv("TSURU_TARGET", "http://localhost:8080")
+	var stdout bytes.Buffer
+	m := buildManager(&stdout, &stdout)
+	m.Cobra().SetArgs(args)
+	m.Cobra().SetOut(&stdout)
+	m.Cobra().SetErr(&stdout)
+	err := m.Run()
+	return stdout.String(), err
+}
+
+func (s *S) TestEndToEndWithFakeServer(c *check.C) {
+	srv := cmdtest.NewFakeServer()
+	defer srv.Close()
+	srv.Token = "sometoken"
+
+	out, err := runWithFakeServer(c, srv, "app-create", "myapp", "python")
+	c.Assert(err, check.IsNil)
+	c.Assert(out, check.Matches, `(?s)App "myapp" has been created!.*`)
+	app, ok := srv.App("myapp")
+	c.Assert(ok, check.Equals, true)
+	c.Assert(app.Platform, check.Equals, "python")
+	c.Assert(app.Pool, check.Equals, "default")
+	c.Assert(app.TeamOwner, check.Equals, "admin")
+
+	_, err = runWithFakeServer(c, srv, "env-set", "-a", "myapp", "DEBUG=1", "--no-restart")
+	c.Assert(err, check.IsNil)
+	out, err = runWithFakeServer(c, srv, "env-get", "-a", "myapp")
+	c.Assert(err, check.IsNil)
+	c.Assert(out, check.Equals, "DEBUG=1\n")
+
+	dir := c.MkDir()
+	err = os.WriteFile(filepath.Join(dir, "app.py"), []byte("print('hello')\n"), 0644)
+	c.Assert(err, check.IsNil)
+	wd, err := os.Getwd()
+	c.Assert(err, check.IsNil)
+	defer os.Chdir(wd)
+	c.Assert(os.Chdir(dir), check.IsNil)
+	out, err = runWithFakeServer(c, srv, "app-deploy", "-a", "myapp", ".")
+	c.Assert(err, check.IsNil)
+	c.Assert(out, check.Matches, `(?s).*Deploying app "myapp".*OK.*`)
+	deploys := srv.Deploys()
+	c.Assert(deploys, check.HasLen, 1)
+	c.Assert(deploys[0].Origin, check.Equals, "app-deploy")
+	c.Assert(len(deploys[0].Archive) > 0, check.Equals, true)
+
+	out, err = runWithFakeServer(c, srv, "app-list", "--output", "name")
+	c.Assert(err, check.IsNil)
+	c.Assert(out, check.Equals, "myapp\n")
+
+	out, err = runWithFakeServer(c, srv, "event-list", "--kind", "app.deploy", "--output", "csv")
+	c.Assert(err, check.IsNil)
+	c.Assert(out, check.Matches, `(?s)id,.*\n[0-9a-f]{24},.*,app.deploy,app,myapp\n`)
+}
+
+func (s *S) TestListOutputShorthand(c *check.C) {
+	srv := cmdtest.NewFakeServer()
+	defer srv.Close()
+	srv.Token = "sometoken"
+
+	_, err := runWithFakeServer(c, srv, "app-create", "myapp", "python")
+	c.Assert(err, check.IsNil)
+
+	out, err := runWithFakeServer(c, srv, "app-list", "-o", "json")
+	c.Assert(err, check.IsNil)
+	var apps []map[string]interface{}
+	c.Assert(json.Unmarshal([]byte(out), &apps), check.IsNil, check.Commentf("output: %s", out))
+	c.Assert(apps, check.HasLen, 1)
+	c.Assert(apps[0]["name"], check.Equals, "myapp")
+
+	out, err = runWithFakeServer(c, srv, "app-list", "--pool", "default", "-o", "name")
+	c.Assert(err, check.IsNil)
+	c.Assert(out, check.Equals, "myapp\n")
+
+	_, err = runWithFakeServer(c, srv, "app-list", "-o", "default")
+	c.Assert(err, check.ErrorMatches, `unknown output format "default".*use --pool or --owner\)`)
+}
+
+func (s *S) TestFakeServerRequiresToken(c *check.C) {
+	srv := cmdtest.NewFakeServer()
+	defer srv.Close()
+	srv.Token = "othertoken"
+	os.Setenv("TSURU_TOKEN", "sometoken")
+	_, err := runWithFakeServer(c, srv, "pool-list")
+	c.Assert(err, check.ErrorMatches, `(?s).*401.*`)
+}
+
+func (s *S) TestApplyProjectContext(c *check.C) {
+	wd, err := os.Getwd()
+	c.Assert(err, check.IsNil)
+	defer os.Chdir(wd)
+	defer os.Setenv("TSURU_TARGET", os.Getenv("TSURU_TARGET"))
+	root := c.MkDir()
+	err = os.WriteFile(filepath.Join(root, ".tsuru.yaml"), []byte("target: https://staging.tsuru.io\napp: myapp\n"), 0600)
+	c.Assert(err, check.IsNil)
+	c.Assert(os.Chdir(root), check.IsNil)
+	os.Unsetenv("TSURU_TARGET")
+	var stderr bytes.Buffer
+	applyProjectContext(&stderr)
+	c.Assert(os.Getenv("TSURU_TARGET"), check.Equals, "https://staging.tsuru.io")
+	c.Assert(stderr.String(), check.Equals, "")
+}
+
+func (s *S) TestApplyProjectContextExplicitTargetWins(c *check.C) {
+	wd, err := os.Getwd()
+	c.Assert(err, check.IsNil)
+	defer os.Chdir(wd)
+	defer os.Setenv("TSURU_TARGET", os.Getenv("TSURU_TARGET"))
+	root := c.MkDir()
+	err = os.WriteFile(filepath.Join(root, ".tsuru.yaml"), []byte("target: https://staging.tsuru.io\n"), 0600)
+	c.Assert(err, check.IsNil)
+	c.Assert(os.Chdir(root), check.IsNil)
+	os.Setenv("TSURU_TARGET", "http://other.tsuru.io")
+	os.Setenv("TSURU_VERBOSITY", "1")
+	defer os.Unsetenv("TSURU_VERBOSITY")
+	var stderr bytes.Buffer
+	applyProjectContext(&stderr)
+	c.Assert(os.Getenv("TSURU_TARGET"), check.Equals, "http://other.tsuru.io")
+	c.Assert(stderr.String(), check.Matches, `(?s).*<Project context path=".*\.tsuru\.yaml">.*Target: +https://staging\.tsuru\.io \(overridden by --target or TSURU_TARGET\).*`)
+}
+
+func (s *S) TestProtectedTarget(c *check.C) {
+	config.SetFileSystem(&fstest.RecordingFs{})
+	defer config.ResetFileSystem()
+	defer os.Setenv("TSURU_TARGET", os.Getenv("TSURU_TARGET"))
+	err := targets.Update(func(list map[string]targets.Target) error {
+		list["prod"] = targets.Target{URL: "https://tsuru.example.com", Protected: true}
+		list["dev"] = targets.Target{URL: "http://localhost:8080"}
+		return nil
+	})
+	c.Assert(err, check.IsNil)
+	os.Setenv("TSURU_TARGET", "prod")
+	c.Assert(protectedTarget(), check.Equals, "prod")
+	os.Setenv("TSURU_TARGET", "dev")
+	c.Assert(protectedTarget(), check.Equals, "")
+	os.Setenv("TSURU_TARGET", "https://unknown.example.com")
+	c.Assert(protectedTarget(), check.Equals, "")
+}
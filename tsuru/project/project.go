@@ -0,0 +1,141 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package project reads the context file of the project the client is run
+// from. The file, named .tsuru.yaml, is looked up from the working directory
+// up to the root of the filesystem and supplies the target, app or job and
+// deploy paths used when they are not given on the command line:
+//
+//	target: staging
+//	app: myapp
+//	deploy:
+//	  paths:
+//	    - src/
+//	    - Procfile
+package project
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// FileName is the name of the project context file.
+const FileName = ".tsuru.yaml"
+
+// Context holds the defaults set in a project context file.
+type Context struct {
+	// Path is the file the context was read from.
+	Path string `json:"-"`
+
+	Target string `json:"target,omitempty"`
+	App    string `json:"app,omitempty"`
+	Job    string `json:"job,omitempty"`
+	Deploy Deploy `json:"deploy,omitempty"`
+}
+
+// Deploy holds the deploy defaults of a project.
+type Deploy struct {
+	// Paths are the files and directories deployed when none is given,
+	// relative to the directory of the context file.
+	Paths []string `json:"paths,omitempty"`
+}
+
+// Current returns the context of the project holding the working directory,
+// or nil when there's none.
+func Current() (*Context, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return Find(wd)
+}
+
+// Find returns the context in the closest context file found walking up from
+// dir, or nil when there's none.
+func Find(dir string) (*Context, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		path := filepath.Join(dir, FileName)
+		if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+			return Load(path)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// Load reads the context file in path.
+func Load(path string) (*Context, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var ctx Context
+	if err = yaml.Unmarshal(data, &ctx); err != nil {
+		return nil, fmt.Errorf("invalid project context %s: %w", path, err)
+	}
+	ctx.Path = path
+	return &ctx, nil
+}
+
+// Dir returns the directory of the context file.
+func (c *Context) Dir() string {
+	return filepath.Dir(c.Path)
+}
+
+// DeployPaths returns the default deploy paths relative to the working
+// directory, the way they are given to the archiver.
+func (c *Context) DeployPaths() ([]string, error) {
+	if len(c.Deploy.Paths) == 0 {
+		return nil, nil
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(c.Deploy.Paths))
+	for i, path := range c.Deploy.Paths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(c.Dir(), path)
+		}
+		if paths[i], err = filepath.Rel(wd, path); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+// Print writes the context in a human readable form, as shown in verbose
+// output. targetOverridden tells whether the target was given explicitly,
+// taking precedence over the one in the context.
+func (c *Context) Print(w io.Writer, targetOverridden bool) {
+	fmt.Fprintf(w, "*************************** <Project context path=%q> **********************************\n", c.Path)
+	target := c.Target
+	if target != "" && targetOverridden {
+		target += " (overridden by --target or TSURU_TARGET)"
+	}
+	settings := []struct{ name, value string }{
+		{"Target", target},
+		{"App", c.App},
+		{"Job", c.Job},
+		{"Deploy paths", strings.Join(c.Deploy.Paths, ", ")},
+	}
+	for _, s := range settings {
+		if s.value != "" {
+			fmt.Fprintf(w, "%-14s%s\n", s.name+":", s.value)
+		}
+	}
+	fmt.Fprintf(w, "*************************** </Project context path=%q> **********************************\n", c.Path)
+}
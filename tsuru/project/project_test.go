@@ -0,0 +1,131 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	check "gopkg.in/check.v1"
+)
+
+type S struct {
+	wd string
+}
+
+var _ = check.Suite(&S{})
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+func (s *S) SetUpTest(c *check.C) {
+	var err error
+	s.wd, err = os.Getwd()
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TearDownTest(c *check.C) {
+	os.Chdir(s.wd)
+}
+
+func writeContext(c *check.C, dir, content string) string {
+	path := filepath.Join(dir, FileName)
+	err := os.WriteFile(path, []byte(content), 0600)
+	c.Assert(err, check.IsNil)
+	return path
+}
+
+func (s *S) TestFindWalksUp(c *check.C) {
+	root := c.MkDir()
+	path := writeContext(c, root, "target: staging\napp: myapp\ndeploy:\n  paths:\n    - src/\n    - Procfile\n")
+	dir := filepath.Join(root, "src", "pkg")
+	c.Assert(os.MkdirAll(dir, 0700), check.IsNil)
+	ctx, err := Find(dir)
+	c.Assert(err, check.IsNil)
+	c.Assert(ctx, check.DeepEquals, &Context{
+		Path:   path,
+		Target: "staging",
+		App:    "myapp",
+		Deploy: Deploy{Paths: []string{"src/", "Procfile"}},
+	})
+}
+
+func (s *S) TestFindPrefersClosestFile(c *check.C) {
+	root := c.MkDir()
+	writeContext(c, root, "app: parent\n")
+	dir := filepath.Join(root, "child")
+	c.Assert(os.Mkdir(dir, 0700), check.IsNil)
+	writeContext(c, dir, "job: child\n")
+	ctx, err := Find(dir)
+	c.Assert(err, check.IsNil)
+	c.Assert(ctx.App, check.Equals, "")
+	c.Assert(ctx.Job, check.Equals, "child")
+}
+
+func (s *S) TestFindWithoutContext(c *check.C) {
+	ctx, err := Find(c.MkDir())
+	c.Assert(err, check.IsNil)
+	c.Assert(ctx, check.IsNil)
+}
+
+func (s *S) TestFindInvalidContext(c *check.C) {
+	root := c.MkDir()
+	path := writeContext(c, root, "app: [myapp\n")
+	ctx, err := Find(root)
+	c.Assert(ctx, check.IsNil)
+	c.Assert(err, check.ErrorMatches, "invalid project context "+path+": .*")
+}
+
+func (s *S) TestCurrent(c *check.C) {
+	root := c.MkDir()
+	writeContext(c, root, "app: myapp\n")
+	c.Assert(os.Chdir(root), check.IsNil)
+	ctx, err := Current()
+	c.Assert(err, check.IsNil)
+	c.Assert(ctx.App, check.Equals, "myapp")
+}
+
+func (s *S) TestDeployPathsRelativeToWorkingDirectory(c *check.C) {
+	root := c.MkDir()
+	ctx := &Context{
+		Path:   filepath.Join(root, FileName),
+		Deploy: Deploy{Paths: []string{".", "src/main.go"}},
+	}
+	c.Assert(os.Chdir(root), check.IsNil)
+	paths, err := ctx.DeployPaths()
+	c.Assert(err, check.IsNil)
+	c.Assert(paths, check.DeepEquals, []string{".", filepath.Join("src", "main.go")})
+	dir := filepath.Join(root, "src")
+	c.Assert(os.Mkdir(dir, 0700), check.IsNil)
+	c.Assert(os.Chdir(dir), check.IsNil)
+	paths, err = ctx.DeployPaths()
+	c.Assert(err, check.IsNil)
+	c.Assert(paths, check.DeepEquals, []string{"..", "main.go"})
+}
+
+func (s *S) TestDeployPathsEmpty(c *check.C) {
+	ctx := &Context{Path: filepath.Join(c.MkDir(), FileName)}
+	paths, err := ctx.DeployPaths()
+	c.Assert(err, check.IsNil)
+	c.Assert(paths, check.IsNil)
+}
+
+func (s *S) TestPrint(c *check.C) {
+	ctx := &Context{
+		Path:   "/home/me/myapp/.tsuru.yaml",
+		Target: "staging",
+		App:    "myapp",
+		Deploy: Deploy{Paths: []string{"src/", "Procfile"}},
+	}
+	var buf bytes.Buffer
+	ctx.Print(&buf, true)
+	c.Assert(buf.String(), check.Equals, `*************************** <Project context path="/home/me/myapp/.tsuru.yaml"> **********************************
+Target:       staging (overridden by --target or TSURU_TARGET)
+App:          myapp
+Deploy paths: src/, Procfile
+*************************** </Project context path="/home/me/myapp/.tsuru.yaml"> **********************************
+`)
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package targets
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/tsuru/go-tsuruclient/pkg/config"
+	"github.com/tsuru/tsuru/fs"
+)
+
+var storeMutex sync.Mutex
+
+func lockPath() string {
+	return storePath() + ".lock"
+}
+
+// lock serializes the changes to the store, within the process and, on the
+// OS filesystem, across processes. It returns the function releasing the
+// lock.
+func lock() (func(), error) {
+	storeMutex.Lock()
+	switch config.Filesystem().(type) {
+	case fs.OsFs, *fs.OsFs:
+	default:
+		return storeMutex.Unlock, nil
+	}
+	path := lockPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		storeMutex.Unlock()
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		storeMutex.Unlock()
+		return nil, err
+	}
+	if err = lockFile(f); err != nil {
+		f.Close()
+		storeMutex.Unlock()
+		return nil, fmt.Errorf("unable to lock %s: %w", path, err)
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+		storeMutex.Unlock()
+	}, nil
+}
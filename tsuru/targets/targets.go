@@ -0,0 +1,292 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package targets stores the tsuru targets known by the client, along with
+// their metadata, in ~/.tsuru/targets.yaml:
+//
+//	targets:
+//	  prod:
+//	    url: https://tsuru.example.com
+//	    description: Production cluster
+//	    team: platform
+//	    pool: prod
+//	    color: red
+//	    protected: true
+//
+// The store is created from the flat lists written by older versions,
+// ~/.tsuru/targets and ~/.tsuru_targets, the first time it's read, leaving
+// them in place. The flat list in ~/.tsuru/targets is still written on every
+// change, as plugins and older versions read it. Changes are made holding a
+// lock, so concurrent clients don't overwrite each other's changes.
+package targets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/tsuru/go-tsuruclient/pkg/config"
+)
+
+// Target is a tsuru server known by the client.
+type Target struct {
+	// Label is the name of the target, used by target-set and --target.
+	Label string `json:"-"`
+
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+	// Team and Pool are used by commands creating apps and jobs when no
+	// team or pool is given.
+	Team string `json:"team,omitempty"`
+	Pool string `json:"pool,omitempty"`
+	// Color highlights the label of the target in target-list.
+	Color string `json:"color,omitempty"`
+	// Protected targets require confirmation before any change is sent to
+	// them.
+	Protected bool `json:"protected,omitempty"`
+}
+
+type storeFile struct {
+	Targets map[string]Target `json:"targets"`
+}
+
+func storePath() string {
+	return config.JoinWithUserDir(".tsuru", "targets.yaml")
+}
+
+func flatPath() string {
+	return config.JoinWithUserDir(".tsuru", "targets")
+}
+
+func legacyFlatPath() string {
+	return config.JoinWithUserDir(".tsuru_targets")
+}
+
+// List returns the targets sorted by label.
+func List() ([]Target, error) {
+	targets, err := read()
+	if err != nil {
+		return nil, err
+	}
+	return sorted(targets), nil
+}
+
+// URLs returns the URL of each target, by label.
+func URLs() (map[string]string, error) {
+	targets, err := read()
+	if err != nil {
+		return nil, err
+	}
+	urls := make(map[string]string, len(targets))
+	for label, t := range targets {
+		urls[label] = t.URL
+	}
+	return urls, nil
+}
+
+// Get returns the target labeled label, or nil when there's none.
+func Get(label string) (*Target, error) {
+	targets, err := read()
+	if err != nil {
+		return nil, err
+	}
+	t, ok := targets[label]
+	if !ok {
+		return nil, nil
+	}
+	return &t, nil
+}
+
+// FindByURL returns the first target, by label, pointing to url, or nil when
+// there's none. Trailing slashes and missing schemes are ignored.
+func FindByURL(url string) (*Target, error) {
+	list, err := List()
+	if err != nil {
+		return nil, err
+	}
+	url = NormalizeURL(url)
+	for _, t := range list {
+		if NormalizeURL(t.URL) == url {
+			return &t, nil
+		}
+	}
+	return nil, nil
+}
+
+// Update changes the targets holding the store lock. The targets given to fn
+// are keyed by label and the store is only written when fn succeeds.
+func Update(fn func(targets map[string]Target) error) error {
+	unlock, err := lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	targets, err := load()
+	if err != nil {
+		return err
+	}
+	if err = fn(targets); err != nil {
+		return err
+	}
+	return write(targets)
+}
+
+// NormalizeURL returns target with an http scheme when it has none and
+// without trailing slashes.
+func NormalizeURL(target string) string {
+	target = strings.TrimRight(strings.TrimSpace(target), "/")
+	if target != "" && !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		target = "http://" + target
+	}
+	return target
+}
+
+// read returns the targets in the store, creating it from the flat lists
+// when it doesn't exist yet.
+func read() (map[string]Target, error) {
+	targets, err := readStore()
+	if !os.IsNotExist(err) {
+		return targets, err
+	}
+	if targets, err = readFlat(); err != nil || len(targets) == 0 {
+		return targets, err
+	}
+	unlock, err := lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return load()
+}
+
+// load reads the store, migrating the flat lists when needed. It must be
+// called holding the lock. The migrated targets are returned even when the
+// store can't be written, as the flat lists are left untouched.
+func load() (map[string]Target, error) {
+	targets, err := readStore()
+	if !os.IsNotExist(err) {
+		return targets, err
+	}
+	targets, err = readFlat()
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) > 0 {
+		write(targets)
+	}
+	return targets, nil
+}
+
+func readStore() (map[string]Target, error) {
+	data, err := readFile(storePath())
+	if err != nil {
+		return nil, err
+	}
+	var content storeFile
+	if err = yaml.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("invalid target store %s: %w", storePath(), err)
+	}
+	targets := make(map[string]Target, len(content.Targets))
+	for label, t := range content.Targets {
+		t.Label = label
+		targets[label] = t
+	}
+	return targets, nil
+}
+
+// readFlat reads the "<label>\t<url>" lines of ~/.tsuru/targets or, when
+// it doesn't exist, of ~/.tsuru_targets.
+func readFlat() (map[string]Target, error) {
+	targets := map[string]Target{}
+	data, err := readFile(flatPath())
+	if os.IsNotExist(err) {
+		data, err = readFile(legacyFlatPath())
+	}
+	if os.IsNotExist(err) {
+		return targets, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 2 {
+			continue
+		}
+		label, url := strings.TrimSpace(fields[0]), strings.TrimSpace(fields[1])
+		targets[label] = Target{Label: label, URL: url}
+	}
+	return targets, nil
+}
+
+func write(targets map[string]Target) error {
+	content := storeFile{Targets: targets}
+	data, err := yaml.Marshal(content)
+	if err != nil {
+		return err
+	}
+	if err = writeFile(storePath(), data); err != nil {
+		return err
+	}
+	var flat bytes.Buffer
+	for _, t := range sorted(targets) {
+		flat.WriteString(t.Label + "\t" + t.URL + "\n")
+	}
+	return writeFile(flatPath(), flat.Bytes())
+}
+
+func sorted(targets map[string]Target) []Target {
+	list := make([]Target, 0, len(targets))
+	for label, t := range targets {
+		t.Label = label
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Label < list[j].Label })
+	return list
+}
+
+func readFile(path string) ([]byte, error) {
+	f, err := config.Filesystem().Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// writeFile replaces the content of path, writing to a temporary file first
+// so readers never see a partial file.
+func writeFile(path string, data []byte) error {
+	fsystem := config.Filesystem()
+	if err := fsystem.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmpPath := path + "." + strconv.Itoa(os.Getpid()) + ".tmp"
+	f, err := fsystem.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	n, err := f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && n != len(data) {
+		err = errors.New("short write")
+	}
+	if err == nil {
+		err = fsystem.Rename(tmpPath, path)
+	}
+	if err != nil {
+		fsystem.Remove(tmpPath)
+		return fmt.Errorf("unable to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 tsuru-client authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package targets
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	check "gopkg.in/check.v1"
+)
+
+type S struct {
+	home string
+}
+
+var _ = check.Suite(&S{})
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+func (s *S) SetUpTest(c *check.C) {
+	s.home = os.Getenv("HOME")
+	os.Setenv("HOME", c.MkDir())
+}
+
+func (s *S) TearDownTest(c *check.C) {
+	os.Setenv("HOME", s.home)
+}
+
+func writeHomeFile(c *check.C, content string, path ...string) {
+	fullPath := filepath.Join(append([]string{os.Getenv("HOME")}, path...)...)
+	c.Assert(os.MkdirAll(filepath.Dir(fullPath), 0700), check.IsNil)
+	c.Assert(os.WriteFile(fullPath, []byte(content), 0600), check.IsNil)
+}
+
+func readHomeFile(c *check.C, path ...string) string {
+	data, err := os.ReadFile(filepath.Join(append([]string{os.Getenv("HOME")}, path...)...))
+	c.Assert(err, check.IsNil)
+	return string(data)
+}
+
+func (s *S) TestListEmpty(c *check.C) {
+	list, err := List()
+	c.Assert(err, check.IsNil)
+	c.Assert(list, check.HasLen, 0)
+	_, err = os.Stat(storePath())
+	c.Assert(os.IsNotExist(err), check.Equals, true)
+}
+
+func (s *S) TestListMigratesFlatList(c *check.C) {
+	flat := "prod\thttps://tsuru.example.com\ndev\thttp://localhost:8080\ninvalid line\n"
+	writeHomeFile(c, flat, ".tsuru", "targets")
+	list, err := List()
+	c.Assert(err, check.IsNil)
+	c.Assert(list, check.DeepEquals, []Target{
+		{Label: "dev", URL: "http://localhost:8080"},
+		{Label: "prod", URL: "https://tsuru.example.com"},
+	})
+	c.Assert(readHomeFile(c, ".tsuru", "targets.yaml"), check.Equals, `targets:
+  dev:
+    url: http://localhost:8080
+  prod:
+    url: https://tsuru.example.com
+`)
+}
+
+func (s *S) TestListMigratesLegacyFlatList(c *check.C) {
+	writeHomeFile(c, "prod\thttps://tsuru.example.com\n", ".tsuru_targets")
+	urls, err := URLs()
+	c.Assert(err, check.IsNil)
+	c.Assert(urls, check.DeepEquals, map[string]string{"prod": "https://tsuru.example.com"})
+	c.Assert(readHomeFile(c, ".tsuru", "targets"), check.Equals, "prod\thttps://tsuru.example.com\n")
+	c.Assert(readHomeFile(c, ".tsuru_targets"), check.Equals, "prod\thttps://tsuru.example.com\n")
+}
+
+func (s *S) TestListStoreTakesPrecedence(c *check.C) {
+	writeHomeFile(c, "old\thttp://old.example.com\n", ".tsuru", "targets")
+	writeHomeFile(c, `targets:
+  prod:
+    url: https://tsuru.example.com
+    description: Production
+    team: platform
+    pool: prod-pool
+    color: red
+    protected: true
+`, ".tsuru", "targets.yaml")
+	list, err := List()
+	c.Assert(err, check.IsNil)
+	c.Assert(list, check.DeepEquals, []Target{{
+		Label:       "prod",
+		URL:         "https://tsuru.example.com",
+		Description: "Production",
+		Team:        "platform",
+		Pool:        "prod-pool",
+		Color:       "red",
+		Protected:   true,
+	}})
+}
+
+func (s *S) TestListInvalidStore(c *check.C) {
+	writeHomeFile(c, "targets: [", ".tsuru", "targets.yaml")
+	_, err := List()
+	c.Assert(err, check.ErrorMatches, `invalid target store .*targets.yaml: .*`)
+}
+
+func (s *S) TestGet(c *check.C) {
+	writeHomeFile(c, "targets:\n  prod:\n    url: https://tsuru.example.com\n    protected: true\n", ".tsuru", "targets.yaml")
+	target, err := Get("prod")
+	c.Assert(err, check.IsNil)
+	c.Assert(target, check.DeepEquals, &Target{Label: "prod", URL: "https://tsuru.example.com", Protected: true})
+	target, err = Get("dev")
+	c.Assert(err, check.IsNil)
+	c.Assert(target, check.IsNil)
+}
+
+func (s *S) TestFindByURL(c *check.C) {
+	writeHomeFile(c, "targets:\n  b:\n    url: tsuru.example.com/\n  a:\n    url: http://tsuru.example.com\n", ".tsuru", "targets.yaml")
+	target, err := FindByURL("http://tsuru.example.com/")
+	c.Assert(err, check.IsNil)
+	c.Assert(target.Label, check.Equals, "a")
+	target, err = FindByURL("https://tsuru.example.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(target, check.IsNil)
+}
+
+func (s *S) TestUpdate(c *check.C) {
+	writeHomeFile(c, "dev\thttp://localhost:8080\n", ".tsuru", "targets")
+	err := Update(func(targets map[string]Target) error {
+		targets["prod"] = Target{URL: "https://tsuru.example.com", Protected: true}
+		return nil
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(readHomeFile(c, ".tsuru", "targets"), check.Equals, "dev\thttp://localhost:8080\nprod\thttps://tsuru.example.com\n")
+	target, err := Get("prod")
+	c.Assert(err, check.IsNil)
+	c.Assert(target, check.DeepEquals, &Target{Label: "prod", URL: "https://tsuru.example.com", Protected: true})
+}
+
+func (s *S) TestUpdateFailureKeepsStore(c *check.C) {
+	writeHomeFile(c, "targets:\n  dev:\n    url: http://localhost:8080\n", ".tsuru", "targets.yaml")
+	err := Update(func(targets map[string]Target) error {
+		delete(targets, "dev")
+		return errors.New("something went wrong")
+	})
+	c.Assert(err, check.ErrorMatches, "something went wrong")
+	c.Assert(readHomeFile(c, ".tsuru", "targets.yaml"), check.Equals, "targets:\n  dev:\n    url: http://localhost:8080\n")
+}
+
+func (s *S) TestUpdateConcurrent(c *check.C) {
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			label := fmt.Sprintf("target%02d", i)
+			err := Update(func(targets map[string]Target) error {
+				targets[label] = Target{URL: "http://" + label + ".example.com"}
+				return nil
+			})
+			c.Check(err, check.IsNil)
+		}(i)
+	}
+	wg.Wait()
+	list, err := List()
+	c.Assert(err, check.IsNil)
+	c.Assert(list, check.HasLen, 20)
+}
+
+func (s *S) TestNormalizeURL(c *check.C) {
+	c.Assert(NormalizeURL(" tsuru.example.com/ "), check.Equals, "http://tsuru.example.com")
+	c.Assert(NormalizeURL("https://tsuru.example.com//"), check.Equals, "https://tsuru.example.com")
+	c.Assert(NormalizeURL(""), check.Equals, "")
+}